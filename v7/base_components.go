@@ -19,6 +19,7 @@ const (
 	STRINGMAP
 	ITEM
 	INVENTORY
+	PATH
 )
 
 // ComponentIDs
@@ -38,4 +39,10 @@ const (
 	INVENTORY_
 	STATE_
 	GENERICTAGS_
+	PATH_
+	FLOWFIELD_
+	STEERWEIGHTS_
+	STEERPARAMS_
+	STEERTARGETENTITY_
+	RVO_
 )
@@ -64,6 +64,8 @@ func (bb *Blackboard) UnmarshalJSON(data []byte) error {
 
 	bb.Name = aux.Name
 	bb.State = make(map[string]any)
+	// events aren't saved, but the bus is needed
+	bb.Events = NewEventBus("blackboard-" + bb.Name)
 
 	for key, value := range aux.State {
 		switch v := value.(type) {
@@ -50,6 +50,18 @@ type BTNode struct {
 	CompletionPredicate func(self *BTNode) bool
 	WhenDone            func(self *BTNode)
 	WhenChildDone       func(self *BTNode)
+
+	// for trees run with BTRunner.TickBT() (see bt_tick.go): Tick gives
+	// the node's status each tick it's run, Enter is called when it starts
+	// running and Exit when it stops (with BT_RUNNING if it was
+	// interrupted). Status is the status of its last tick
+	Tick  func(self *BTNode, ctx *BTContext) BTStatus
+	Enter func(self *BTNode)
+	Exit  func(self *BTNode, status BTStatus)
+	// checked when entered; the node fails if it returns false
+	Guard  func(self *BTNode, ctx *BTContext) bool
+	Status BTStatus
+	tick   btTickState
 }
 
 func (n *BTNode) SetChildren(children []*BTNode) {
@@ -95,7 +107,7 @@ func (n *BTNode) SetFailed(val bool) {
 	parent := n.Parent
 	// percolate failure up
 	for parent != nil {
-		if parent.IsFailed(parent) {
+		if parent.IsFailed != nil && parent.IsFailed(parent) {
 			parent.Failed = true
 			n.Tree.FailedNodeSet[parent] = true
 			parent = parent.Parent
@@ -115,12 +127,16 @@ type BTExecState struct {
 type BehaviourTree struct {
 	// how many times we've run this bad boy
 	run int
+	// total dt_ms of the ticks run by TickBT()
+	time_ms float64
 	// named with a string so we can modularly reuse subtrees by string name
 	Name          string
 	Root          *BTNode
 	FailedNodeSet map[*BTNode]bool
 	// current state is the path that's active down to its lowest node, an action
 	state *BTExecState
+	// if non-nil, records each ExecuteBT() (see bt_recorder.go)
+	recorder *BTRecorder
 }
 
 func NewBehaviourTree(name string, root *BTNode) *BehaviourTree {
@@ -158,6 +174,24 @@ func (bt *BehaviourTree) ResetFailed() {
 	}
 }
 
+// Reset clears completion and failure throughout the tree so it can be run
+// again from the start
+func (bt *BehaviourTree) Reset() {
+	bt.ResetFailed()
+	var reset func(node *BTNode)
+	reset = func(node *BTNode) {
+		node.Complete = false
+		node.CompletedChildren = 0
+		for _, ch := range node.Children {
+			reset(ch)
+		}
+	}
+	if bt.Root != nil {
+		reset(bt.Root)
+	}
+	bt.state = nil
+}
+
 // stores the database of named trees and decorators needed to run a tree
 type BTRunner struct {
 	// if we reach a string node with no children, it is potentially just
@@ -168,14 +202,23 @@ type BTRunner struct {
 	trees map[string]*BehaviourTree
 
 	// the runner has a set of decorators that it can honour - a decorator is
-	// just a string for now, but really, it should be a parsed DSL
+	// just a string (trees loaded from data use EFDSL predicates instead)
 	decorators map[string]func(*BTNode) bool
+
+	// for trees loaded from data (see bt_data.go)
+	actions map[string]BTActionFunc
+	defs    map[string]*btTreeDef
+
+	// the record of the ExecuteBT() running, if its tree is recorded
+	recording *BTRecord
 }
 
 func NewBTRunner() *BTRunner {
 	return &BTRunner{
 		trees:      make(map[string]*BehaviourTree),
 		decorators: make(map[string]func(self *BTNode) bool),
+		actions:    make(map[string]BTActionFunc),
+		defs:       make(map[string]*btTreeDef),
 	}
 }
 
@@ -193,6 +236,7 @@ func (btr *BTRunner) RunDecorators(node *BTNode) bool {
 			// and if it returns false, it failed. Mark this node as
 			// failed
 			succeed := dec(node)
+			btr.recording.decorator(node, dstr, succeed)
 			node.SetFailed(!succeed)
 			if !succeed {
 				return false
@@ -206,6 +250,17 @@ func (btr *BTRunner) RunDecorators(node *BTNode) bool {
 }
 
 func (btr *BTRunner) ExecuteBT(e *Entity, bt *BehaviourTree) *BTExecState {
+	if bt.recorder == nil {
+		return btr.executeBT(e, bt)
+	}
+	btr.recording = bt.recorder.begin(e, bt)
+	state := btr.executeBT(e, bt)
+	bt.recorder.finish(btr.recording, bt, state)
+	btr.recording = nil
+	return state
+}
+
+func (btr *BTRunner) executeBT(e *Entity, bt *BehaviourTree) *BTExecState {
 	bt.run++
 	bt.ResetFailed()
 	state := &BTExecState{}
@@ -225,6 +280,7 @@ func (btr *BTRunner) ExecuteBT(e *Entity, bt *BehaviourTree) *BTExecState {
 		// every node we visit, is on the path
 		// how beautiful
 		dotPath(node.Name)
+		btr.recording.visit(node)
 
 		if node.Complete {
 			// when we reach something that's done, our path ends in a dot.
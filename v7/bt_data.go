@@ -0,0 +1,708 @@
+package sameriver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+/*
+Behaviour trees can be loaded from data into a BTRunner (see LoadTrees())
+and instantiated per entity with NewTree(), so that they can be authored
+without Go changes. The JSON schema is:
+
+	{
+	  "trees": {
+	    "eat": {
+	      // optional; a param with a null default must be given by
+	      // references to the tree
+	      "params": {"food": null, "patience": 3000},
+	      "root": {
+	        "type": "timeLimit", "ms": "$patience",
+	        "child": {"type": "action", "action": "eat", "params": {"item": "$food"}}
+	      }
+	    },
+	    "villager": {
+	      "root": {
+	        "type": "selector", "name": "root",
+	        "children": [
+	          {"type": "conditionalAbort", "abort": "lowerPriority",
+	           "if": "State(scared, 1)",
+	           "child": {"type": "action", "action": "flee"}},
+	          {"type": "tree", "tree": "eat", "params": {"food": "berries"},
+	           "if": "CanBe(hungry, 1)"},
+	          {"type": "action", "action": "wander"}
+	        ]
+	      }
+	    }
+	  }
+	}
+
+Node types are the built-in nodes of bt_tick.go:
+
+	sequence, selector, random  children
+	parallel                    children, successes (optional, default all)
+	priority                    children, each with a "priority": a number
+	                            or an identifier like "mind.hunger"
+	inverter                    child
+	repeat, retry               child, times (optional, default forever)
+	timeLimit, cooldown         child, ms
+	conditionalAbort            child, if, abort (none, self, lowerPriority
+	                            or both; default self)
+	condition                   if
+	action                      action (registered with RegisterActions()),
+	                            params (optional, given to the action)
+	tree                        tree (loaded, or registered with
+	                            RegisterTree()), params (optional)
+
+Any node can have a "name" (the default is the action or tree name, or the
+type) and an "if": an EFDSL predicate tested on the ticking entity itself
+(eg. "CanBe(hungry, 1)"), checked when the node is entered, which fails the
+node if the entity doesn't pass it. Strings anywhere in a tree's nodes can use
+its params as "$param" (a string which is just "$param" takes the param's
+value, whatever its type).
+
+Everything is validated when loaded (register actions first); all problems
+found are reported together in a *BTDataError. Predicates are compiled (and
+checked against the world's EFDSL predicates) by NewTree().
+*/
+
+type BTActionFunc func(self *BTNode, ctx *BTContext, params map[string]any) BTStatus
+
+// BTDataError lists the problems found validating behaviour tree data
+type BTDataError struct {
+	Problems []string
+}
+
+func (err *BTDataError) Error() string {
+	return fmt.Sprintf("invalid behaviour tree data:\n    %s", strings.Join(err.Problems, "\n    "))
+}
+
+func (err *BTDataError) add(at string, format string, args ...any) {
+	err.Problems = append(err.Problems, at+": "+fmt.Sprintf(format, args...))
+}
+
+type btTreeDef struct {
+	name   string
+	params map[string]any
+	root   map[string]any
+}
+
+// a reference from one loaded tree to another, checked once all are loaded
+type btTreeRef struct {
+	at     string
+	from   string
+	tree   string
+	params map[string]any
+}
+
+// the fields each node type has, besides type, name, if and priority
+var btNodeFields = map[string][]string{
+	"sequence":         {"children"},
+	"selector":         {"children"},
+	"random":           {"children"},
+	"priority":         {"children"},
+	"parallel":         {"children", "successes"},
+	"inverter":         {"child"},
+	"repeat":           {"child", "times"},
+	"retry":            {"child", "times"},
+	"timeLimit":        {"child", "ms"},
+	"cooldown":         {"child", "ms"},
+	"conditionalAbort": {"child", "abort"},
+	"condition":        {},
+	"action":           {"action", "params"},
+	"tree":             {"tree", "params"},
+}
+
+var btAbortModes = map[string]BTAbortMode{
+	"none":          BT_ABORT_NONE,
+	"self":          BT_ABORT_SELF,
+	"lowerPriority": BT_ABORT_LOWER_PRIORITY,
+	"both":          BT_ABORT_BOTH,
+}
+
+var btParamRegexp = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// RegisterTree adds a tree which other trees can refer to by name
+func (btr *BTRunner) RegisterTree(bt *BehaviourTree) {
+	btr.trees[bt.Name] = bt
+}
+
+// RegisterActions adds the actions that "action" nodes can name
+func (btr *BTRunner) RegisterActions(actions map[string]BTActionFunc) {
+	for name, f := range actions {
+		btr.actions[name] = f
+	}
+}
+
+func (btr *BTRunner) LoadTreesFile(filename string) error {
+	Logger.Printf("Loading behaviour trees from %s...", filename)
+	jsonFile, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer jsonFile.Close()
+	contents, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return err
+	}
+	return btr.LoadTreesJSON(contents)
+}
+
+func (btr *BTRunner) LoadTreesJSON(jsonStr []byte) error {
+	var data map[string]any
+	if err := json.Unmarshal(jsonStr, &data); err != nil {
+		return err
+	}
+	return btr.LoadTrees(data)
+}
+
+// LoadTrees validates behaviour tree data already decoded into generic maps
+// and slices (as by encoding/json, or a YAML decoder), adding the trees to
+// those NewTree() can make. If there are any problems, no trees are added
+func (btr *BTRunner) LoadTrees(data map[string]any) error {
+	errs := &BTDataError{}
+	for _, k := range goapSortedKeys(data) {
+		if k != "trees" {
+			errs.add(k, "unknown field (expected trees)")
+		}
+	}
+	trees, ok := data["trees"].(map[string]any)
+	if !ok {
+		errs.add("trees", "should be an object of trees by name")
+	}
+	defs := make(map[string]*btTreeDef)
+	refs := make([]btTreeRef, 0)
+	for _, name := range goapSortedKeys(trees) {
+		if def, ok := btr.loadTreeDef("trees."+name, name, trees[name], &refs, errs); ok {
+			defs[name] = def
+		}
+	}
+	btr.checkTreeRefs(defs, refs, errs)
+	if len(errs.Problems) > 0 {
+		return errs
+	}
+	for name, def := range defs {
+		btr.defs[name] = def
+	}
+	return nil
+}
+
+func (btr *BTRunner) loadTreeDef(
+	at string, name string, v any, refs *[]btTreeRef, errs *BTDataError) (*btTreeDef, bool) {
+
+	m, ok := v.(map[string]any)
+	if !ok {
+		errs.add(at, "should be an object with a root")
+		return nil, false
+	}
+	for _, k := range goapSortedKeys(m) {
+		if k != "params" && k != "root" {
+			errs.add(at, "unknown field %s", k)
+		}
+	}
+	def := &btTreeDef{name: name, params: make(map[string]any)}
+	if params, ok := m["params"]; ok {
+		if def.params, ok = params.(map[string]any); !ok {
+			errs.add(at+".params", "should be an object of param defaults")
+		}
+	}
+	root, ok := m["root"].(map[string]any)
+	if !ok {
+		errs.add(at+".root", "should be a node")
+		return nil, false
+	}
+	def.root = root
+	btr.loadNode(at+".root", root, def, refs, false, errs)
+	// (kept even if invalid, so references to it can be checked)
+	return def, true
+}
+
+// validate a node (and its descendants)
+func (btr *BTRunner) loadNode(
+	at string, v any, def *btTreeDef, refs *[]btTreeRef, inPriority bool, errs *BTDataError) {
+
+	m, ok := v.(map[string]any)
+	if !ok {
+		errs.add(at, "should be a node object")
+		return
+	}
+	nodeType, _ := m["type"].(string)
+	fields, ok := btNodeFields[nodeType]
+	if !ok {
+		errs.add(at, "unknown type %v (expected %s)", m["type"], strings.Join(btSortedNodeTypes(), ", "))
+		return
+	}
+	at = fmt.Sprintf("%s(%s)", at, nodeType)
+	allowed := map[string]bool{"type": true, "name": true, "if": true, "priority": true}
+	for _, f := range fields {
+		allowed[f] = true
+	}
+	for _, k := range goapSortedKeys(m) {
+		if !allowed[k] {
+			errs.add(at, "unknown field %s", k)
+		}
+	}
+	btCheckParamUses(at, m, def, errs)
+	if name, ok := m["name"]; ok {
+		if s, ok := name.(string); !ok || s == "" {
+			errs.add(at+".name", "should be a non-empty string")
+		}
+	}
+	if expr, ok := m["if"]; ok {
+		if s, ok := expr.(string); !ok || s == "" {
+			errs.add(at+".if", "should be an EFDSL predicate string")
+		} else if !btParamRegexp.MatchString(s) {
+			if _, err := (&EFDSLParser{}).Parse(s); err != nil {
+				errs.add(at+".if", "bad predicate %q: %s", s, err)
+			}
+		}
+	} else if nodeType == "condition" || nodeType == "conditionalAbort" {
+		errs.add(at, "should have an if")
+	}
+	if p, ok := m["priority"]; ok != inPriority {
+		if ok {
+			errs.add(at+".priority", "only children of priority nodes have a priority")
+		} else {
+			errs.add(at, "children of priority nodes should have a priority")
+		}
+	} else if ok {
+		switch x := p.(type) {
+		case float64:
+		case string:
+			if x == "" {
+				errs.add(at+".priority", "should be a number or identifier")
+			}
+		default:
+			errs.add(at+".priority", "should be a number or identifier")
+		}
+	}
+	for _, k := range []string{"successes", "times"} {
+		if x, ok := m[k]; ok && !btIsParam(x) {
+			btLoadInt(at+"."+k, x, errs)
+		}
+	}
+	if x, ok := m["ms"]; ok {
+		if _, isNum := x.(float64); !isNum && !btIsParam(x) {
+			errs.add(at+".ms", "should be a number")
+		}
+	} else if nodeType == "timeLimit" || nodeType == "cooldown" {
+		errs.add(at, "should have ms")
+	}
+	if abort, ok := m["abort"]; ok {
+		if s, _ := abort.(string); !btHasAbortMode(s) {
+			errs.add(at+".abort", "should be none, self, lowerPriority or both")
+		}
+	}
+	if params, ok := m["params"]; ok {
+		if _, ok := params.(map[string]any); !ok {
+			errs.add(at+".params", "should be an object")
+		}
+	}
+	for _, f := range fields {
+		switch f {
+		case "children":
+			children, ok := m["children"].([]any)
+			if !ok || len(children) == 0 {
+				errs.add(at+".children", "should be a non-empty array of nodes")
+			}
+			for i, ch := range children {
+				btr.loadNode(fmt.Sprintf("%s.children[%d]", at, i), ch, def, refs, nodeType == "priority", errs)
+			}
+		case "child":
+			btr.loadNode(at+".child", m["child"], def, refs, false, errs)
+		case "action":
+			action, ok := m["action"].(string)
+			if !ok {
+				errs.add(at+".action", "should be an action name")
+			} else if _, ok := btr.actions[action]; !ok {
+				errs.add(at+".action", "unknown action %s (register actions before loading)", action)
+			}
+		case "tree":
+			tree, ok := m["tree"].(string)
+			if !ok {
+				errs.add(at+".tree", "should be a tree name")
+				continue
+			}
+			params, _ := m["params"].(map[string]any)
+			*refs = append(*refs, btTreeRef{at: at, from: def.name, tree: tree, params: params})
+		}
+	}
+}
+
+func btHasAbortMode(s string) bool {
+	_, ok := btAbortModes[s]
+	return ok
+}
+
+func btSortedNodeTypes() []string {
+	types := make([]string, 0, len(btNodeFields))
+	for t := range btNodeFields {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func btIsParam(x any) bool {
+	s, ok := x.(string)
+	return ok && btParamRegexp.FindString(s) == s
+}
+
+func btLoadInt(at string, v any, errs *BTDataError) (int, bool) {
+	// (params given from Go may be ints)
+	if x, ok := v.(int); ok {
+		return x, true
+	}
+	goapErrs := &GOAPDataError{}
+	x, ok := goapLoadInt(at, v, goapErrs)
+	errs.Problems = append(errs.Problems, goapErrs.Problems...)
+	return x, ok
+}
+
+// check the "$param"s used in the node's own strings are the tree's params
+func btCheckParamUses(at string, m map[string]any, def *btTreeDef, errs *BTDataError) {
+	var check func(at string, v any)
+	check = func(at string, v any) {
+		switch x := v.(type) {
+		case string:
+			for _, match := range btParamRegexp.FindAllStringSubmatch(x, -1) {
+				if _, ok := def.params[match[1]]; !ok {
+					errs.add(at, "unknown param $%s", match[1])
+				}
+			}
+		case map[string]any:
+			for _, k := range goapSortedKeys(x) {
+				check(at+"."+k, x[k])
+			}
+		case []any:
+			for i, y := range x {
+				check(fmt.Sprintf("%s[%d]", at, i), y)
+			}
+		}
+	}
+	for _, k := range goapSortedKeys(m) {
+		if k != "children" && k != "child" {
+			check(at+"."+k, m[k])
+		}
+	}
+}
+
+// check references are to known trees, with known params, giving the
+// required ones, and that no tree refers to itself
+func (btr *BTRunner) checkTreeRefs(defs map[string]*btTreeDef, refs []btTreeRef, errs *BTDataError) {
+	lookup := func(name string) *btTreeDef {
+		if def, ok := defs[name]; ok {
+			return def
+		}
+		return btr.defs[name]
+	}
+	edges := make(map[string][]string)
+	for _, ref := range refs {
+		def := lookup(ref.tree)
+		if def == nil {
+			if _, ok := btr.trees[ref.tree]; !ok {
+				errs.add(ref.at+".tree", "unknown tree %s", ref.tree)
+			} else if len(ref.params) > 0 {
+				errs.add(ref.at+".params", "tree %s was registered from Go and takes no params", ref.tree)
+			}
+			continue
+		}
+		edges[ref.from] = append(edges[ref.from], ref.tree)
+		for _, k := range goapSortedKeys(ref.params) {
+			if _, ok := def.params[k]; !ok {
+				errs.add(ref.at+".params", "tree %s has no param %s", ref.tree, k)
+			}
+		}
+		for _, k := range goapSortedKeys(def.params) {
+			if _, given := ref.params[k]; def.params[k] == nil && !given {
+				errs.add(ref.at+".params", "tree %s needs param %s", ref.tree, k)
+			}
+		}
+	}
+	// depth-first search for cycles from each loaded tree
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		visiting := make(map[string]bool)
+		var visit func(tree string) bool
+		visit = func(tree string) bool {
+			if visiting[tree] {
+				return true
+			}
+			visiting[tree] = true
+			for _, next := range edges[tree] {
+				if next == name || visit(next) {
+					return true
+				}
+			}
+			return false
+		}
+		if visit(name) {
+			errs.add("trees."+name, "refers to itself through its subtrees")
+		}
+	}
+}
+
+//
+// instantiation
+//
+
+// NewTree makes an instance of a loaded tree (each entity running it needs
+// its own) for the world, with params overriding its defaults
+func (btr *BTRunner) NewTree(w *World, name string, params map[string]any) (*BehaviourTree, error) {
+	errs := &BTDataError{}
+	root := btr.instantiate(w, "trees."+name, name, params, errs)
+	if len(errs.Problems) > 0 {
+		return nil, errs
+	}
+	return NewBehaviourTree(name, root), nil
+}
+
+func (btr *BTRunner) instantiate(
+	w *World, at string, name string, params map[string]any, errs *BTDataError) *BTNode {
+
+	def, ok := btr.defs[name]
+	if !ok {
+		errs.add(at, "unknown tree %s", name)
+		return nil
+	}
+	args := make(map[string]any, len(def.params))
+	for k, v := range def.params {
+		args[k] = v
+	}
+	for k, v := range params {
+		if _, ok := def.params[k]; !ok {
+			errs.add(at, "tree %s has no param %s", name, k)
+		}
+		args[k] = v
+	}
+	for _, k := range goapSortedKeys(args) {
+		if args[k] == nil {
+			errs.add(at, "tree %s needs param %s", name, k)
+		}
+	}
+	if len(errs.Problems) > 0 {
+		return nil
+	}
+	return btr.buildNode(w, at+".root", def.root, args, errs)
+}
+
+// substitute the params into v
+func btSubstitute(v any, args map[string]any) any {
+	switch x := v.(type) {
+	case string:
+		if btIsParam(x) {
+			return args[x[1:]]
+		}
+		return btParamRegexp.ReplaceAllStringFunc(x, func(match string) string {
+			return fmt.Sprint(args[match[1:]])
+		})
+	case map[string]any:
+		result := make(map[string]any, len(x))
+		for k, y := range x {
+			result[k] = btSubstitute(y, args)
+		}
+		return result
+	case []any:
+		result := make([]any, len(x))
+		for i, y := range x {
+			result[i] = btSubstitute(y, args)
+		}
+		return result
+	}
+	return v
+}
+
+func (btr *BTRunner) buildNode(
+	w *World, at string, raw map[string]any, args map[string]any, errs *BTDataError) *BTNode {
+
+	nodeType := raw["type"].(string)
+	at = fmt.Sprintf("%s(%s)", at, nodeType)
+	// substitute into everything but the children, which are built with
+	// the same args
+	m := make(map[string]any, len(raw))
+	for k, v := range raw {
+		if k == "children" || k == "child" {
+			m[k] = v
+		} else {
+			m[k] = btSubstitute(v, args)
+		}
+	}
+	children := make([]*BTNode, 0)
+	if list, ok := m["children"].([]any); ok {
+		for i, ch := range list {
+			children = append(children,
+				btr.buildNode(w, fmt.Sprintf("%s.children[%d]", at, i), ch.(map[string]any), args, errs))
+		}
+	}
+	if ch, ok := m["child"].(map[string]any); ok {
+		children = append(children, btr.buildNode(w, at+".child", ch, args, errs))
+	}
+	var cond func(self *BTNode, ctx *BTContext) bool
+	if expr, ok := m["if"].(string); ok {
+		cond = btPredicate(w, at+".if", expr, errs)
+	}
+	intField := func(k string) int {
+		if x, ok := m[k]; ok {
+			n, _ := btLoadInt(at+"."+k, x, errs)
+			return n
+		}
+		return 0
+	}
+	msField := func() float64 {
+		if ms, ok := m["ms"].(int); ok {
+			return float64(ms)
+		}
+		ms, ok := m["ms"].(float64)
+		if !ok {
+			errs.add(at+".ms", "should be a number, got %v", m["ms"])
+		}
+		return ms
+	}
+	if len(errs.Problems) > 0 {
+		return nil
+	}
+
+	var n *BTNode
+	switch nodeType {
+	case "sequence":
+		n = BTSequence(nodeType, children...)
+	case "selector":
+		n = BTSelector(nodeType, children...)
+	case "random":
+		n = BTRandom(nodeType, children...)
+	case "priority":
+		priorities := make(map[*BTNode]any, len(children))
+		for i, ch := range children {
+			priorities[ch] = btSubstitute(m["children"].([]any)[i].(map[string]any)["priority"], args)
+		}
+		n = BTPriority(nodeType, func(child *BTNode, ctx *BTContext) float64 {
+			return btPriority(w, priorities[child], ctx)
+		}, children...)
+	case "parallel":
+		n = BTParallel(nodeType, intField("successes"), children...)
+	case "inverter":
+		n = BTInverter(children[0])
+	case "repeat":
+		n = BTRepeat(intField("times"), children[0])
+	case "retry":
+		n = BTRetry(intField("times"), children[0])
+	case "timeLimit":
+		n = BTTimeLimit(msField(), children[0])
+	case "cooldown":
+		n = BTCooldown(msField(), children[0])
+	case "conditionalAbort":
+		mode := BT_ABORT_SELF
+		if s, ok := m["abort"].(string); ok {
+			mode = btAbortModes[s]
+		}
+		n = BTConditionalAbort(nodeType, mode, cond, children[0])
+		// the condition is the node's own, not a guard
+		cond = nil
+	case "condition":
+		n = BTCondition(nodeType, cond)
+		cond = nil
+	case "action":
+		name := m["action"].(string)
+		f := btr.actions[name]
+		params, _ := m["params"].(map[string]any)
+		n = BTAction(name, func(self *BTNode, ctx *BTContext) BTStatus {
+			return f(self, ctx, params)
+		})
+	case "tree":
+		name := m["tree"].(string)
+		params, _ := m["params"].(map[string]any)
+		if _, ok := btr.defs[name]; ok {
+			n = btr.instantiate(w, at, name, params, errs)
+		} else {
+			// a tree registered from Go, looked up as it's ticked (by the
+			// tree's name, since the node may be given its own)
+			n = &BTNode{Name: name, State: make(map[string]any),
+				Tick: func(self *BTNode, ctx *BTContext) BTStatus {
+					self.tick.ref = ctx.Runner.trees[name].Root
+					return self.tick.ref.tickNode(ctx)
+				}}
+		}
+	}
+	if n == nil {
+		return nil
+	}
+	if name, ok := m["name"].(string); ok {
+		n.Name = name
+	} else if nodeType != "action" && nodeType != "tree" {
+		n.Name = nodeType
+	}
+	// (a subtree's root may have its own guard)
+	if guard := n.Guard; guard != nil && cond != nil {
+		n.Guard = func(self *BTNode, ctx *BTContext) bool {
+			return cond(self, ctx) && guard(self, ctx)
+		}
+	} else if cond != nil {
+		n.Guard = cond
+	}
+	return n
+}
+
+// compile an EFDSL predicate to check against the ticking entity
+func btPredicate(w *World, at string, expr string, errs *BTDataError) func(self *BTNode, ctx *BTContext) bool {
+	ast, err := (&EFDSLParser{}).Parse(expr)
+	if err != nil {
+		errs.add(at, "bad predicate %q: %s", expr, err)
+		return nil
+	}
+	if len(ast.Children) > 1 {
+		errs.add(at, "predicate %q shouldn't have a spatial clause, sort or page", expr)
+		return nil
+	}
+	var unknown func(n *Node) bool
+	unknown = func(n *Node) bool {
+		if n.Type == NodeFunction {
+			if _, ok := w.EFDSL.predicates[n.Value]; !ok {
+				errs.add(at, "unknown predicate %s in %q", n.Value, expr)
+				return true
+			}
+			return false
+		}
+		for _, ch := range n.Children {
+			if unknown(ch) {
+				return true
+			}
+		}
+		return false
+	}
+	if unknown(ast) {
+		return nil
+	}
+	return func(self *BTNode, ctx *BTContext) bool {
+		filter, _ := w.EFDSL.Evaluate(ast, &EntityResolver{e: ctx.Entity, w: w})
+		return filter(ctx.Entity)
+	}
+}
+
+// a priority is a number or an identifier resolved for the entity
+func btPriority(w *World, p any, ctx *BTContext) float64 {
+	switch x := p.(type) {
+	case float64:
+		return x
+	case int:
+		return float64(x)
+	case string:
+		switch v := (&EntityResolver{e: ctx.Entity, w: w}).Resolve(x).(type) {
+		case int:
+			return float64(v)
+		case float64:
+			return v
+		case bool:
+			return goapBoolVal(v)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,215 @@
+package sameriver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testingBTDataRunner(done map[string]int, params map[string]any) *BTRunner {
+	btr := NewBTRunner()
+	action := func(name string, ticks int) BTActionFunc {
+		return func(self *BTNode, ctx *BTContext, p map[string]any) BTStatus {
+			if item, ok := p["item"]; ok {
+				params[name] = item
+			}
+			done[name]++
+			if done[name]%ticks == 0 {
+				return BT_SUCCESS
+			}
+			return BT_RUNNING
+		}
+	}
+	btr.RegisterActions(map[string]BTActionFunc{
+		"eat":    action("eat", 10),
+		"flee":   action("flee", 100),
+		"wander": action("wander", 2),
+		"sweep":  action("sweep", 100),
+		"cook":   action("cook", 100),
+	})
+	return btr
+}
+
+func TestBTDataLoad(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			STATE_: map[string]int{"hungry": 0, "scared": 0},
+		},
+		"mind": map[string]any{"dirt": 0.2},
+	})
+	done := make(map[string]int)
+	params := make(map[string]any)
+	btr := testingBTDataRunner(done, params)
+	if err := btr.LoadTreesFile("test_data/villager_bt.json"); err != nil {
+		t.Fatal(err)
+	}
+	bt, err := btr.NewTree(w, "villager", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := w.GetIntMap(e, STATE_)
+
+	btr.TickBT(e, bt, FRAME_MS)
+	if bt.RunningPath() != "root.repeat.wander" {
+		t.Fatalf("should wander when not hungry, at %s", bt.RunningPath())
+	}
+	state.Set("hungry", 1)
+	bt.Abort()
+	btr.TickBT(e, bt, FRAME_MS)
+	if bt.RunningPath() != "root.lunch.eat" || params["eat"] != "berries" {
+		t.Fatalf("should eat the berries given to the subtree, at %s, params %v", bt.RunningPath(), params)
+	}
+	state.Set("scared", 1)
+	btr.TickBT(e, bt, FRAME_MS)
+	if bt.RunningPath() != "root.danger.flee" {
+		t.Fatalf("being scared should abort eating, at %s", bt.RunningPath())
+	}
+
+	// the eat tree's own default for patience, overridden
+	bt, err = btr.NewTree(w, "eat", map[string]any{"food": "bread", "patience": 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	btr.TickBT(e, bt, 40)
+	btr.TickBT(e, bt, 40)
+	if status := btr.TickBT(e, bt, 40); status != BT_FAILURE || params["eat"] != "bread" {
+		t.Fatalf("eating bread should run out of patience, got %s, params %v", status, params)
+	}
+
+	bt, err = btr.NewTree(w, "chores", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	btr.TickBT(e, bt, FRAME_MS)
+	if bt.RunningPath() != "priority.cook" {
+		t.Fatalf("should cook while there's little dirt, at %s", bt.RunningPath())
+	}
+	e.Mind.Set("dirt", 0.9)
+	btr.TickBT(e, bt, FRAME_MS)
+	if bt.RunningPath() != "priority.sweep" {
+		t.Fatalf("should sweep once dirt is high, at %s", bt.RunningPath())
+	}
+}
+
+func TestBTDataRegisterTree(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	btr := testingBTDataRunner(make(map[string]int), make(map[string]any))
+	ticks := 0
+	goTree := NewBehaviourTree("goTree", BTAction("goAction",
+		func(self *BTNode, ctx *BTContext) BTStatus {
+			ticks++
+			return BT_SUCCESS
+		}))
+	btr.RegisterTree(goTree)
+	if btr.trees["goTree"] != goTree {
+		t.Fatal("should register the tree by its name")
+	}
+	if err := btr.LoadTreesJSON([]byte(`{"trees": {
+		"a": {"root": {"type": "tree", "tree": "goTree"}}
+	}}`)); err != nil {
+		t.Fatal(err)
+	}
+	bt, err := btr.NewTree(w, "a", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := btr.TickBT(e, bt, FRAME_MS); status != BT_SUCCESS || ticks != 1 {
+		t.Fatalf("loaded tree should tick the registered tree, got %s", status)
+	}
+}
+
+func TestBTDataNamedGoTreeRef(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	done := make(map[string]int)
+	btr := testingBTDataRunner(done, make(map[string]any))
+	btr.RegisterTree(NewBehaviourTree("goChores", BTAction("goSweep",
+		func(self *BTNode, ctx *BTContext) BTStatus {
+			done["goSweep"]++
+			return BT_SUCCESS
+		})))
+	if err := btr.LoadTreesJSON([]byte(`{"trees": {
+		"day": {"root": {"type": "tree", "tree": "goChores", "name": "doChores"}}
+	}}`)); err != nil {
+		t.Fatal(err)
+	}
+	bt, err := btr.NewTree(w, "day", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := btr.TickBT(e, bt, FRAME_MS); status != BT_SUCCESS || done["goSweep"] != 1 {
+		t.Fatalf("renamed reference should tick the Go tree, got %s", status)
+	}
+	if bt.Root.Name != "doChores" {
+		t.Fatalf("reference should have its own name, got %s", bt.Root.Name)
+	}
+}
+
+func TestBTDataValidation(t *testing.T) {
+	btr := testingBTDataRunner(make(map[string]int), make(map[string]any))
+	btr.RegisterTree(NewBehaviourTree("goTree", BTCondition("yes",
+		func(self *BTNode, ctx *BTContext) bool { return true })))
+	err := btr.LoadTreesJSON([]byte(`{
+		"trees": {
+			"a": {"root": {"type": "sequence", "children": [
+				{"type": "action", "action": "dance"},
+				{"type": "repeat", "times": 1.5, "child": {"type": "action", "action": "eat", "colour": "red"}},
+				{"type": "condition", "if": "State(hungry, 1"},
+				{"type": "timeLimit", "child": {"type": "tree", "tree": "b", "params": {"x": 1, "y": 2}}}
+			]}},
+			"b": {"params": {"x": null, "z": null}, "root": {"type": "tree", "tree": "a", "if": "State($w, 1)"}},
+			"c": {"root": {"type": "selector", "children": [
+				{"type": "tree", "tree": "goTree", "params": {"x": 1}},
+				{"type": "tree", "tree": "nowhere", "priority": 1},
+				{"type": "conditionalAbort", "abort": "sometimes", "child": {"type": "spin"}}
+			]}}
+		},
+		"extra": 1
+	}`))
+	var dataErr *BTDataError
+	if !errors.As(err, &dataErr) {
+		t.Fatalf("should have failed validation, got %v", err)
+	}
+	for _, expected := range []string{
+		"extra: unknown field",
+		"trees.a.root(sequence).children[0](action).action: unknown action dance",
+		"trees.a.root(sequence).children[1](repeat).times: should be an integer",
+		"trees.a.root(sequence).children[1](repeat).child(action): unknown field colour",
+		"trees.a.root(sequence).children[2](condition).if: bad predicate",
+		"trees.a.root(sequence).children[3](timeLimit): should have ms",
+		"trees.b.root(tree).if: unknown param $w",
+		"children[3](timeLimit).child(tree).params: tree b has no param y",
+		"children[3](timeLimit).child(tree).params: tree b needs param z",
+		"trees.a: refers to itself through its subtrees",
+		"children[0](tree).params: tree goTree was registered from Go and takes no params",
+		"children[1](tree).tree: unknown tree nowhere",
+		"children[1](tree).priority: only children of priority nodes have a priority",
+		"children[2](conditionalAbort).abort: should be none, self, lowerPriority or both",
+		"children[2](conditionalAbort): should have an if",
+		"children[2](conditionalAbort).child: unknown type spin",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("errors should include %q:\n%s", expected, err)
+		}
+	}
+	if _, err := btr.NewTree(testingWorld(), "a", nil); err == nil {
+		t.Fatal("no trees should be loaded when validation fails")
+	}
+
+	// predicates are checked against the world when instantiated
+	if err := btr.LoadTreesJSON([]byte(`{"trees": {
+		"d": {"params": {"p": "Frobbed"}, "root": {"type": "condition", "if": "$p(self)"}}
+	}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := btr.NewTree(testingWorld(), "d", nil); err == nil ||
+		!strings.Contains(err.Error(), "unknown predicate Frobbed") {
+		t.Fatalf("should fail for unknown predicate, got %v", err)
+	}
+	if _, err := btr.NewTree(testingWorld(), "d", map[string]any{"q": 1}); err == nil ||
+		!strings.Contains(err.Error(), "tree d has no param q") {
+		t.Fatalf("should fail for unknown param, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package sameriver
+
+// BTGOAPSpec configures a BTGOAP() node
+type BTGOAPSpec struct {
+	Planner *GOAPPlanner
+	// a goal spec as accepted by GOAPPlanner.Plan(), or a func() any giving
+	// one each time the node plans
+	Goal any
+	// gives the live world state to plan from; nil means an empty state
+	State   func() *GOAPWorldState
+	MaxIter int
+	// how many times to replan after a step fails before failing
+	MaxReplans int
+}
+
+/*
+BTGOAP is a node which plans for its goal with the entity's GOAPPlanner
+when entered, and runs the plan as its children: one node per action of
+the path, named for the action, which the node steps through as a sequence.
+Each child is ticked by the BTRunner action registered under the GOAP
+action's name (see RegisterActions()), whose params hold:
+
+	"action"  the *GOAPAction
+	"node"    the *Entity bound to the action's node
+	"count"   the action's count
+
+A child with no registered action runs until Done() or SetFailed(true) is
+called on it (eg. by an FSM playing an animation). Completion is tracked
+with Done(), as for older trees: the node succeeds once CompletedChildren
+reaches the length of the plan. If a child fails (or its node entity has
+despawned), the node replans from the current state, up to MaxReplans
+times, and then fails. If the planner has a GOAPNodeMemory, failed nodes
+are remembered, and if it has GOAPReservations, the plan's are held while
+the node runs.
+*/
+func BTGOAP(name string, spec BTGOAPSpec) *BTNode {
+	if spec.MaxIter == 0 {
+		spec.MaxIter = 500
+	}
+	n := btNode(name, nil)
+	n.CompletionPredicate = func(self *BTNode) bool {
+		return self.Children != nil && self.CompletedChildren == len(self.Children)
+	}
+	// failures of children are handled by replanning, not percolated
+	n.IsFailed = func(self *BTNode) bool {
+		return false
+	}
+	n.Tick = func(self *BTNode, ctx *BTContext) BTStatus {
+		if self.Children == nil && !btGOAPPlan(self, &spec, ctx) {
+			return BT_FAILURE
+		}
+		for self.tick.ix < len(self.Children) {
+			child := self.Children[self.tick.ix]
+			status := BT_FAILURE
+			if node, _ := child.State["node"].(*Entity); node == nil || !node.Despawned {
+				status = child.tickNode(ctx)
+			}
+			switch status {
+			case BT_RUNNING:
+				return BT_RUNNING
+			case BT_SUCCESS:
+				if !child.Complete {
+					child.Done()
+				}
+				self.tick.ix++
+			case BT_FAILURE:
+				child.halt()
+				btGOAPRememberFailure(&spec, child)
+				if self.tick.count >= spec.MaxReplans {
+					return BT_FAILURE
+				}
+				self.tick.count++
+				if !btGOAPPlan(self, &spec, ctx) {
+					return BT_FAILURE
+				}
+			}
+		}
+		return BT_SUCCESS
+	}
+	n.tick.enter = func(self *BTNode) {
+		self.Children = nil
+		self.CompletedChildren = 0
+	}
+	n.tick.exit = func(self *BTNode, status BTStatus) {
+		if r := spec.Planner.reservations; r != nil {
+			r.ReleaseAll(spec.Planner.e)
+		}
+	}
+	return n
+}
+
+// plan from the current state, making the path's actions the node's
+// children, returning whether a plan was found
+func btGOAPPlan(n *BTNode, spec *BTGOAPSpec, ctx *BTContext) bool {
+	p := spec.Planner
+	start := NewGOAPWorldState(nil)
+	if spec.State != nil {
+		start = spec.State()
+	}
+	goal := spec.Goal
+	if f, ok := goal.(func() any); ok {
+		goal = f()
+	}
+	path, ok := p.Plan(start, goal, spec.MaxIter)
+	if !ok {
+		logGOAPDebug("BT node %s found no plan", n.Name)
+		return false
+	}
+	nodes := make(map[string]*Entity)
+	for node, e := range path.statesAlong[len(path.path)].ModalEntities {
+		nodes[node] = e
+	}
+	if p.reservations != nil && !p.reservations.reservePlan(p.e, path, nodes) {
+		return false
+	}
+	children := make([]*BTNode, len(path.path))
+	for i, action := range path.path {
+		child := &BTNode{
+			Name: action.Name,
+			Tree: n.Tree,
+			State: map[string]any{
+				"action": action,
+				"node":   nodes[action.Node],
+				"count":  action.Count,
+			},
+		}
+		if f, ok := ctx.Runner.actions[action.Name]; ok {
+			params := child.State
+			child.Tick = func(self *BTNode, ctx *BTContext) BTStatus {
+				return f(self, ctx, params)
+			}
+		}
+		children[i] = child
+	}
+	n.SetChildren(children)
+	n.CompletedChildren = 0
+	n.Complete = false
+	n.tick.ix = 0
+	return true
+}
+
+func btGOAPRememberFailure(spec *BTGOAPSpec, child *BTNode) {
+	m := spec.Planner.nodeMemory
+	node, _ := child.State["node"].(*Entity)
+	if m == nil || node == nil || node == spec.Planner.e {
+		return
+	}
+	m.Fail(node, child.State["action"].(*GOAPAction).Node)
+}
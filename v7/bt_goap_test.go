@@ -0,0 +1,134 @@
+package sameriver
+
+import (
+	"testing"
+)
+
+func testingBTGOAPWoodcutter(w *World, maxReplans int) (*testingWoodcutter, *BehaviourTree) {
+	wc := testingSpawnWoodcutter(w, false)
+	bt := NewBehaviourTree("wood", BTGOAP("wood", BTGOAPSpec{
+		Planner: wc.p,
+		Goal:    map[string]int{"woodChopped,>=": 2},
+		State: func() *GOAPWorldState {
+			return NewGOAPWorldState(map[string]int{"woodChopped": wc.chopped})
+		},
+		MaxReplans: maxReplans,
+	}))
+	return wc, bt
+}
+
+func TestBTGOAPPlanAndDone(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	r := NewGOAPReservations(w, "village")
+	wc, bt := testingBTGOAPWoodcutter(w, 0)
+	wc.p.SetReservations(r)
+	btr := NewBTRunner()
+	// chopTree has no action, so runs until Done()
+	btr.RegisterActions(map[string]BTActionFunc{
+		"getAxe": func(self *BTNode, ctx *BTContext, params map[string]any) BTStatus {
+			if params["node"] != wc.axe {
+				t.Fatal("getAxe should be bound to the axe")
+			}
+			w.GetIntMap(ctx.Entity, STATE_).Set("hasAxe", 1)
+			return BT_SUCCESS
+		},
+	})
+	if status := btr.TickBT(wc.e, bt, FRAME_MS); status != BT_RUNNING {
+		t.Fatalf("should be running the plan, got %s", status)
+	}
+	children := bt.Root.Children
+	if len(children) != 2 || children[0].Name != "getAxe" || children[1].Name != "chopTree" {
+		t.Fatal("plan should be materialised as getAxe, chopTree")
+	}
+	if children[1].State["node"] != wc.tree || children[1].State["count"] != 2 {
+		t.Fatal("chopTree should be bound to the tree with its count")
+	}
+	if bt.RunningPath() != "wood.chopTree" {
+		t.Fatalf("should be on chopTree, got %s", bt.RunningPath())
+	}
+	if r.NodeReservedBy(wc.tree) != wc.e {
+		t.Fatal("plan's nodes should be reserved while it runs")
+	}
+	wc.chopped += 2
+	children[1].Done()
+	if status := btr.TickBT(wc.e, bt, FRAME_MS); status != BT_SUCCESS {
+		t.Fatalf("should succeed once the plan is done, got %s", status)
+	}
+	if !bt.Root.Complete {
+		t.Fatal("Done() should percolate up to the GOAP node")
+	}
+	if r.NodeReservedBy(wc.axe) != nil || r.NodeReservedBy(wc.tree) != nil {
+		t.Fatal("reservations should be released on exit")
+	}
+}
+
+func TestBTGOAPReplan(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc, bt := testingBTGOAPWoodcutter(w, 1)
+	btr := NewBTRunner()
+	attempts := 0
+	btr.RegisterActions(map[string]BTActionFunc{
+		"getAxe": func(self *BTNode, ctx *BTContext, params map[string]any) BTStatus {
+			attempts++
+			if attempts == 1 {
+				return BT_FAILURE
+			}
+			w.GetIntMap(ctx.Entity, STATE_).Set("hasAxe", 1)
+			return BT_SUCCESS
+		},
+		"chopTree": func(self *BTNode, ctx *BTContext, params map[string]any) BTStatus {
+			wc.chopped += params["count"].(int)
+			return BT_SUCCESS
+		},
+	})
+	if status := btr.TickBT(wc.e, bt, FRAME_MS); status != BT_SUCCESS {
+		t.Fatalf("should replan after getAxe fails and succeed, got %s", status)
+	}
+	if attempts != 2 || wc.chopped != 2 {
+		t.Fatal("should have retried getAxe and chopped the wood")
+	}
+}
+
+func TestBTGOAPFailUpward(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc, bt := testingBTGOAPWoodcutter(w, 2)
+	m := NewGOAPNodeMemory(w, 10000)
+	wc.p.SetNodeMemory(m)
+	btr := NewBTRunner()
+	failed := false
+	bt.Root = BTSequence("root", bt.Root, BTCondition("after", func(self *BTNode, ctx *BTContext) bool {
+		return true
+	}))
+	bt.Root.Exit = func(self *BTNode, status BTStatus) {
+		failed = status == BT_FAILURE
+	}
+	btr.RegisterActions(map[string]BTActionFunc{
+		"getAxe": func(self *BTNode, ctx *BTContext, params map[string]any) BTStatus {
+			return BT_FAILURE
+		},
+	})
+	// the only axe is remembered as failed, so replanning finds no plan
+	if status := btr.TickBT(wc.e, bt, FRAME_MS); status != BT_FAILURE || !failed {
+		t.Fatalf("should fail upward when no plan is left, got %s", status)
+	}
+	if m.Failures(wc.axe) != 1 {
+		t.Fatal("failed axe should be remembered")
+	}
+
+	// a despawned node fails its step
+	w2 := testingGOAPAgentWorld()
+	wc2, bt2 := testingBTGOAPWoodcutter(w2, 0)
+	btr2 := NewBTRunner()
+	btr2.RegisterActions(map[string]BTActionFunc{
+		"getAxe": func(self *BTNode, ctx *BTContext, params map[string]any) BTStatus {
+			return BT_RUNNING
+		},
+	})
+	if status := btr2.TickBT(wc2.e, bt2, FRAME_MS); status != BT_RUNNING {
+		t.Fatalf("should be running getAxe, got %s", status)
+	}
+	w2.Despawn(wc2.axe)
+	if status := btr2.TickBT(wc2.e, bt2, FRAME_MS); status != BT_FAILURE {
+		t.Fatalf("should fail when the axe despawns, got %s", status)
+	}
+}
@@ -0,0 +1,348 @@
+package sameriver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// outcomes of a run in a BTRecord
+const (
+	// descended to an action to run
+	BT_RECORD_ACTION = "action"
+	// descended to a node which was already done (the path ends in ".")
+	BT_RECORD_DONE = "done"
+	// a decorator failed or a selector chose no child
+	BT_RECORD_FAILED = "failed"
+	// the tree has no root
+	BT_RECORD_EMPTY = "empty"
+)
+
+// BTRecord is what happened in one BTRunner.ExecuteBT() of a tree being
+// recorded (see BehaviourTree.SetRecorder())
+type BTRecord struct {
+	Entity int    `json:"entity"`
+	Tree   string `json:"tree"`
+	// the tree's run counter
+	Run int `json:"run"`
+	// the nodes descended through, even if the run failed
+	Path    string `json:"path"`
+	Action  string `json:"action,omitempty"`
+	Outcome string `json:"outcome"`
+	// in the order they ran
+	Decorators []BTDecoratorRecord `json:"decorators,omitempty"`
+	// dotted paths of the nodes failed since the last run (by SetFailed())
+	// or during it (by decorators)
+	Failed []string `json:"failed,omitempty"`
+	// the nodes on the path, root first
+	Nodes []BTNodeRecord `json:"nodes"`
+
+	visited    map[*BTNode]bool
+	decorators map[*BTNode][]BTDecoratorRecord
+}
+
+type BTDecoratorRecord struct {
+	Node      string `json:"node"`
+	Decorator string `json:"decorator"`
+	Passed    bool   `json:"passed"`
+}
+
+type BTNodeRecord struct {
+	Name              string `json:"name"`
+	Complete          bool   `json:"complete"`
+	Failed            bool   `json:"failed"`
+	CompletedChildren int    `json:"completedChildren"`
+	// a copy of the node's State as of the run; values other than bools,
+	// numbers and strings are formatted with %v
+	State map[string]any `json:"state,omitempty"`
+}
+
+/*
+BTRecorder keeps the history of the runs of the trees it's set on, as a
+ring buffer of the last Capacity BTRecords of each entity. One recorder can
+be shared by the trees of many entities, eg.
+
+	recorder := NewBTRecorder(32)
+	bt.SetRecorder(recorder)
+	...
+	fmt.Print(recorder.Last(e).Path)
+
+Entity IDs are reused after despawn, so Clear() an entity's history if
+it's despawned while recording.
+*/
+type BTRecorder struct {
+	Capacity  int
+	histories map[int]*btHistory
+}
+
+type btHistory struct {
+	records []*BTRecord
+	// index of the oldest record, once the buffer is full
+	next int
+}
+
+func NewBTRecorder(capacity int) *BTRecorder {
+	if capacity <= 0 {
+		panic("BTRecorder capacity must be positive")
+	}
+	return &BTRecorder{
+		Capacity:  capacity,
+		histories: make(map[int]*btHistory),
+	}
+}
+
+// SetRecorder sets the recorder recording the tree's runs (nil to stop
+// recording). Only ExecuteBT() is recorded, not TickBT()
+func (bt *BehaviourTree) SetRecorder(r *BTRecorder) {
+	bt.recorder = r
+}
+
+func (bt *BehaviourTree) Recorder() *BTRecorder {
+	return bt.recorder
+}
+
+func (r *BTRecorder) add(rec *BTRecord) {
+	h, ok := r.histories[rec.Entity]
+	if !ok {
+		h = &btHistory{records: make([]*BTRecord, 0, r.Capacity)}
+		r.histories[rec.Entity] = h
+	}
+	if len(h.records) < r.Capacity {
+		h.records = append(h.records, rec)
+		return
+	}
+	h.records[h.next] = rec
+	h.next = (h.next + 1) % len(h.records)
+}
+
+// History returns the recorded runs of the entity, oldest first
+func (r *BTRecorder) History(e *Entity) []*BTRecord {
+	h, ok := r.histories[e.ID]
+	if !ok {
+		return nil
+	}
+	result := make([]*BTRecord, 0, len(h.records))
+	result = append(result, h.records[h.next:]...)
+	result = append(result, h.records[:h.next]...)
+	return result
+}
+
+// Last returns the entity's last recorded run, or nil
+func (r *BTRecorder) Last(e *Entity) *BTRecord {
+	h, ok := r.histories[e.ID]
+	if !ok || len(h.records) == 0 {
+		return nil
+	}
+	return h.records[(h.next+len(h.records)-1)%len(h.records)]
+}
+
+// Clear forgets the entity's history
+func (r *BTRecorder) Clear(e *Entity) {
+	delete(r.histories, e.ID)
+}
+
+// JSON encodes the entity's history, oldest first
+func (r *BTRecorder) JSON(e *Entity) ([]byte, error) {
+	history := r.History(e)
+	if history == nil {
+		history = []*BTRecord{}
+	}
+	return json.MarshalIndent(history, "", "  ")
+}
+
+// start recording a run, noting the failures since the last run before
+// ExecuteBT() resets them
+func (r *BTRecorder) begin(e *Entity, bt *BehaviourTree) *BTRecord {
+	rec := &BTRecord{
+		Entity:     e.ID,
+		Tree:       bt.Name,
+		Nodes:      make([]BTNodeRecord, 0),
+		visited:    make(map[*BTNode]bool),
+		decorators: make(map[*BTNode][]BTDecoratorRecord),
+	}
+	rec.addFailed(bt)
+	return rec
+}
+
+func (rec *BTRecord) visit(n *BTNode) {
+	if rec == nil {
+		return
+	}
+	rec.visited[n] = true
+	if rec.Path == "" {
+		rec.Path = n.Name
+	} else {
+		rec.Path += "." + n.Name
+	}
+	nr := BTNodeRecord{
+		Name:              n.Name,
+		Complete:          n.Complete,
+		Failed:            n.Failed,
+		CompletedChildren: n.CompletedChildren,
+	}
+	if len(n.State) > 0 {
+		nr.State = make(map[string]any, len(n.State))
+		for k, v := range n.State {
+			nr.State[k] = btSnapshotValue(v)
+		}
+	}
+	rec.Nodes = append(rec.Nodes, nr)
+}
+
+func (rec *BTRecord) decorator(n *BTNode, decorator string, passed bool) {
+	if rec == nil {
+		return
+	}
+	dr := BTDecoratorRecord{Node: btNodePath(n), Decorator: decorator, Passed: passed}
+	rec.Decorators = append(rec.Decorators, dr)
+	rec.decorators[n] = append(rec.decorators[n], dr)
+}
+
+func (rec *BTRecord) addFailed(bt *BehaviourTree) {
+	for n := range bt.FailedNodeSet {
+		path := btNodePath(n)
+		found := false
+		for _, f := range rec.Failed {
+			if f == path {
+				found = true
+				break
+			}
+		}
+		if !found {
+			rec.Failed = append(rec.Failed, path)
+		}
+	}
+	sort.Strings(rec.Failed)
+}
+
+func (r *BTRecorder) finish(rec *BTRecord, bt *BehaviourTree, state *BTExecState) {
+	rec.Run = bt.run
+	rec.addFailed(bt)
+	// nodes of referenced trees fail in their own tree's FailedNodeSet
+	for n := range rec.visited {
+		if n.Tree != nil && n.Tree != bt {
+			rec.addFailed(n.Tree)
+		}
+	}
+	switch {
+	case bt.Root == nil:
+		rec.Outcome = BT_RECORD_EMPTY
+	case state == nil:
+		rec.Outcome = BT_RECORD_FAILED
+	case strings.HasSuffix(state.Path, "."):
+		rec.Outcome = BT_RECORD_DONE
+		rec.Action = state.Action.Name
+	default:
+		rec.Outcome = BT_RECORD_ACTION
+		if state.Action != nil {
+			rec.Action = state.Action.Name
+		}
+	}
+	r.add(rec)
+}
+
+// values which encode to JSON as themselves are kept, the rest formatted
+func btSnapshotValue(v any) any {
+	switch v := v.(type) {
+	case nil, bool, string, int, int32, int64, float32, float64:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// the dotted names of the node's ancestors and itself, eg. "root.work.getAxe"
+func btNodePath(n *BTNode) string {
+	names := make([]string, 0)
+	for ; n != nil; n = n.Parent {
+		names = append(names, n.Name)
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, ".")
+}
+
+/*
+DumpBT renders the tree as text for a debug overlay, one node per line,
+eg.
+
+	> Utility
+	    fight-flight
+	  > plant
+	  > Sequence [planPlant ok]
+	    + getYoke
+	    > yokeOx {ticks: 2}
+	      plowField
+
+marking the nodes on the path of the last recorded run with ">" (or, if
+the tree isn't recorded, none), failed nodes with "x" and complete nodes
+with "+". Decorators are shown with their outcome in that run, and the
+states of the nodes on the path. A leaf naming a registered tree is
+followed by that tree's nodes if the path went through it.
+*/
+func (btr *BTRunner) DumpBT(e *Entity, bt *BehaviourTree) string {
+	var rec *BTRecord
+	if bt.recorder != nil {
+		rec = bt.recorder.Last(e)
+	}
+	var buf bytes.Buffer
+	var dump func(n *BTNode, depth int)
+	dump = func(n *BTNode, depth int) {
+		onPath := rec != nil && rec.visited[n]
+		buf.WriteString(strings.Repeat("  ", depth))
+		switch {
+		case n.Failed:
+			buf.WriteString("x ")
+		case onPath:
+			buf.WriteString("> ")
+		case n.Complete:
+			buf.WriteString("+ ")
+		default:
+			buf.WriteString("  ")
+		}
+		buf.WriteString(n.Name)
+		if len(n.Decorators) > 0 {
+			decs := make([]string, len(n.Decorators))
+			for i, d := range n.Decorators {
+				decs[i] = d
+				if rec != nil {
+					for _, dr := range rec.decorators[n] {
+						if dr.Decorator == d && dr.Passed {
+							decs[i] += " ok"
+						} else if dr.Decorator == d {
+							decs[i] += " failed"
+						}
+					}
+				}
+			}
+			fmt.Fprintf(&buf, " [%s]", strings.Join(decs, ", "))
+		}
+		if onPath && len(n.State) > 0 {
+			keys := make([]string, 0, len(n.State))
+			for k := range n.State {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			kvs := make([]string, len(keys))
+			for i, k := range keys {
+				kvs[i] = fmt.Sprintf("%s: %v", k, btSnapshotValue(n.State[k]))
+			}
+			fmt.Fprintf(&buf, " {%s}", strings.Join(kvs, ", "))
+		}
+		buf.WriteString("\n")
+		for _, ch := range n.Children {
+			dump(ch, depth+1)
+		}
+		if tree, ok := btr.trees[n.Name]; ok && len(n.Children) == 0 &&
+			tree.Root != n && onPath && rec.visited[tree.Root] {
+			dump(tree.Root, depth)
+		}
+	}
+	if bt.Root != nil {
+		dump(bt.Root, 0)
+	}
+	return buf.String()
+}
@@ -0,0 +1,146 @@
+package sameriver
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testingBTRecorderTrees(btr *BTRunner, haveYoke *bool) *BehaviourTree {
+	btr.RegisterDecorators([]BTDecorator{
+		{
+			Name: "haveYoke",
+			Impl: func(self *BTNode) bool {
+				return *haveYoke
+			},
+		},
+	})
+	root := NewBehaviourTree("villager", &BTNode{
+		Name: "Utility",
+		Selector: func(self *BTNode) int {
+			return 1
+		},
+		Children: []*BTNode{
+			{Name: "rest"},
+			{Name: "plant"},
+		},
+	})
+	btr.RegisterTree(NewBehaviourTree("plant", &BTNode{
+		Name:       "Sequence",
+		Decorators: []string{"haveYoke"},
+		Selector: func(self *BTNode) int {
+			return self.CompletedChildren
+		},
+		CompletionPredicate: func(self *BTNode) bool {
+			return self.CompletedChildren == len(self.Children)
+		},
+		Children: []*BTNode{
+			{Name: "yokeOx"},
+			{Name: "plowField", Init: func(self *BTNode) {
+				self.State["furrows"] = 3
+				self.State["rows"] = []int{1, 2}
+			}},
+		},
+	}))
+	return root
+}
+
+func TestBTRecorder(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	btr := NewBTRunner()
+	haveYoke := false
+	bt := testingBTRecorderTrees(btr, &haveYoke)
+	r := NewBTRecorder(3)
+	bt.SetRecorder(r)
+
+	if btr.ExecuteBT(e, bt) != nil {
+		t.Fatal("should fail without a yoke")
+	}
+	rec := r.Last(e)
+	if rec.Outcome != BT_RECORD_FAILED || rec.Path != "Utility.plant.Sequence" || rec.Run != 1 {
+		t.Fatalf("should record the failed run's path, got %+v", rec)
+	}
+	if len(rec.Decorators) != 1 || rec.Decorators[0] != (BTDecoratorRecord{"Sequence", "haveYoke", false}) {
+		t.Fatalf("should record the decorator failing, got %+v", rec.Decorators)
+	}
+	if len(rec.Failed) != 1 || rec.Failed[0] != "Sequence" {
+		t.Fatalf("should record the failed node, got %v", rec.Failed)
+	}
+
+	haveYoke = true
+	state := btr.ExecuteBT(e, bt)
+	state.Action.Done()
+	btr.ExecuteBT(e, bt)
+	rec = r.Last(e)
+	if rec.Outcome != BT_RECORD_ACTION || rec.Action != "plowField" ||
+		rec.Path != "Utility.plant.Sequence.plowField" {
+		t.Fatalf("should record descending to plowField, got %+v", rec)
+	}
+	plow := rec.Nodes[len(rec.Nodes)-1]
+	if plow.State["furrows"] != 3 || plow.State["rows"] != "[1 2]" {
+		t.Fatalf("should snapshot the node's state, got %v", plow.State)
+	}
+	if seq := rec.Nodes[2]; seq.Name != "Sequence" || seq.CompletedChildren != 1 {
+		t.Fatalf("should snapshot the sequence's progress, got %+v", seq)
+	}
+
+	// the ring buffer keeps the last 3 runs, oldest first
+	btr.ExecuteBT(e, bt)
+	history := r.History(e)
+	if len(history) != 3 || history[0].Run != 2 || history[2].Run != 4 {
+		t.Fatalf("should keep the last 3 runs, got %d", len(history))
+	}
+	// histories are per entity
+	other := w.Spawn(nil)
+	if r.Last(other) != nil || r.History(other) != nil {
+		t.Fatal("other entity should have no history")
+	}
+
+	// JSON
+	jsonStr, err := r.JSON(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := make([]*BTRecord, 0)
+	if err := json.Unmarshal(jsonStr, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 3 || decoded[2].Path != history[2].Path || decoded[2].Nodes[3].State["rows"] != "[1 2]" {
+		t.Fatalf("JSON should round-trip the history, got %s", jsonStr)
+	}
+
+	// stop recording
+	bt.SetRecorder(nil)
+	btr.ExecuteBT(e, bt)
+	if r.Last(e).Run != 4 {
+		t.Fatal("should not record once the recorder is unset")
+	}
+	r.Clear(e)
+	if r.Last(e) != nil {
+		t.Fatal("history should be cleared")
+	}
+}
+
+func TestBTRecorderDump(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	btr := NewBTRunner()
+	haveYoke := true
+	bt := testingBTRecorderTrees(btr, &haveYoke)
+	bt.SetRecorder(NewBTRecorder(8))
+	btr.ExecuteBT(e, bt).Action.Done()
+	btr.ExecuteBT(e, bt)
+	expected := strings.Join([]string{
+		"> Utility",
+		"    rest",
+		"  > plant",
+		"  > Sequence [haveYoke ok]",
+		"    + yokeOx",
+		"    > plowField {furrows: 3, rows: [1 2]}",
+		"",
+	}, "\n")
+	if dump := btr.DumpBT(e, bt); dump != expected {
+		t.Fatalf("unexpected dump:\n%s", dump)
+	}
+}
@@ -0,0 +1,514 @@
+package sameriver
+
+import (
+	"math/rand"
+	"sort"
+)
+
+/*
+Tick-based behaviour trees. A tree run with BTRunner.TickBT() is ticked from
+the root each frame, and each node it runs returns a BTStatus: running (call
+me again next tick), success or failure. Composites and decorators are
+built from the constructors below (BTSequence(), BTRetry(), ...) rather than
+by hand with Selector / CompletionPredicate, and leaves are BTAction()s and
+BTCondition()s, eg.
+
+	NewBehaviourTree("woodcutter", BTSelector("root",
+		BTConditionalAbort("flee", BT_ABORT_LOWER_PRIORITY, wolvesNear,
+			BTAction("run", runAway)),
+		BTSequence("work",
+			BTAction("getAxe", getAxe),
+			BTRepeat(3, BTAction("chop", chop)),
+		),
+	))
+
+A running node is entered (Enter() called) on the tick it's first run, and
+exited (Exit() called with its final status) when it succeeds or fails, or
+when it's interrupted: a composite moving on, a conditional abort, or
+BehaviourTree.Abort() halt the running nodes below them, deepest first,
+calling Exit() with BT_RUNNING.
+
+To bridge to the older style, a node with no Tick that names a tree in the
+runner ticks that tree's root, and any other node with no Tick runs until
+Done() or SetFailed(true) is called on it. A node's Guard and decorator
+strings (see BTRunner.RegisterDecorators()) are run when it's entered,
+failing it if any return false.
+*/
+
+type BTStatus int
+
+const (
+	BT_RUNNING BTStatus = iota
+	BT_SUCCESS
+	BT_FAILURE
+)
+
+func (s BTStatus) String() string {
+	switch s {
+	case BT_RUNNING:
+		return "running"
+	case BT_SUCCESS:
+		return "success"
+	case BT_FAILURE:
+		return "failure"
+	}
+	return "unknown"
+}
+
+// BTAbortMode says when a BTConditionalAbort() re-checks its condition
+type BTAbortMode int
+
+const (
+	// only check the condition when entered
+	BT_ABORT_NONE BTAbortMode = iota
+	// check each tick while running, failing (and halting the child) once
+	// it's false
+	BT_ABORT_SELF
+	// while a later sibling is running under a selector or priority node,
+	// check each tick, and once it's true, halt the sibling and run this
+	BT_ABORT_LOWER_PRIORITY
+	BT_ABORT_BOTH
+)
+
+// BTContext is given to each node's Tick
+type BTContext struct {
+	Entity *Entity
+	Runner *BTRunner
+	Tree   *BehaviourTree
+	Dt_ms  float64
+}
+
+// the state kept by the tick engine and built-in nodes; reset when a node
+// is entered
+type btTickState struct {
+	running bool
+	// the child index a composite is on, or the order of a random node
+	ix    int
+	order []int
+	// repeats / retries done
+	count int
+	// tree time the node was entered, and (for cooldowns) can next run
+	entered_ms float64
+	ready_ms   float64
+	// the root of a named tree a leaf refers to, while it's running it
+	ref *BTNode
+	// a built-in node's own reset on enter, and cleanup on exit
+	enter func(self *BTNode)
+	exit  func(self *BTNode, status BTStatus)
+	// for conditional aborts
+	abortMode BTAbortMode
+	cond      func(self *BTNode, ctx *BTContext) bool
+}
+
+// TickBT ticks the tree for e, returning the root's status. Once the root
+// succeeds or fails, the next tick runs it again from the start
+func (btr *BTRunner) TickBT(e *Entity, bt *BehaviourTree, dt_ms float64) BTStatus {
+	bt.run++
+	bt.time_ms += dt_ms
+	if bt.Root == nil {
+		return BT_FAILURE
+	}
+	ctx := &BTContext{Entity: e, Runner: btr, Tree: bt, Dt_ms: dt_ms}
+	return bt.Root.tickNode(ctx)
+}
+
+// Abort halts the running nodes of the tree, calling their Exit() hooks
+func (bt *BehaviourTree) Abort() {
+	if bt.Root != nil {
+		bt.Root.halt()
+	}
+}
+
+// Running returns whether the node was running as of its last tick
+func (n *BTNode) Running() bool {
+	return n.tick.running
+}
+
+// RunningPath returns the dotted names of the running nodes from the root
+// down, eg. "root.work.getAxe" (like BTExecState.Path)
+func (bt *BehaviourTree) RunningPath() string {
+	path := ""
+	n := bt.Root
+	for n != nil && n.tick.running {
+		if path != "" {
+			path += "."
+		}
+		path += n.Name
+		next := n.tick.ref
+		for _, ch := range n.Children {
+			if ch.tick.running {
+				next = ch
+				break
+			}
+		}
+		n = next
+	}
+	return path
+}
+
+func (n *BTNode) tickNode(ctx *BTContext) BTStatus {
+	if n.Tree == nil {
+		n.Tree = ctx.Tree
+	}
+	if !n.tick.running {
+		n.tick = btTickState{
+			running:    true,
+			entered_ms: ctx.Tree.time_ms,
+			ready_ms:   n.tick.ready_ms,
+			enter:      n.tick.enter,
+			exit:       n.tick.exit,
+			abortMode:  n.tick.abortMode,
+			cond:       n.tick.cond,
+		}
+		n.Complete = false
+		n.Failed = false
+		if n.tick.enter != nil {
+			n.tick.enter(n)
+		}
+		if n.Enter != nil {
+			n.Enter(n)
+		}
+		if n.Guard != nil && !n.Guard(n, ctx) {
+			return n.finish(BT_FAILURE)
+		}
+		for _, dstr := range n.Decorators {
+			dec, ok := ctx.Runner.decorators[dstr]
+			if !ok {
+				panic("Unknown decorator: " + dstr)
+			}
+			if !dec(n) {
+				return n.finish(BT_FAILURE)
+			}
+		}
+	}
+	var status BTStatus
+	switch {
+	case n.Tick != nil:
+		status = n.Tick(n, ctx)
+	case ctx.Runner.trees[n.Name] != nil && ctx.Runner.trees[n.Name].Root != n:
+		n.tick.ref = ctx.Runner.trees[n.Name].Root
+		status = n.tick.ref.tickNode(ctx)
+	case n.Failed:
+		status = BT_FAILURE
+	case n.Complete:
+		status = BT_SUCCESS
+	default:
+		status = BT_RUNNING
+	}
+	if status != BT_RUNNING {
+		return n.finish(status)
+	}
+	n.Status = status
+	return status
+}
+
+func (n *BTNode) finish(status BTStatus) BTStatus {
+	n.Status = status
+	n.tick.running = false
+	n.tick.ref = nil
+	if n.tick.exit != nil {
+		n.tick.exit(n, status)
+	}
+	if n.Exit != nil {
+		n.Exit(n, status)
+	}
+	return status
+}
+
+// stop the node if it's running, halting its running descendants first
+func (n *BTNode) halt() {
+	if !n.tick.running {
+		return
+	}
+	for _, ch := range n.Children {
+		ch.halt()
+	}
+	if n.tick.ref != nil {
+		n.tick.ref.halt()
+	}
+	n.finish(BT_RUNNING)
+}
+
+func (n *BTNode) haltChildren() {
+	for _, ch := range n.Children {
+		ch.halt()
+	}
+}
+
+// btNode makes a built-in node and sets its children's parents
+func btNode(name string, tick func(self *BTNode, ctx *BTContext) BTStatus, children ...*BTNode) *BTNode {
+	n := &BTNode{
+		Name:     name,
+		State:    make(map[string]any),
+		Tick:     tick,
+		Children: children,
+	}
+	for _, ch := range children {
+		ch.Parent = n
+	}
+	return n
+}
+
+//
+// leaves
+//
+
+// BTAction is a leaf running f each tick until it succeeds or fails
+func BTAction(name string, f func(self *BTNode, ctx *BTContext) BTStatus) *BTNode {
+	return btNode(name, f)
+}
+
+// BTCondition is a leaf succeeding or failing immediately by cond
+func BTCondition(name string, cond func(self *BTNode, ctx *BTContext) bool) *BTNode {
+	return btNode(name, func(self *BTNode, ctx *BTContext) BTStatus {
+		if cond(self, ctx) {
+			return BT_SUCCESS
+		}
+		return BT_FAILURE
+	})
+}
+
+//
+// composites
+//
+
+// BTSequence runs its children in order, failing as soon as one fails and
+// succeeding once all have
+func BTSequence(name string, children ...*BTNode) *BTNode {
+	return btNode(name, func(self *BTNode, ctx *BTContext) BTStatus {
+		for self.tick.ix < len(self.Children) {
+			switch self.Children[self.tick.ix].tickNode(ctx) {
+			case BT_RUNNING:
+				return BT_RUNNING
+			case BT_FAILURE:
+				return BT_FAILURE
+			}
+			self.tick.ix++
+		}
+		return BT_SUCCESS
+	}, children...)
+}
+
+// BTSelector runs its children in order until one succeeds, failing if
+// none do. A BTConditionalAbort() child with BT_ABORT_LOWER_PRIORITY
+// interrupts a later running child once its condition holds
+func BTSelector(name string, children ...*BTNode) *BTNode {
+	return btNode(name, func(self *BTNode, ctx *BTContext) BTStatus {
+		if ix := btLowerPriorityAbort(self, self.tick.ix, ctx); ix >= 0 {
+			self.haltChildren()
+			self.tick.ix = ix
+		}
+		for self.tick.ix < len(self.Children) {
+			switch self.Children[self.tick.ix].tickNode(ctx) {
+			case BT_RUNNING:
+				return BT_RUNNING
+			case BT_SUCCESS:
+				return BT_SUCCESS
+			}
+			self.tick.ix++
+		}
+		return BT_FAILURE
+	}, children...)
+}
+
+// the first of the children before `before` which would abort lower
+// priorities now, or -1
+func btLowerPriorityAbort(n *BTNode, before int, ctx *BTContext) int {
+	for i := 0; i < before && i < len(n.Children); i++ {
+		ch := n.Children[i]
+		mode := ch.tick.abortMode
+		if (mode == BT_ABORT_LOWER_PRIORITY || mode == BT_ABORT_BOTH) && ch.tick.cond(ch, ctx) {
+			return i
+		}
+	}
+	return -1
+}
+
+// BTParallel ticks all its running children each tick, succeeding once
+// successes of them have succeeded and failing once that's no longer
+// possible (halting any still running). successes <= 0 means all
+func BTParallel(name string, successes int, children ...*BTNode) *BTNode {
+	return btNode(name, func(self *BTNode, ctx *BTContext) BTStatus {
+		need := successes
+		if need <= 0 || need > len(self.Children) {
+			need = len(self.Children)
+		}
+		succeeded, failed := 0, 0
+		for _, ch := range self.Children {
+			status := ch.Status
+			if ch.tick.running || self.tick.count == 0 {
+				status = ch.tickNode(ctx)
+			}
+			switch status {
+			case BT_SUCCESS:
+				succeeded++
+			case BT_FAILURE:
+				failed++
+			}
+		}
+		self.tick.count++
+		switch {
+		case succeeded >= need:
+			self.haltChildren()
+			return BT_SUCCESS
+		case failed > len(self.Children)-need:
+			self.haltChildren()
+			return BT_FAILURE
+		}
+		return BT_RUNNING
+	}, children...)
+}
+
+// BTRandom is a selector trying its children in a random order (shuffled
+// each time it's entered)
+func BTRandom(name string, children ...*BTNode) *BTNode {
+	n := btNode(name, func(self *BTNode, ctx *BTContext) BTStatus {
+		for self.tick.ix < len(self.tick.order) {
+			switch self.Children[self.tick.order[self.tick.ix]].tickNode(ctx) {
+			case BT_RUNNING:
+				return BT_RUNNING
+			case BT_SUCCESS:
+				return BT_SUCCESS
+			}
+			self.tick.ix++
+		}
+		return BT_FAILURE
+	}, children...)
+	n.tick.enter = func(self *BTNode) {
+		self.tick.order = rand.Perm(len(self.Children))
+	}
+	return n
+}
+
+// BTPriority is a selector which each tick tries its children in order of
+// descending priority, switching (and halting the running child) whenever
+// a higher-priority child would now run
+func BTPriority(
+	name string, priority func(child *BTNode, ctx *BTContext) float64, children ...*BTNode) *BTNode {
+
+	return btNode(name, func(self *BTNode, ctx *BTContext) BTStatus {
+		order := make([]*BTNode, len(self.Children))
+		copy(order, self.Children)
+		priorities := make(map[*BTNode]float64, len(order))
+		for _, ch := range order {
+			priorities[ch] = priority(ch, ctx)
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return priorities[order[i]] > priorities[order[j]]
+		})
+		for _, ch := range order {
+			if !ch.tick.running {
+				// a different child is about to run; stop the old one
+				for _, other := range self.Children {
+					if other != ch && other.tick.running {
+						other.halt()
+					}
+				}
+			}
+			switch ch.tickNode(ctx) {
+			case BT_RUNNING:
+				return BT_RUNNING
+			case BT_SUCCESS:
+				return BT_SUCCESS
+			}
+		}
+		return BT_FAILURE
+	}, children...)
+}
+
+//
+// decorators
+//
+
+// BTInverter swaps its child's success and failure
+func BTInverter(child *BTNode) *BTNode {
+	return btNode("Inverter", func(self *BTNode, ctx *BTContext) BTStatus {
+		switch self.Children[0].tickNode(ctx) {
+		case BT_SUCCESS:
+			return BT_FAILURE
+		case BT_FAILURE:
+			return BT_SUCCESS
+		}
+		return BT_RUNNING
+	}, child)
+}
+
+// BTRepeat runs its child n times (forever if n <= 0), failing if it fails
+func BTRepeat(n int, child *BTNode) *BTNode {
+	return btNode("Repeat", func(self *BTNode, ctx *BTContext) BTStatus {
+		switch self.Children[0].tickNode(ctx) {
+		case BT_FAILURE:
+			return BT_FAILURE
+		case BT_SUCCESS:
+			self.tick.count++
+			if n > 0 && self.tick.count >= n {
+				return BT_SUCCESS
+			}
+		}
+		return BT_RUNNING
+	}, child)
+}
+
+// BTRetry runs its child until it succeeds, up to n times (forever if
+// n <= 0), failing if it never does
+func BTRetry(n int, child *BTNode) *BTNode {
+	return btNode("Retry", func(self *BTNode, ctx *BTContext) BTStatus {
+		switch self.Children[0].tickNode(ctx) {
+		case BT_SUCCESS:
+			return BT_SUCCESS
+		case BT_FAILURE:
+			self.tick.count++
+			if n > 0 && self.tick.count >= n {
+				return BT_FAILURE
+			}
+		}
+		return BT_RUNNING
+	}, child)
+}
+
+// BTTimeLimit fails (halting its child) if the child runs for longer than
+// limit_ms of tree time
+func BTTimeLimit(limit_ms float64, child *BTNode) *BTNode {
+	return btNode("TimeLimit", func(self *BTNode, ctx *BTContext) BTStatus {
+		if ctx.Tree.time_ms-self.tick.entered_ms > limit_ms {
+			self.haltChildren()
+			return BT_FAILURE
+		}
+		return self.Children[0].tickNode(ctx)
+	}, child)
+}
+
+// BTCooldown fails without running its child until cooldown_ms of tree
+// time after the child last finished
+func BTCooldown(cooldown_ms float64, child *BTNode) *BTNode {
+	return btNode("Cooldown", func(self *BTNode, ctx *BTContext) BTStatus {
+		if !self.Children[0].tick.running && ctx.Tree.time_ms < self.tick.ready_ms {
+			return BT_FAILURE
+		}
+		status := self.Children[0].tickNode(ctx)
+		if status != BT_RUNNING {
+			self.tick.ready_ms = ctx.Tree.time_ms + cooldown_ms
+		}
+		return status
+	}, child)
+}
+
+// BTConditionalAbort runs its child while cond holds, failing if it
+// doesn't when entered, and re-checking it as given by mode (see
+// BTAbortMode)
+func BTConditionalAbort(
+	name string, mode BTAbortMode, cond func(self *BTNode, ctx *BTContext) bool, child *BTNode) *BTNode {
+
+	n := btNode(name, func(self *BTNode, ctx *BTContext) BTStatus {
+		checks := !self.Children[0].tick.running ||
+			mode == BT_ABORT_SELF || mode == BT_ABORT_BOTH
+		if checks && !cond(self, ctx) {
+			self.haltChildren()
+			return BT_FAILURE
+		}
+		return self.Children[0].tickNode(ctx)
+	}, child)
+	n.tick.abortMode = mode
+	n.tick.cond = cond
+	return n
+}
@@ -0,0 +1,244 @@
+package sameriver
+
+import (
+	"strings"
+	"testing"
+)
+
+// a leaf running for ticks ticks then finishing with status, logging its
+// enters and exits
+func testingBTLeaf(name string, ticks int, status BTStatus, log *[]string) *BTNode {
+	n := BTAction(name, func(self *BTNode, ctx *BTContext) BTStatus {
+		self.State["ticks"] = self.State["ticks"].(int) + 1
+		if self.State["ticks"].(int) >= ticks {
+			return status
+		}
+		return BT_RUNNING
+	})
+	n.Enter = func(self *BTNode) {
+		self.State["ticks"] = 0
+		*log = append(*log, "enter "+name)
+	}
+	n.Exit = func(self *BTNode, status BTStatus) {
+		*log = append(*log, "exit "+name+" "+status.String())
+	}
+	return n
+}
+
+func TestBTTickSequenceSelector(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	btr := NewBTRunner()
+	log := make([]string, 0)
+	bt := NewBehaviourTree("root", BTSelector("root",
+		BTSequence("work",
+			testingBTLeaf("getAxe", 2, BT_SUCCESS, &log),
+			testingBTLeaf("chop", 1, BT_FAILURE, &log),
+		),
+		testingBTLeaf("idle", 1, BT_SUCCESS, &log),
+	))
+	if status := btr.TickBT(e, bt, FRAME_MS); status != BT_RUNNING ||
+		bt.RunningPath() != "root.work.getAxe" {
+		t.Fatalf("should be running getAxe, got %s at %s", status, bt.RunningPath())
+	}
+	if status := btr.TickBT(e, bt, FRAME_MS); status != BT_SUCCESS {
+		t.Fatalf("chop failing should make the selector fall back to idle, got %s", status)
+	}
+	expected := []string{
+		"enter getAxe", "exit getAxe success",
+		"enter chop", "exit chop failure",
+		"enter idle", "exit idle success",
+	}
+	if strings.Join(log, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v, got %v", expected, log)
+	}
+	if bt.RunningPath() != "" || bt.Root.Status != BT_SUCCESS {
+		t.Fatal("tree should be done")
+	}
+}
+
+func TestBTTickParallelRandomPriority(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	btr := NewBTRunner()
+	log := make([]string, 0)
+
+	// one success is enough, halting the other
+	bt := NewBehaviourTree("parallel", BTParallel("both", 1,
+		testingBTLeaf("walk", 5, BT_SUCCESS, &log),
+		testingBTLeaf("whistle", 2, BT_SUCCESS, &log),
+	))
+	btr.TickBT(e, bt, FRAME_MS)
+	if status := btr.TickBT(e, bt, FRAME_MS); status != BT_SUCCESS {
+		t.Fatalf("parallel should succeed when whistle does, got %s", status)
+	}
+	if log[len(log)-1] != "exit walk running" {
+		t.Fatalf("walk should have been halted, log %v", log)
+	}
+
+	seen := make(map[string]bool)
+	leaves := []*BTNode{
+		BTCondition("a", func(self *BTNode, ctx *BTContext) bool { seen["a"] = true; return false }),
+		BTCondition("b", func(self *BTNode, ctx *BTContext) bool { seen["b"] = true; return false }),
+		BTCondition("c", func(self *BTNode, ctx *BTContext) bool { seen["c"] = true; return false }),
+	}
+	bt = NewBehaviourTree("random", BTRandom("random", leaves...))
+	if status := btr.TickBT(e, bt, FRAME_MS); status != BT_FAILURE || len(seen) != 3 {
+		t.Fatalf("random should try every child before failing, got %s after %v", status, seen)
+	}
+
+	log = log[:0]
+	urgency := map[string]float64{"eat": 1, "sleep": 0}
+	bt = NewBehaviourTree("priority", BTPriority("needs",
+		func(child *BTNode, ctx *BTContext) float64 { return urgency[child.Name] },
+		testingBTLeaf("eat", 10, BT_SUCCESS, &log),
+		testingBTLeaf("sleep", 10, BT_SUCCESS, &log),
+	))
+	btr.TickBT(e, bt, FRAME_MS)
+	urgency["sleep"] = 2
+	btr.TickBT(e, bt, FRAME_MS)
+	if bt.RunningPath() != "needs.sleep" || log[1] != "exit eat running" {
+		t.Fatalf("should switch to sleep, halting eat, at %s, log %v", bt.RunningPath(), log)
+	}
+}
+
+func TestBTTickDecorators(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	btr := NewBTRunner()
+	log := make([]string, 0)
+	tick := func(bt *BehaviourTree, n int) BTStatus {
+		var status BTStatus
+		for i := 0; i < n; i++ {
+			status = btr.TickBT(e, bt, 100)
+		}
+		return status
+	}
+
+	bt := NewBehaviourTree("invert", BTInverter(testingBTLeaf("fail", 1, BT_FAILURE, &log)))
+	if tick(bt, 1) != BT_SUCCESS {
+		t.Fatal("inverter should turn failure into success")
+	}
+
+	chops := 0
+	chop := BTAction("chop", func(self *BTNode, ctx *BTContext) BTStatus {
+		chops++
+		return BT_SUCCESS
+	})
+	bt = NewBehaviourTree("repeat", BTRepeat(3, chop))
+	if tick(bt, 2) != BT_RUNNING || tick(bt, 1) != BT_SUCCESS || chops != 3 {
+		t.Fatalf("repeat should succeed after 3 chops, chopped %d", chops)
+	}
+
+	attempts := 0
+	flaky := BTAction("flaky", func(self *BTNode, ctx *BTContext) BTStatus {
+		attempts++
+		if attempts == 3 {
+			return BT_SUCCESS
+		}
+		return BT_FAILURE
+	})
+	bt = NewBehaviourTree("retry", BTRetry(2, flaky))
+	if tick(bt, 2) != BT_FAILURE {
+		t.Fatal("retry should fail after 2 failures")
+	}
+	if tick(bt, 1) != BT_SUCCESS {
+		t.Fatal("retry should succeed when the child does")
+	}
+
+	log = log[:0]
+	bt = NewBehaviourTree("timelimit", BTTimeLimit(250, testingBTLeaf("slow", 10, BT_SUCCESS, &log)))
+	if tick(bt, 3) != BT_RUNNING || tick(bt, 1) != BT_FAILURE || log[1] != "exit slow running" {
+		t.Fatalf("time limit should halt the child after 250 ms, log %v", log)
+	}
+
+	runs := 0
+	bt = NewBehaviourTree("cooldown", BTCooldown(300, BTAction("shout", func(self *BTNode, ctx *BTContext) BTStatus {
+		runs++
+		return BT_SUCCESS
+	})))
+	if tick(bt, 1) != BT_SUCCESS || tick(bt, 1) != BT_FAILURE {
+		t.Fatal("cooldown should fail right after the child ran")
+	}
+	if tick(bt, 1) != BT_FAILURE || tick(bt, 1) != BT_SUCCESS || runs != 2 {
+		t.Fatalf("cooldown should allow the child again after 300 ms, ran %d", runs)
+	}
+}
+
+func TestBTTickConditionalAbort(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	btr := NewBTRunner()
+	log := make([]string, 0)
+	wolves, tired := false, false
+	bt := NewBehaviourTree("root", BTSelector("root",
+		BTConditionalAbort("danger", BT_ABORT_LOWER_PRIORITY,
+			func(self *BTNode, ctx *BTContext) bool { return wolves },
+			testingBTLeaf("flee", 2, BT_SUCCESS, &log)),
+		BTConditionalAbort("awake", BT_ABORT_SELF,
+			func(self *BTNode, ctx *BTContext) bool { return !tired },
+			testingBTLeaf("work", 10, BT_SUCCESS, &log)),
+		testingBTLeaf("sleep", 1, BT_SUCCESS, &log),
+	))
+	btr.TickBT(e, bt, FRAME_MS)
+	if bt.RunningPath() != "root.awake.work" {
+		t.Fatalf("should work, at %s", bt.RunningPath())
+	}
+	wolves = true
+	btr.TickBT(e, bt, FRAME_MS)
+	if bt.RunningPath() != "root.danger.flee" {
+		t.Fatalf("wolves should abort work to flee, at %s", bt.RunningPath())
+	}
+	wolves = false
+	btr.TickBT(e, bt, FRAME_MS)
+	btr.TickBT(e, bt, FRAME_MS)
+	tired = true
+	if status := btr.TickBT(e, bt, FRAME_MS); status != BT_SUCCESS {
+		t.Fatalf("tiredness should abort work and fall back to sleep, got %s", status)
+	}
+	expected := []string{
+		"enter work", "exit work running",
+		"enter flee", "exit flee success",
+		"enter work", "exit work running",
+		"enter sleep", "exit sleep success",
+	}
+	if strings.Join(log, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v, got %v", expected, log)
+	}
+
+	// interrupting the tree exits the running nodes, deepest first
+	exits := make([]string, 0)
+	bt = NewBehaviourTree("interrupt", BTSequence("outer", BTSequence("inner",
+		testingBTLeaf("long", 10, BT_SUCCESS, &log))))
+	for _, n := range []*BTNode{bt.Root, bt.Root.Children[0]} {
+		n.Exit = func(self *BTNode, status BTStatus) { exits = append(exits, self.Name+" "+status.String()) }
+	}
+	btr.TickBT(e, bt, FRAME_MS)
+	bt.Abort()
+	if strings.Join(exits, ",") != "inner running,outer running" || bt.RunningPath() != "" {
+		t.Fatalf("abort should exit inner then outer, got %v", exits)
+	}
+}
+
+func TestBTTickBridge(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(nil)
+	btr := NewBTRunner()
+	btr.RegisterDecorators([]BTDecorator{
+		{Name: "never", Impl: func(self *BTNode) bool { return false }},
+	})
+	// a named subtree, and an old-style leaf finished with Done()
+	chores := NewBehaviourTree("chores", BTSequence("chores", &BTNode{Name: "sweep"}))
+	btr.RegisterTree(chores)
+	bt := NewBehaviourTree("root", BTSelector("root",
+		&BTNode{Name: "guarded", Decorators: []string{"never"}},
+		&BTNode{Name: "chores"},
+	))
+	if btr.TickBT(e, bt, FRAME_MS) != BT_RUNNING || bt.RunningPath() != "root.chores.chores.sweep" {
+		t.Fatalf("should be running the sweep leaf of the chores tree, at %s", bt.RunningPath())
+	}
+	chores.Root.Children[0].Done()
+	if btr.TickBT(e, bt, FRAME_MS) != BT_SUCCESS {
+		t.Fatal("Done() should make the leaf succeed")
+	}
+}
@@ -0,0 +1,218 @@
+/*
+sameriver-efdsl-lint checks the EFDSL expressions embedded in JSON and YAML
+data files (such as behaviour trees, see BTRunner.LoadTrees() and
+LoadTreesFile()) before they're run, reporting each problem with where it
+is in the data and in the expression.
+
+	sameriver-efdsl-lint [-keys if,query] [-components position,needs] [-states hunger,mood] files or dirs...
+
+The expressions are the strings under the given keys, at any depth.
+Directories are searched for .json, .yaml and .yml files. A "$param" in an
+expression (as bt data substitutes) is checked as the identifier param.
+Only the base predicates, sorts and spatial clauses are known; components
+and state keys are only checked if listed. The exit status is 1 if any
+problems are found.
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aiur-adept/sameriver/v7"
+	"gopkg.in/yaml.v3"
+)
+
+var paramRe = regexp.MustCompile(`\$([A-Za-z0-9_]+)`)
+
+type problem struct {
+	file string
+	path string
+	expr string
+	err  *sameriver.EFDSLError
+}
+
+func main() {
+	keys := flag.String("keys", "if,query,filter,expr", "comma-separated keys whose string values are EFDSL expressions")
+	components := flag.String("components", "", "comma-separated component names accessors may use (default: don't check)")
+	states := flag.String("states", "", "comma-separated state keys accessors may use (default: don't check)")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: sameriver-efdsl-lint [flags] files or dirs...")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	v := sameriver.NewEFDSLValidator()
+	if *components != "" {
+		v.Components = set(*components)
+		// components are registered in upper case
+		for name := range v.Components {
+			v.Components[strings.ToUpper(name)] = true
+		}
+	}
+	if *states != "" {
+		v.States = set(*states)
+	}
+	exprKeys := set(*keys)
+
+	files, err := dataFiles(flag.Args())
+	if err != nil {
+		fail(err)
+	}
+	problems := make([]problem, 0)
+	for _, file := range files {
+		data, err := load(file)
+		if err != nil {
+			fail(err)
+		}
+		walk(data, "", func(path string, expr string) {
+			for _, err := range v.Validate(paramRe.ReplaceAllString(expr, "$1")) {
+				err.Column = originalColumn(expr, err)
+				problems = append(problems, problem{file, path, expr, err})
+			}
+		}, exprKeys)
+	}
+	for _, p := range problems {
+		fmt.Printf("%s: %s: %s\n", p.file, p.path, p.err)
+		fmt.Printf("\t%s\n\t%s^\n", p.expr, caretIndent(p.expr, p.err))
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "%d problem(s)\n", len(problems))
+		os.Exit(1)
+	}
+}
+
+func set(csv string) map[string]bool {
+	result := make(map[string]bool)
+	for _, s := range strings.Split(csv, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			result[s] = true
+		}
+	}
+	return result
+}
+
+// the files given, and the data files in the dirs given, in order
+func dataFiles(args []string) ([]string, error) {
+	files := make([]string, 0)
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch filepath.Ext(path) {
+			case ".json", ".yaml", ".yml":
+				if !d.IsDir() {
+					files = append(files, path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func load(file string) (any, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var data any
+	if filepath.Ext(file) == ".json" {
+		err = json.Unmarshal(contents, &data)
+	} else {
+		err = yaml.Unmarshal(contents, &data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	return data, nil
+}
+
+// walk calls found with the path (eg. trees.villager.children[1].if) of
+// each string under one of the keys
+func walk(data any, path string, found func(path string, expr string), keys map[string]bool) {
+	switch d := data.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(d))
+		for k := range d {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			if expr, ok := d[k].(string); ok && keys[k] {
+				found(childPath, expr)
+				continue
+			}
+			walk(d[k], childPath, found, keys)
+		}
+	case []any:
+		for i, x := range d {
+			walk(x, fmt.Sprintf("%s[%d]", path, i), found, keys)
+		}
+	}
+}
+
+// the column in expr of the error in expr with its params' $ removed
+func originalColumn(expr string, err *sameriver.EFDSLError) int {
+	lines := strings.Split(expr, "\n")
+	if err.Line < 1 || err.Line > len(lines) {
+		return err.Column
+	}
+	line := []rune(lines[err.Line-1])
+	column := 1
+	for i, rewritten := 0, 1; i < len(line) && rewritten < err.Column; i++ {
+		column++
+		if line[i] == '$' && i+1 < len(line) && paramRe.MatchString(string(line[i:i+2])) {
+			continue
+		}
+		rewritten++
+	}
+	return column
+}
+
+// whitespace up to the error's column on its line, keeping tabs
+func caretIndent(expr string, err *sameriver.EFDSLError) string {
+	lines := strings.Split(expr, "\n")
+	if err.Line < 1 || err.Line > len(lines) {
+		return ""
+	}
+	line := []rune(lines[err.Line-1])
+	var indent strings.Builder
+	for i := 0; i < err.Column-1 && i < len(line); i++ {
+		if line[i] == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+	return indent.String()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(2)
+}
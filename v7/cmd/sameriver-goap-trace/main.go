@@ -0,0 +1,302 @@
+/*
+sameriver-goap-trace renders a GOAP planner trace (see GOAPTrace, recorded
+with GOAPPlanner.SetTracing(true) and exported with GOAPTrace.JSON()) as a
+search tree, for inspecting why a plan was or wasn't found.
+
+	sameriver-goap-trace [-format html|svg|dot] [-o out] [-skip pruned,queued] trace.json
+
+With no trace file, the trace is read from stdin.
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aiur-adept/sameriver/v7"
+)
+
+const (
+	nodeW   = 240
+	nodeH   = 64
+	gapX    = 24
+	gapY    = 48
+	margin  = 20
+	maxText = 34
+)
+
+var outcomeFills = map[string]string{
+	sameriver.GOAP_TRACE_QUEUED:   "#e0e0e0",
+	sameriver.GOAP_TRACE_EXPANDED: "#add8e6",
+	sameriver.GOAP_TRACE_SOLUTION: "#98fb98",
+	sameriver.GOAP_TRACE_REJECTED: "#fa8072",
+	sameriver.GOAP_TRACE_PRUNED:   "#ffffff",
+}
+
+type layoutNode struct {
+	n        *sameriver.GOAPTraceNode
+	children []*layoutNode
+	x, y     float64
+}
+
+func main() {
+	format := flag.String("format", "html", "output format: html, svg or dot")
+	out := flag.String("o", "", "output file (default stdout)")
+	skip := flag.String("skip", "", "comma-separated outcomes of nodes to leave out (eg. pruned,queued)")
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	contents, err := io.ReadAll(in)
+	if err != nil {
+		fail(err)
+	}
+	trace, err := sameriver.LoadGOAPTraceJSON(contents)
+	if err != nil {
+		fail(err)
+	}
+	trace = withoutOutcomes(trace, *skip)
+
+	var rendered string
+	switch *format {
+	case "html":
+		rendered = renderHTML(trace)
+	case "svg":
+		rendered = renderSVG(trace)
+	case "dot":
+		rendered = trace.DOT()
+	default:
+		fail(fmt.Errorf("unknown format %s (expected html, svg, dot)", *format))
+	}
+
+	if *out == "" {
+		fmt.Print(rendered)
+		return
+	}
+	if err := os.WriteFile(*out, []byte(rendered), 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "sameriver-goap-trace: %s\n", err)
+	os.Exit(1)
+}
+
+// drop nodes with the given outcomes, and their descendants
+func withoutOutcomes(trace *sameriver.GOAPTrace, skip string) *sameriver.GOAPTrace {
+	if skip == "" {
+		return trace
+	}
+	skipped := make(map[string]bool)
+	for _, outcome := range strings.Split(skip, ",") {
+		skipped[strings.TrimSpace(outcome)] = true
+	}
+	dropped := make(map[int]bool)
+	nodes := make([]*sameriver.GOAPTraceNode, 0, len(trace.Nodes))
+	for _, n := range trace.Nodes {
+		if skipped[n.Outcome] || (n.Parent >= 0 && dropped[n.Parent]) {
+			dropped[n.ID] = true
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	result := *trace
+	result.Nodes = nodes
+	return &result
+}
+
+// lay the tree out with leaves side by side and parents centred over their
+// children, returning the roots and the size of the drawing
+func layout(trace *sameriver.GOAPTrace) (roots []*layoutNode, w, h float64) {
+	byID := make(map[int]*layoutNode)
+	for _, n := range trace.Nodes {
+		ln := &layoutNode{n: n}
+		byID[n.ID] = ln
+		if parent, ok := byID[n.Parent]; ok {
+			parent.children = append(parent.children, ln)
+		} else {
+			roots = append(roots, ln)
+		}
+	}
+	nextX := 0.0
+	maxDepth := 0
+	var place func(ln *layoutNode, depth int)
+	place = func(ln *layoutNode, depth int) {
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		ln.y = float64(margin + depth*(nodeH+gapY))
+		if len(ln.children) == 0 {
+			ln.x = margin + nextX
+			nextX += nodeW + gapX
+			return
+		}
+		for _, c := range ln.children {
+			place(c, depth+1)
+		}
+		ln.x = (ln.children[0].x + ln.children[len(ln.children)-1].x) / 2
+	}
+	for _, root := range roots {
+		place(root, 0)
+	}
+	w = 2*margin + nextX - gapX
+	h = float64(2*margin + (maxDepth+1)*(nodeH+gapY) - gapY)
+	return roots, w, h
+}
+
+func ellipsize(s string) string {
+	if len(s) > maxText {
+		return s[:maxText-1] + "…"
+	}
+	return s
+}
+
+func renderSVG(trace *sameriver.GOAPTrace) string {
+	roots, w, h := layout(trace)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="monospace" font-size="11">`+"\n", w, h)
+	var draw func(ln *layoutNode)
+	draw = func(ln *layoutNode) {
+		n := ln.n
+		for _, c := range ln.children {
+			x1, y1 := ln.x+nodeW/2, ln.y+nodeH
+			x2, y2 := c.x+nodeW/2, c.y
+			fmt.Fprintf(&buf, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#888"/>`+"\n", x1, y1, x2, y2)
+			fmt.Fprintf(&buf, `<text x="%.0f" y="%.0f" fill="#555">%s</text>`+"\n",
+				(x1+x2)/2+4, (y1+y2)/2,
+				html.EscapeString(ellipsize(fmt.Sprintf("+%s@%d", c.n.Inserted, c.n.InsertionIx))))
+			draw(c)
+		}
+		stroke, width, dash := "#333", 1, ""
+		if n.ID == trace.Best {
+			stroke, width = "#060", 3
+		}
+		if n.Outcome == sameriver.GOAP_TRACE_PRUNED {
+			dash = ` stroke-dasharray="4 3"`
+		}
+		fmt.Fprintf(&buf, `<a href="#node-%d"><g>`, n.ID)
+		fmt.Fprintf(&buf, `<title>%s</title>`, html.EscapeString(tooltip(n)))
+		fmt.Fprintf(&buf, `<rect x="%.0f" y="%.0f" width="%d" height="%d" rx="4" fill="%s" stroke="%s" stroke-width="%d"%s/>`,
+			ln.x, ln.y, nodeW, nodeH, outcomeFills[n.Outcome], stroke, width, dash)
+		lines := []string{
+			fmt.Sprintf("#%d [%s]", n.ID, strings.Join(n.Path, ",")),
+			fmt.Sprintf("cost %.2f  %s", n.Cost, n.Outcome),
+		}
+		if left := n.RemainingString(); left != "" {
+			lines = append(lines, left)
+		}
+		if n.Reason != "" {
+			lines = append(lines, n.Reason)
+		}
+		for i, line := range lines[:min(len(lines), 4)] {
+			fmt.Fprintf(&buf, `<text x="%.0f" y="%.0f">%s</text>`,
+				ln.x+6, ln.y+15+float64(i*14), html.EscapeString(ellipsize(line)))
+		}
+		buf.WriteString("</g></a>\n")
+	}
+	for _, root := range roots {
+		draw(root)
+	}
+	buf.WriteString("</svg>\n")
+	return buf.String()
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func tooltip(n *sameriver.GOAPTraceNode) string {
+	s := fmt.Sprintf("#%d [%s]\ncost %.2f\n%s", n.ID, strings.Join(n.Path, ","), n.Cost, n.Outcome)
+	if n.Popped >= 0 {
+		s += fmt.Sprintf(" (popped %d)", n.Popped)
+	}
+	if left := n.RemainingString(); left != "" {
+		s += "\n" + left
+	}
+	if n.Reason != "" {
+		s += "\n" + n.Reason
+	}
+	return s
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func renderHTML(trace *sameriver.GOAPTrace) string {
+	var buf bytes.Buffer
+	buf.WriteString(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>GOAP trace</title>
+<style>
+body { font-family: monospace; margin: 1em; }
+.tree { overflow: auto; border: 1px solid #ccc; margin: 1em 0; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 2px 6px; text-align: left; vertical-align: top; }
+tr:target { background: #ffffcc; }
+</style></head><body>
+`)
+	fmt.Fprintf(&buf, "<h1>GOAP trace for entity %d</h1>\n", trace.Entity)
+	buf.WriteString("<p>start: ")
+	for _, k := range sortedKeys(trace.Start) {
+		fmt.Fprintf(&buf, "%s=%g ", html.EscapeString(k), trace.Start[k])
+	}
+	buf.WriteString("<br>goal: ")
+	for i, region := range trace.Goal {
+		if i > 0 {
+			buf.WriteString(" then ")
+		}
+		vars := make([]string, 0, len(region))
+		for _, k := range sortedKeys(region) {
+			vars = append(vars, k+" "+region[k])
+		}
+		fmt.Fprintf(&buf, "{%s}", html.EscapeString(strings.Join(vars, ", ")))
+	}
+	fmt.Fprintf(&buf, "<br>%d iterations (max %d), %d nodes", trace.Iterations, trace.MaxIter, len(trace.Nodes))
+	if trace.Best >= 0 {
+		fmt.Fprintf(&buf, `, best: <a href="#node-%d">#%d</a>`, trace.Best, trace.Best)
+	} else {
+		buf.WriteString(", no solution")
+	}
+	buf.WriteString("</p>\n<div class=\"tree\">\n")
+	buf.WriteString(renderSVG(trace))
+	buf.WriteString("</div>\n<table>\n<tr><th>id</th><th>parent</th><th>inserted</th><th>path</th><th>cost</th><th>popped</th><th>outcome</th><th>regionOffsets</th><th>remaining</th><th>reason</th></tr>\n")
+	for _, n := range trace.Nodes {
+		popped := ""
+		if n.Popped >= 0 {
+			popped = fmt.Sprint(n.Popped)
+		}
+		fmt.Fprintf(&buf, `<tr id="node-%d"><td>%d</td><td>%d</td><td>%s@%d</td><td>[%s]</td><td>%.2f</td><td>%s</td><td style="background:%s">%s</td><td>%v</td><td>%s</td><td>%s</td></tr>`+"\n",
+			n.ID, n.ID, n.Parent,
+			html.EscapeString(n.Inserted), n.InsertionIx,
+			html.EscapeString(strings.Join(n.Path, ",")),
+			n.Cost, popped,
+			outcomeFills[n.Outcome], n.Outcome,
+			n.RegionOffsets,
+			html.EscapeString(n.RemainingString()),
+			html.EscapeString(n.Reason))
+	}
+	buf.WriteString("</table>\n</body></html>\n")
+	return buf.String()
+}
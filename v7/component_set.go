@@ -23,6 +23,7 @@ type ComponentSet struct {
 	stringMapMap       map[ComponentID]StringMap
 	itemMap            map[ComponentID]Item
 	inventoryMap       map[ComponentID]Inventory
+	pathMap            map[ComponentID]Path
 }
 
 // takes as componentSpecs a map whose keys are components specified by {kind},{name}
@@ -138,6 +139,13 @@ func (ct *ComponentTable) makeComponentSet(componentSpecs map[ComponentID]any) C
 				}
 				cs.inventoryMap[name] = m
 			}
+		case PATH:
+			if p, ok := value.(Path); ok {
+				if cs.pathMap == nil {
+					cs.pathMap = make(map[ComponentID]Path)
+				}
+				cs.pathMap[name] = p
+			}
 		}
 	}
 	return cs
@@ -23,6 +23,7 @@ var componentKindStrings = map[ComponentKind]string{
 	STRINGMAP:       "STRINGMAP",
 	ITEM:            "ITEM",
 	INVENTORY:       "INVENTORY",
+	PATH:            "PATH",
 }
 
 type ComponentTable struct {
@@ -54,6 +55,7 @@ type ComponentTable struct {
 	StringMapMap       map[ComponentID][]StringMap       `json:"stringMapMap"`
 	ItemMap            map[ComponentID][]Item            `json:"itemMap"`
 	InventoryMap       map[ComponentID][]Inventory       `json:"inventoryMap"`
+	PathMap            map[ComponentID][]Path            `json:"pathMap"`
 }
 
 func NewComponentTable(capacity int) ComponentTable {
@@ -83,6 +85,7 @@ func NewComponentTable(capacity int) ComponentTable {
 		StringMapMap:       make(map[ComponentID][]StringMap),
 		ItemMap:            make(map[ComponentID][]Item),
 		InventoryMap:       make(map[ComponentID][]Inventory),
+		PathMap:            make(map[ComponentID][]Path),
 	}
 }
 
@@ -159,6 +162,11 @@ func (ct *ComponentTable) expand(n int) {
 		extraSpace := make([]Inventory, n)
 		ct.InventoryMap[name] = append(slice, extraSpace...)
 	}
+	for name, slice := range ct.PathMap {
+		Logger.Printf("Expanding table of component %s,%s", componentKindStrings[ct.Kinds[name]], ct.Strings[name])
+		extraSpace := make([]Path, n)
+		ct.PathMap[name] = append(slice, extraSpace...)
+	}
 	// expand ComponentBitArrays
 	ct.ComponentStrings = append(ct.ComponentStrings, make([]map[string]bool, n)...)
 	ct.ComponentBitArrays = append(ct.ComponentBitArrays, make([]bitarray.BitArray, n)...)
@@ -222,6 +230,8 @@ func (ct *ComponentTable) addComponent(kind ComponentKind, name ComponentID, str
 		ct.ItemMap[name] = make([]Item, ct.Capacity, 2*ct.Capacity)
 	case INVENTORY:
 		ct.InventoryMap[name] = make([]Inventory, ct.Capacity, 2*ct.Capacity)
+	case PATH:
+		ct.PathMap[name] = make([]Path, ct.Capacity, 2*ct.Capacity)
 	default:
 		panic(fmt.Sprintf("added component of kind %s has no case in component_table.go", componentKindStrings[kind]))
 	}
@@ -301,6 +311,11 @@ func (ct *ComponentTable) AssertValidComponentSet(cs ComponentSet) {
 			panic(fmt.Sprintf("%s not found in inventoryMap - maybe not registered yet?", ct.Strings[name]))
 		}
 	}
+	for name := range cs.pathMap {
+		if _, ok := ct.PathMap[name]; !ok {
+			panic(fmt.Sprintf("%s not found in pathMap - maybe not registered yet?", ct.Strings[name]))
+		}
+	}
 }
 
 func (ct *ComponentTable) ApplyComponentSet(e *Entity, spec map[ComponentID]any) {
@@ -380,6 +395,11 @@ func (ct *ComponentTable) applyComponentSet(e *Entity, cs ComponentSet) {
 		e.Components = append(e.Components, ct.Strings[name])
 		ct.ComponentStrings[e.ID][ct.Strings[name]] = true
 	}
+	for name, p := range cs.pathMap {
+		ct.PathMap[name][e.ID] = p
+		e.Components = append(e.Components, ct.Strings[name])
+		ct.ComponentStrings[e.ID][ct.Strings[name]] = true
+	}
 
 	ct.orBitArrayInto(e, ct.bitArrayFromComponentSet(cs))
 }
@@ -540,6 +560,10 @@ func (w *World) GetInventory(e *Entity, name ComponentID) *Inventory {
 	w.Em.ComponentsTable.guardInvalidComponentGet(e, name)
 	return &w.Em.ComponentsTable.InventoryMap[name][e.ID]
 }
+func (w *World) GetPath(e *Entity, name ComponentID) *Path {
+	w.Em.ComponentsTable.guardInvalidComponentGet(e, name)
+	return &w.Em.ComponentsTable.PathMap[name][e.ID]
+}
 
 func (w *World) GetVal(e *Entity, name ComponentID) any {
 	w.Em.ComponentsTable.guardInvalidComponentGet(e, name)
@@ -569,6 +593,8 @@ func (w *World) GetVal(e *Entity, name ComponentID) any {
 		return &w.Em.ComponentsTable.ItemMap[name][e.ID]
 	case INVENTORY:
 		return &w.Em.ComponentsTable.InventoryMap[name][e.ID]
+	case PATH:
+		return &w.Em.ComponentsTable.PathMap[name][e.ID]
 	default:
 		panic(fmt.Sprintf("Can't get component with ID %d - it doesn't seem to exist", name))
 	}
@@ -27,3 +27,9 @@ const ADD_REMOVE_LOGIC_CHANNEL_CAPACITY = MAX_ENTITIES / 4
 
 const RUNTIME_LIMIT_SHARER_MAX_LOOPS = 8
 const RUNTIME_LIMIT_SHARER_MAX_RUNNER_RUNS = 4
+
+// how many compiled EFDSL expressions are kept (see EFDSLEvaluator.Compile())
+const EFDSL_QUERY_CACHE_SIZE = 256
+
+// how many flow fields (each the size of the grid) a FlowFieldCache keeps
+const FLOWFIELD_CACHE_SIZE = 64
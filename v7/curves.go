@@ -1,7 +1,9 @@
 package sameriver
 
 import (
+	"fmt"
 	"math"
+	"reflect"
 )
 
 // visual aid:
@@ -395,3 +397,47 @@ func (c *curves) QuantX(n int, f CurveFunc) CurveFunc {
 		return c.StepsB(n)(f(x))
 	}
 }
+
+//
+// BY NAME
+//
+
+var curveFuncType = reflect.TypeOf(CurveFunc(nil))
+
+// Named returns the curve made by the method of the given name with the
+// given params (eg. Curves.Named("Sigmoid", 0.5, 1)), for when curves are
+// chosen in data. Curves taking no params (eg. "Lin") are returned as-is
+func (c *curves) Named(name string, params ...float64) (CurveFunc, error) {
+	m := reflect.ValueOf(c).MethodByName(name)
+	if !m.IsValid() {
+		return nil, fmt.Errorf("unknown curve %s", name)
+	}
+	t := m.Type()
+	if f, ok := m.Interface().(func(float64) float64); ok {
+		if len(params) != 0 {
+			return nil, fmt.Errorf("curve %s takes no params, got %d", name, len(params))
+		}
+		return f, nil
+	}
+	if t.NumOut() != 1 || t.Out(0) != curveFuncType {
+		return nil, fmt.Errorf("%s is not a curve", name)
+	}
+	if t.NumIn() != len(params) {
+		return nil, fmt.Errorf("curve %s takes %d params, got %d", name, t.NumIn(), len(params))
+	}
+	args := make([]reflect.Value, len(params))
+	for i, param := range params {
+		switch t.In(i).Kind() {
+		case reflect.Float64:
+			args[i] = reflect.ValueOf(param)
+		case reflect.Int:
+			if param != math.Trunc(param) {
+				return nil, fmt.Errorf("curve %s param %d should be an integer, got %g", name, i, param)
+			}
+			args[i] = reflect.ValueOf(int(param))
+		default:
+			return nil, fmt.Errorf("curve %s param %d is a %s, which can't be given by name", name, i, t.In(i))
+		}
+	}
+	return m.Call(args)[0].Interface().(CurveFunc), nil
+}
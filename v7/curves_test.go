@@ -340,3 +340,39 @@ func TestCurvesQuantize(t *testing.T) {
 		}
 	}
 }
+
+func TestCurvesNamed(t *testing.T) {
+	named := func(name string, params ...float64) CurveFunc {
+		f, err := Curves.Named(name, params...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	expect := []float64{
+		named("Sigmoid", 0.5, 1)(0.5), Curves.Sigmoid(0.5, 1)(0.5),
+		named("Lin")(0.3), 0.3,
+		named("Steps", 4)(0.6), Curves.Steps(4)(0.6),
+		named("SkewMayan", 3, 0.2, 1)(0.7), Curves.SkewMayan(3, 0.2, 1)(0.7),
+	}
+	for i := 0; i < len(expect); i += 2 {
+		if math.Abs(expect[i]-expect[i+1]) > 0.001 {
+			t.Fatalf("condition %d resulted in %f, not %f", i/2, expect[i], expect[i+1])
+		}
+	}
+	for _, bad := range []struct {
+		name   string
+		params []float64
+	}{
+		{"Wiggle", nil},
+		{"Sigmoid", []float64{0.5}},
+		{"Lin", []float64{1}},
+		{"Steps", []float64{1.5}},
+		{"QuantX", []float64{4, 1}},
+		{"Named", nil},
+	} {
+		if _, err := Curves.Named(bad.name, bad.params...); err == nil {
+			t.Fatalf("%s%v should be an error", bad.name, bad.params)
+		}
+	}
+}
@@ -1,47 +1,37 @@
 package sameriver
 
-import (
-	"fmt"
-	"sort"
-)
-
 // notice the sortf returned by Evaluate() is a closure that wants the result string so it can actually use i, j int
 // params: EFDSLEval takes the expression and a resolver (for identifiers)
 // returns: an entity predicate and an entity sort function and possibly an error
 //
 //	aka (p, q, err)
 func EFDSLEval(expr string, resolver IdentifierResolver, world *World) (func(*Entity) bool, func(xs []*Entity) func(i, j int) bool, error) {
-	parser := &EFDSLParser{}
-
-	ast, err := parser.Parse(expr)
+	q, err := world.EFDSL.Compile(expr)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse expr: %s", err)
+		return nil, nil, err
 	}
 
-	filter, sort := world.EFDSL.Evaluate(ast, resolver)
+	filter, sort := q.Evaluate(resolver)
 
 	return filter, sort, nil
 }
 
+// EFDSLFilter and EFDSLFilterSort use the world's cache of compiled queries
+// (see EFDSLQuery), narrowing the candidates by the spatial clause if any
 func EFDSLFilter(expr string, resolver IdentifierResolver, world *World) ([]*Entity, error) {
-	filter, _, err := EFDSLEval(expr, resolver, world)
+	q, err := world.EFDSL.Compile(expr)
 	if err != nil {
 		return nil, err
 	}
-	result := world.FilterAllEntities(filter)
-	return result, nil
+	return q.Filter(resolver), nil
 }
 
 func EFDSLFilterSort(expr string, resolver IdentifierResolver, world *World) ([]*Entity, error) {
-	filterf, sortf, err := EFDSLEval(expr, resolver, world)
+	q, err := world.EFDSL.Compile(expr)
 	if err != nil {
 		return nil, err
 	}
-	result := world.FilterAllEntities(filterf)
-	if sortf != nil {
-		sort.Slice(result, sortf(result))
-	}
-	return result, nil
+	return q.FilterSort(resolver), nil
 }
 
 func (w *World) EFDSLFilterEntity(e *Entity, expr string) ([]*Entity, error) {
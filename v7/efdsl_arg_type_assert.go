@@ -19,6 +19,10 @@ func AssertT[T any](x interface{}, expectedTypeStr string) (T, error) {
 	}
 }
 
+// IdentResolve<T> resolves identifiers as T by T's name (see
+// RegisterEFDSLType())
+var IdentResolveTypeAssertMap = map[string]DSLArgTypeAssertionFunc{}
+
 var typeResolveFuncs = map[string]map[string]DSLArgTypeAssertionFunc{
 	"IdentResolve": IdentResolveTypeAssertMap,
 }
@@ -92,11 +96,15 @@ func DSLAssertArgTypes(signature string, args []string, resolver IdentifierResol
 		parts := strings.Split(expectedTypes[i], "<")
 		if typeResolveFuncsMap, ok := typeResolveFuncs[parts[0]]; ok && len(parts) > 1 {
 			typeName := strings.TrimSuffix(parts[1], ">")
-			if arg == "self" {
+			if arg == "self" || arg == "x" {
 				typeName = "int"
 			}
 			if typeResolveFunc, ok := typeResolveFuncsMap[typeName]; ok {
-				value, err := typeResolveFunc(arg, resolver)
+				argResolver := resolver
+				if literal, ok := efdslLiteral(arg); ok {
+					argResolver = efdslLiteralResolver{literal}
+				}
+				value, err := typeResolveFunc(arg, argResolver)
 				if err != nil {
 					return nil, fmt.Errorf("error for %s(%s): expected %s for argument %s, but %s", signature, strings.Join(args, ", "), expectedTypes[i], arg, err)
 				}
@@ -126,9 +134,13 @@ func DSLAssertArgTypes(signature string, args []string, resolver IdentifierResol
 				}
 				resolved[i] = v
 			case "string":
-				resolved[i] = arg
+				resolved[i] = efdslUnquote(arg)
 			case "[]string":
-				resolved[i] = args
+				strs := make([]string, len(args))
+				for j, arg := range args {
+					strs[j] = efdslUnquote(arg)
+				}
+				resolved[i] = strs
 			default:
 				return nil, fmt.Errorf("unsupported type in signature: %s", expectedTypes[i])
 			}
@@ -0,0 +1,211 @@
+package sameriver
+
+/*
+The functions given to Predicate() and Sort() take typed arguments, eg.
+func(k string, v int) func(*Entity) bool, while the args resolved from an
+expression are []any, so something has to call the one with the other.
+
+When the factory is made, each function gets a caller built for its type:
+if the signature was registered (RegisterEFDSLSignature(),
+RegisterEFDSLSignature2() or RegisterEFDSLSignature3()), a direct call
+with the args type-asserted, otherwise a call by reflection, which is
+slower but works for any arity and argument types. Either way, a function
+which couldn't be called with its signature's args panics then, rather
+than when an expression is evaluated.
+
+Argument types are resolved from identifiers (IdentResolve<T>) by name;
+RegisterEFDSLType() adds one, eg. for a game's own types:
+
+	RegisterEFDSLType[*Faction]("*Faction")
+	RegisterEFDSLSignature2[*Faction, int]()
+
+	"AtWarWith": e.Predicate(
+		"IdentResolve<*Faction>, int",
+		func(f *Faction, years int) func(*Entity) bool { ... },
+	),
+
+Registration isn't synchronised with evaluation, so do it at init.
+*/
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type efdslSortFunc = func(xs []*Entity) func(i, j int) int
+
+// an efdslCaller calls the function it was built for with args typed as
+// its parameters
+type efdslCaller[R any] func(argsTyped []any) R
+
+type efdslDispatcher[R any] struct {
+	mutex sync.RWMutex
+	// by the type of the function, makes the caller of a function of that
+	// type
+	adapters map[reflect.Type]func(f any) efdslCaller[R]
+}
+
+var efdslPredicateDispatcher = &efdslDispatcher[func(*Entity) bool]{
+	adapters: make(map[reflect.Type]func(f any) efdslCaller[func(*Entity) bool]),
+}
+
+var efdslSortDispatcher = &efdslDispatcher[efdslSortFunc]{
+	adapters: make(map[reflect.Type]func(f any) efdslCaller[efdslSortFunc]),
+}
+
+func init() {
+	RegisterEFDSLType[bool]("bool")
+	RegisterEFDSLType[int]("int")
+	RegisterEFDSLType[float64]("float64")
+	RegisterEFDSLType[string]("string")
+	RegisterEFDSLType[[]string]("[]string")
+	RegisterEFDSLType[[]int]("[]int")
+	RegisterEFDSLType[Vec2D]("Vec2D")
+	RegisterEFDSLType[*Vec2D]("*Vec2D")
+	RegisterEFDSLType[[]Vec2D]("[]Vec2D")
+	RegisterEFDSLType[*Entity]("*Entity")
+	RegisterEFDSLType[*Item]("*Item")
+
+	// the signatures of the base predicates and sorts
+	RegisterEFDSLSignature[bool]()
+	RegisterEFDSLSignature[int]()
+	RegisterEFDSLSignature[float64]()
+	RegisterEFDSLSignature[string]()
+	RegisterEFDSLSignature[[]string]()
+	RegisterEFDSLSignature[*Entity]()
+	RegisterEFDSLSignature2[string, int]()
+	RegisterEFDSLSignature2[int, float64]()
+	RegisterEFDSLSignature3[*Vec2D, *Vec2D, float64]()
+}
+
+// the types of the type names of signatures
+var efdslTypes = make(map[string]reflect.Type)
+
+// RegisterEFDSLType lets arguments be resolved to T as IdentResolve<name>
+func RegisterEFDSLType[T any](name string) {
+	IdentResolveTypeAssertMap[name] = func(arg string, resolver IdentifierResolver) (any, error) {
+		return AssertT[T](resolver.Resolve(arg), name)
+	}
+	efdslTypes[name] = reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// the type of an arg of a signature, eg. int for IdentResolve<int>
+func efdslArgType(typeName string) (reflect.Type, bool) {
+	if i := strings.Index(typeName, "<"); i != -1 {
+		typeName = strings.TrimSuffix(typeName[i+1:], ">")
+	}
+	t, ok := efdslTypes[typeName]
+	return t, ok
+}
+
+// RegisterEFDSLSignature makes calling predicates and sorts taking an A
+// direct rather than by reflection
+func RegisterEFDSLSignature[A any]() {
+	efdslPredicateDispatcher.register(reflect.TypeOf(func(A) func(*Entity) bool { return nil }),
+		func(f any) efdslCaller[func(*Entity) bool] {
+			fTyped := f.(func(A) func(*Entity) bool)
+			return func(args []any) func(*Entity) bool {
+				return fTyped(efdslArg[A](args[0]))
+			}
+		})
+	efdslSortDispatcher.register(reflect.TypeOf(func(A) efdslSortFunc { return nil }),
+		func(f any) efdslCaller[efdslSortFunc] {
+			fTyped := f.(func(A) efdslSortFunc)
+			return func(args []any) efdslSortFunc {
+				return fTyped(efdslArg[A](args[0]))
+			}
+		})
+}
+
+func RegisterEFDSLSignature2[A, B any]() {
+	efdslPredicateDispatcher.register(reflect.TypeOf(func(A, B) func(*Entity) bool { return nil }),
+		func(f any) efdslCaller[func(*Entity) bool] {
+			fTyped := f.(func(A, B) func(*Entity) bool)
+			return func(args []any) func(*Entity) bool {
+				return fTyped(efdslArg[A](args[0]), efdslArg[B](args[1]))
+			}
+		})
+	efdslSortDispatcher.register(reflect.TypeOf(func(A, B) efdslSortFunc { return nil }),
+		func(f any) efdslCaller[efdslSortFunc] {
+			fTyped := f.(func(A, B) efdslSortFunc)
+			return func(args []any) efdslSortFunc {
+				return fTyped(efdslArg[A](args[0]), efdslArg[B](args[1]))
+			}
+		})
+}
+
+func RegisterEFDSLSignature3[A, B, C any]() {
+	efdslPredicateDispatcher.register(reflect.TypeOf(func(A, B, C) func(*Entity) bool { return nil }),
+		func(f any) efdslCaller[func(*Entity) bool] {
+			fTyped := f.(func(A, B, C) func(*Entity) bool)
+			return func(args []any) func(*Entity) bool {
+				return fTyped(efdslArg[A](args[0]), efdslArg[B](args[1]), efdslArg[C](args[2]))
+			}
+		})
+	efdslSortDispatcher.register(reflect.TypeOf(func(A, B, C) efdslSortFunc { return nil }),
+		func(f any) efdslCaller[efdslSortFunc] {
+			fTyped := f.(func(A, B, C) efdslSortFunc)
+			return func(args []any) efdslSortFunc {
+				return fTyped(efdslArg[A](args[0]), efdslArg[B](args[1]), efdslArg[C](args[2]))
+			}
+		})
+}
+
+// an arg as T, or T's zero value if it's nil (as an unresolved pointer is)
+func efdslArg[T any](arg any) T {
+	if arg == nil {
+		var zero T
+		return zero
+	}
+	return arg.(T)
+}
+
+func (d *efdslDispatcher[R]) register(t reflect.Type, adapter func(f any) efdslCaller[R]) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.adapters[t] = adapter
+}
+
+// caller builds the caller of f, checking it takes the args of signature
+func (d *efdslDispatcher[R]) caller(f any, signature string) efdslCaller[R] {
+	ft := reflect.TypeOf(f)
+	var r R
+	rt := reflect.TypeOf(&r).Elem()
+	if ft == nil || ft.Kind() != reflect.Func || ft.NumOut() != 1 || ft.Out(0) != rt {
+		panic(fmt.Sprintf("EFDSL function for signature %q should be a func(...) %s, got %v", signature, rt, ft))
+	}
+	types, err := ExtractTypesFromSignature(signature)
+	if err != nil {
+		panic(err)
+	}
+	if ft.NumIn() != len(types) || ft.IsVariadic() {
+		panic(fmt.Sprintf("EFDSL function %v should take the %d args of signature %q", ft, len(types), signature))
+	}
+	for i, typeName := range types {
+		if t, ok := efdslArgType(typeName); ok && t != ft.In(i) {
+			panic(fmt.Sprintf("EFDSL function %v should take %v for %s of signature %q", ft, t, typeName, signature))
+		}
+	}
+
+	d.mutex.RLock()
+	adapter, ok := d.adapters[ft]
+	d.mutex.RUnlock()
+	if ok {
+		return adapter(f)
+	}
+
+	fv := reflect.ValueOf(f)
+	return func(args []any) R {
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			if arg == nil {
+				in[i] = reflect.Zero(ft.In(i))
+			} else {
+				in[i] = reflect.ValueOf(arg)
+			}
+		}
+		return fv.Call(in)[0].Interface().(R)
+	}
+}
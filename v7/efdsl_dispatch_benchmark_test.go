@@ -0,0 +1,50 @@
+package sameriver
+
+import (
+	"testing"
+)
+
+func BenchmarkEFDSLDispatchIntFunc(b *testing.B) {
+	// One-arg "int" func (a registered signature)
+	intFunc := func(arg int) func(*Entity) bool {
+		return func(e *Entity) bool { return true }
+	}
+	call := efdslPredicateDispatcher.caller(intFunc, "int")
+
+	// Prepare the arguments
+	argsTyped := []any{42}
+
+	for i := 0; i < b.N; i++ {
+		_ = call(argsTyped)
+	}
+}
+
+func BenchmarkEFDSLDispatchVec2DBoolSliceVec2DFunc(b *testing.B) {
+	// Three-arg "*Vec2D, bool, []*Vec2D" func
+	threeArgFunc := func(v *Vec2D, b bool, vs []*Vec2D) func(*Entity) bool {
+		return func(e *Entity) bool { return true }
+	}
+	RegisterEFDSLSignature3[*Vec2D, bool, []*Vec2D]()
+	call := efdslPredicateDispatcher.caller(threeArgFunc, "IdentResolve<*Vec2D>, bool, IdentResolve<[]*Vec2D>")
+
+	// Prepare the arguments
+	argsTyped := []any{&Vec2D{1, 2}, true, []*Vec2D{{3, 4}, {5, 6}}}
+
+	for i := 0; i < b.N; i++ {
+		_ = call(argsTyped)
+	}
+}
+
+func BenchmarkEFDSLDispatchReflectFunc(b *testing.B) {
+	// an unregistered signature, called by reflection
+	fourArgFunc := func(a, b, c, d int) func(*Entity) bool {
+		return func(e *Entity) bool { return true }
+	}
+	call := efdslPredicateDispatcher.caller(fourArgFunc, "int, int, int, int")
+
+	argsTyped := []any{1, 2, 3, 4}
+
+	for i := 0; i < b.N; i++ {
+		_ = call(argsTyped)
+	}
+}
@@ -6,15 +6,19 @@ type EFDSLPredicateMap map[string](EFDSLPredicate)
 type EFDSLSort func(args []string, resolver IdentifierResolver) func(xs []*Entity) func(i, j int) bool
 type EFDSLSortMap map[string](EFDSLSort)
 
+// an EFDSLSpatial gives the candidates near somewhere, from the
+// SpatialHasher, and the predicate of being there (for when the clause is
+// evaluated as a plain filter)
+type EFDSLSpatial func(args []string, resolver IdentifierResolver) (candidates func() []*Entity, filter func(*Entity) bool)
+type EFDSLSpatialMap map[string](EFDSLSpatial)
+
 type EFDSLEvaluator struct {
 	w          *World
 	predicates EFDSLPredicateMap
 	sorts      EFDSLSortMap
-	// used to allow - in a performant way avoiding reflect - users to
-	// specify their own predicate/sort func type signatures beyond
-	// those defined in the switch block in GENERATED_efdsl_sig_assert_switches.go
-	userPredicateSignatureAsserter func(f any, argsTyped []any) func(*Entity) bool
-	userSortSignatureAsserter      func(f any, argsTyped []any) func(xs []*Entity) func(i, j int) int
+	spatials   EFDSLSpatialMap
+	// compiled queries by expression text (see Compile())
+	queries *lruCache[string, *EFDSLQuery]
 }
 
 func NewEFDSLEvaluator(w *World) *EFDSLEvaluator {
@@ -22,9 +26,12 @@ func NewEFDSLEvaluator(w *World) *EFDSLEvaluator {
 		w:          w,
 		predicates: EFDSLPredicateMap{},
 		sorts:      EFDSLSortMap{},
+		spatials:   EFDSLSpatialMap{},
+		queries:    newLRUCache[string, *EFDSLQuery](EFDSL_QUERY_CACHE_SIZE),
 	}
 	e.RegisterPredicates(EFDSLPredicatesBase(e))
 	e.RegisterSorts(EFDSLSortsBase(e))
+	e.RegisterSpatials(EFDSLSpatialsBase(e))
 	return e
 }
 
@@ -42,14 +49,17 @@ func (e *EFDSLEvaluator) RegisterSorts(sorts EFDSLSortMap) *EFDSLEvaluator {
 	return e
 }
 
-// TODO: this is never called
-func (e *EFDSLEvaluator) RegisterUserPredicateSignatureAsserter(asserter func(f any, argsTyped []any) func(*Entity) bool) {
-	e.userPredicateSignatureAsserter = asserter
-}
-
-// TODO: this is never called
-func (e *EFDSLEvaluator) RegisterUserSortSignatureAsserter(asserter func(f any, argsTyped []any) func(xs []*Entity) func(i, j int) int) {
-	e.userSortSignatureAsserter = asserter
+// RegisterSpatials also adds the names to EFDSLSpatialFunctions, so that
+// the parser knows them as spatial clauses (which, being shared by all
+// parsers, shouldn't happen while expressions are being parsed)
+func (e *EFDSLEvaluator) RegisterSpatials(spatials EFDSLSpatialMap) *EFDSLEvaluator {
+	for k, v := range spatials {
+		e.spatials[k] = v
+		if !EFDSLSpatialFunctions[k] {
+			EFDSLSpatialFunctions[k] = true
+		}
+	}
+	return e
 }
 
 func (e *EFDSLEvaluator) Evaluate(n *Node, resolver IdentifierResolver) (filter func(*Entity) bool, sort func(xs []*Entity) func(i, j int) bool) {
@@ -57,48 +67,67 @@ func (e *EFDSLEvaluator) Evaluate(n *Node, resolver IdentifierResolver) (filter
 		panic("Node type must be NodeExpr")
 	}
 
-	predicateNode := n.Children[0]
-	filter = e.evaluatePredicate(predicateNode, resolver)
+	predicateNode := n.Clause(NodePredicateExpr)
+	filter = e.evaluateFilter(predicateNode, resolver)
 
-	if len(n.Children) > 1 {
-		sortNode := n.Children[1]
+	// without narrowing the candidates, the spatial clause is part of the
+	// filter
+	if spatialNode := n.Clause(NodeSpatialExpr); spatialNode != nil {
+		_, near := e.evaluateSpatial(spatialNode, resolver)
+		predicate := filter
+		filter = func(x *Entity) bool {
+			return near(x) && predicate(x)
+		}
+	}
+
+	if sortNode := n.Clause(NodeSortExpr); sortNode != nil {
 		sort = e.evaluateSort(sortNode, resolver)
 	}
 
 	return filter, sort
 }
 
+// evaluateFilter is evaluatePredicate, but matching nothing if a predicate
+// couldn't be made (its args didn't type, say; the error's been logged)
+func (e *EFDSLEvaluator) evaluateFilter(n *Node, resolver IdentifierResolver) func(*Entity) bool {
+	if predicate := e.evaluatePredicate(n, resolver); predicate != nil {
+		return predicate
+	}
+	return func(*Entity) bool {
+		return false
+	}
+}
+
+// a NodePredicateExpr's children are an optional NodeNot, the function,
+// then an optional NodeAnd or NodeOr whose child is the rest of the
+// expression
 func (e *EFDSLEvaluator) evaluatePredicate(n *Node, resolver IdentifierResolver) func(*Entity) bool {
 	if n.Type == NodePredicateExpr {
-		predicates := make([]func(*Entity) bool, 0, len(n.Children))
-		for _, child := range n.Children {
-			predicates = append(predicates, e.evaluatePredicate(child, resolver))
+		i := 0
+		negate := n.Children[0].Type == NodeNot
+		if negate {
+			i++
 		}
-		return func(entity *Entity) bool {
-			for _, pred := range predicates {
-				if !pred(entity) {
-					return false
-				}
+		predicate := e.evaluatePredicate(n.Children[i], resolver)
+		if predicate == nil {
+			return nil
+		}
+		if negate {
+			p := predicate
+			predicate = func(entity *Entity) bool {
+				return !p(entity)
 			}
-			return true
 		}
-	} else if n.Type == NodeNot {
-		predicate := e.evaluatePredicate(n.Children[0], resolver)
-		return func(entity *Entity) bool {
-			return !predicate(entity)
+		if len(n.Children) == i+1 {
+			return predicate
 		}
-	} else if n.Type == NodeFunction {
-		args := make([]string, 0, len(n.Children))
-		for _, child := range n.Children {
-			args = append(args, child.Value)
-		}
-		return e.predicates[n.Value](args, resolver)
-	} else if n.Type == NodeAnd || n.Type == NodeOr {
-		left := e.evaluatePredicate(n.Children[0], resolver)
-		right := e.evaluatePredicate(n.Children[1], resolver)
-		// TODO: how does this work for P && Q && R ?
-		// or P && Q || R for that matter?
-		if n.Type == NodeAnd {
+		op := n.Children[i+1]
+		left := predicate
+		right := e.evaluatePredicate(op.Children[0], resolver)
+		if right == nil {
+			return nil
+		}
+		if op.Type == NodeAnd {
 			return func(entity *Entity) bool {
 				return left(entity) && right(entity)
 			}
@@ -107,19 +136,108 @@ func (e *EFDSLEvaluator) evaluatePredicate(n *Node, resolver IdentifierResolver)
 				return left(entity) || right(entity)
 			}
 		}
+	} else if n.Type == NodeFunction {
+		f, ok := e.predicates[n.Value]
+		if !ok {
+			logDSLError("predicate %s is not registered", n.Value)
+			return nil
+		}
+		predicate := f(efdslArgs(n), resolver)
+		if predicate == nil {
+			return nil
+		}
+		if n.Subject == "" || n.Subject == "x" {
+			return predicate
+		}
+		// the predicate is of the subject, whichever the candidate
+		subject := n.Subject
+		return func(x *Entity) bool {
+			candidateResolver := &efdslCandidateResolver{resolver, e.w, x}
+			s := efdslEntity(e.w, candidateResolver.Resolve(subject))
+			return s != nil && predicate(s)
+		}
 	}
 	panic("Invalid node type for predicate")
 }
 
+// the Source() of each argument of a NodeFunction
+func efdslArgs(n *Node) []string {
+	args := make([]string, 0, len(n.Children))
+	for _, child := range n.Children {
+		args = append(args, child.Source())
+	}
+	return args
+}
+
 func (e *EFDSLEvaluator) evaluateSort(n *Node, resolver IdentifierResolver) func(xs []*Entity) func(i, j int) bool {
 	if n.Type != NodeSortExpr {
 		panic("Node type must be NodeSortExpr")
 	}
 
-	functionNode := n.Children[0]
-	args := make([]string, 0, len(functionNode.Children))
-	for _, child := range functionNode.Children {
-		args = append(args, child.Value)
+	if len(n.Children) == 1 {
+		functionNode := n.Children[0]
+		return e.sorts[functionNode.Value](efdslArgs(functionNode), resolver)
+	}
+	// a chain of sorts, each breaking the ties of the one before
+	sorts := make([]func(xs []*Entity) func(i, j int) bool, 0, len(n.Children))
+	for _, functionNode := range n.Children {
+		if sort := e.sorts[functionNode.Value](efdslArgs(functionNode), resolver); sort != nil {
+			sorts = append(sorts, sort)
+		}
+	}
+	return func(xs []*Entity) func(i, j int) bool {
+		lesses := make([]func(i, j int) bool, len(sorts))
+		for k, sort := range sorts {
+			lesses[k] = sort(xs)
+		}
+		return func(i, j int) bool {
+			for _, less := range lesses {
+				if less(i, j) {
+					return true
+				}
+				if less(j, i) {
+					return false
+				}
+			}
+			return false
+		}
+	}
+}
+
+// evaluatePage gives the Offset() and Limit() of a NodePageExpr (0 and -1
+// if absent, the last if repeated)
+func (e *EFDSLEvaluator) evaluatePage(n *Node, resolver IdentifierResolver) (offset int, limit int) {
+	if n.Type != NodePageExpr {
+		panic("Node type must be NodePageExpr")
+	}
+
+	limit = -1
+	for _, functionNode := range n.Children {
+		argsTyped, err := DSLAssertArgTypes("IdentResolve<int>", efdslArgs(functionNode), resolver)
+		if err != nil {
+			logDSLError("%s: %s", functionNode.Value, err)
+			continue
+		}
+		v := argsTyped[0].(int)
+		if v < 0 {
+			logDSLError("%s(%d) should not be negative", functionNode.Value, v)
+			continue
+		}
+		switch functionNode.Value {
+		case "Offset":
+			offset = v
+		case "Limit":
+			limit = v
+		}
+	}
+	return offset, limit
+}
+
+func (e *EFDSLEvaluator) evaluateSpatial(n *Node, resolver IdentifierResolver) (candidates func() []*Entity, filter func(*Entity) bool) {
+	if n.Type != NodeSpatialExpr {
+		panic("Node type must be NodeSpatialExpr")
 	}
-	return e.sorts[functionNode.Value](args, resolver)
+
+	functionNode := n.Children[0]
+	return e.spatials[functionNode.Value](efdslArgs(functionNode), resolver)
 }
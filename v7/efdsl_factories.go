@@ -10,9 +10,9 @@ EFDSL predicates and sorts.
 
 They take an arbitrary number of interleaved string signatures and
 corresponding functions as arguments. The factory function handles type
-assertion, identifier resolution, and uses generated switch statements to pass
-the type-asserted arguments to the corresponding function based on the provided
-signature. This makes defining EFDSL predicates more concise and readable.
+assertion, identifier resolution, and uses a caller built for each function
+(see efdsl_dispatch.go) to pass the type-asserted arguments to the
+corresponding function based on the provided signature. This makes defining EFDSL predicates more concise and readable.
 */
 
 func (e *EFDSLEvaluator) Predicate(args ...any) func(args []string, resolver IdentifierResolver) func(*Entity) bool {
@@ -28,22 +28,36 @@ func (e *EFDSLEvaluator) Predicate(args ...any) func(args []string, resolver Ide
 		funcs[i/2] = args[i+1]
 	}
 
+	callers := make([]efdslCaller[func(*Entity) bool], len(funcs))
+	for i, f := range funcs {
+		callers[i] = efdslPredicateDispatcher.caller(f, signatures[i])
+	}
+
 	return func(args []string, resolver IdentifierResolver) func(*Entity) bool {
+		if efdslSignatures(resolver, signatures...) {
+			return nil
+		}
+		// args referring to the candidate x can only be resolved as each
+		// candidate is tested, so we resolve them all then
+		for _, arg := range args {
+			if efdslRefersToX(arg) {
+				return func(x *Entity) bool {
+					candidateResolver := &efdslCandidateResolver{resolver, e.w, x}
+					argsTyped, i, err := DSLAssertOverloadedArgTypes(signatures, args, candidateResolver)
+					if err != nil {
+						return false
+					}
+					return callers[i](argsTyped)(x)
+				}
+			}
+		}
+
 		argsTyped, i, err := DSLAssertOverloadedArgTypes(signatures, args, resolver)
 		if err != nil {
 			logDSLError("%s", err)
 			return nil
 		}
-
-		// check if type signature is user-defined
-		if e.userPredicateSignatureAsserter != nil {
-			result := e.userPredicateSignatureAsserter(funcs[i], argsTyped)
-			if result != nil {
-				return result
-			}
-		}
-		// else, we handle a finite set of signatures (from generated)
-		return e.predicateSignatureAssertSwitch(funcs[i], argsTyped)
+		return callers[i](argsTyped)
 	}
 }
 
@@ -60,21 +74,20 @@ func (e *EFDSLEvaluator) Sort(args ...any) func(args []string, resolver Identifi
 		funcs[i/2] = args[i+1]
 	}
 
+	callers := make([]efdslCaller[efdslSortFunc], len(funcs))
+	for i, f := range funcs {
+		callers[i] = efdslSortDispatcher.caller(f, signatures[i])
+	}
+
 	return func(args []string, resolver IdentifierResolver) func(xs []*Entity) func(i, j int) int {
+		if efdslSignatures(resolver, signatures...) {
+			return nil
+		}
 		argsTyped, i, err := DSLAssertOverloadedArgTypes(signatures, args, resolver)
 		if err != nil {
 			logDSLError("%s", err)
 			return nil
 		}
-
-		// check if type signature is user-defined
-		if e.userSortSignatureAsserter != nil {
-			result := e.userSortSignatureAsserter(funcs[i], argsTyped)
-			if result != nil {
-				return result
-			}
-		}
-		// else, we handle a finite set of signatures from generated
-		return e.sortSignatureAssertSwitch(funcs[i], argsTyped)
+		return callers[i](argsTyped)
 	}
 }
@@ -2,6 +2,7 @@ package sameriver
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 	"text/scanner"
 	"unicode"
@@ -26,6 +27,15 @@ const (
 	CloseParen
 	Comma
 	Semicolon
+	// literals (TokenText() of a String is unquoted)
+	Number
+	String
+	Bool
+	// arithmetic on the arguments of functions
+	Plus
+	Minus
+	Star
+	Slash
 )
 
 func (t EFDSLToken) String() string {
@@ -50,6 +60,20 @@ func (t EFDSLToken) String() string {
 		return "Comma"
 	case Semicolon:
 		return "Semicolon"
+	case Number:
+		return "Number"
+	case String:
+		return "String"
+	case Bool:
+		return "Bool"
+	case Plus:
+		return "Plus"
+	case Minus:
+		return "Minus"
+	case Star:
+		return "Star"
+	case Slash:
+		return "Slash"
 	default:
 		return "Unknown"
 	}
@@ -59,6 +83,10 @@ type EFDSLLexer struct {
 	scanner.Scanner
 	token       EFDSLToken
 	stringValue string
+	// where the last token started
+	pos scanner.Position
+	// characters which couldn't be lexed (and were skipped)
+	illegal []*EFDSLError
 }
 
 func (l *EFDSLLexer) IsEOF() bool {
@@ -69,15 +97,26 @@ func (l *EFDSLLexer) TokenText() string {
 	return l.stringValue
 }
 
+// TokenPos is where the last token started (or, at EOF, the end)
+func (l *EFDSLLexer) TokenPos() scanner.Position {
+	return l.pos
+}
+
+func (l *EFDSLLexer) illegalf(pos scanner.Position, format string, args ...any) {
+	l.illegal = append(l.illegal, efdslErrorf(pos, format, args...))
+}
+
 func (l *EFDSLLexer) Lex() EFDSLToken {
 	l.stringValue = ""
 	l.token = EOF
+	l.pos = l.Pos()
 
 	for !l.IsEOF() {
 		r := l.Peek()
 
 		if unicode.IsSpace(r) {
 			l.Next()
+			l.pos = l.Pos()
 			continue
 		}
 
@@ -92,6 +131,7 @@ func (l *EFDSLLexer) Lex() EFDSLToken {
 				l.token = And
 			} else {
 				l.token = EOF
+				l.illegalf(l.pos, "expected &&")
 			}
 		case r == '|':
 			l.Next()
@@ -100,6 +140,7 @@ func (l *EFDSLLexer) Lex() EFDSLToken {
 				l.token = Or
 			} else {
 				l.token = EOF
+				l.illegalf(l.pos, "expected ||")
 			}
 		case r == '(':
 			l.Next()
@@ -113,6 +154,26 @@ func (l *EFDSLLexer) Lex() EFDSLToken {
 		case r == ';':
 			l.Next()
 			l.token = Semicolon
+		case r == '+':
+			l.Next()
+			l.token = Plus
+		case r == '-':
+			l.Next()
+			l.token = Minus
+		case r == '*':
+			l.Next()
+			l.token = Star
+		case r == '/':
+			l.Next()
+			l.token = Slash
+		case r == '"':
+			if str, ok := l.scanQuoted(); ok {
+				l.stringValue = str
+				l.token = String
+			} else {
+				l.token = EOF
+				l.illegalf(l.pos, "unterminated string")
+			}
 		case unicode.IsUpper(r):
 			str := l.scanString(func(r rune) bool {
 				return unicode.IsLetter(r)
@@ -127,18 +188,26 @@ func (l *EFDSLLexer) Lex() EFDSLToken {
 			str := l.scanString(DSLIdentRune)
 			if str != "" {
 				l.stringValue = str
-				l.token = Identifier
+				switch {
+				case str == "true" || str == "false":
+					l.token = Bool
+				case unicode.IsDigit(r) && efdslIsNumber(str):
+					l.token = Number
+				default:
+					l.token = Identifier
+				}
 			} else {
 				l.token = EOF
 			}
 		default:
 			l.token = EOF
-			l.Next()
+			l.illegalf(l.pos, "unexpected character %q", l.Next())
 		}
 
 		if l.token != EOF {
 			break
 		}
+		l.pos = l.Pos()
 	}
 
 	return l.token
@@ -151,3 +220,25 @@ func (l *EFDSLLexer) scanString(isValid func(rune) bool) string {
 	}
 	return buf.String()
 }
+
+// scan a double-quoted string with Go escapes, returning it unquoted
+func (l *EFDSLLexer) scanQuoted() (string, bool) {
+	var buf strings.Builder
+	buf.WriteRune(l.Next())
+	for !l.IsEOF() {
+		r := l.Next()
+		buf.WriteRune(r)
+		if r == '\\' && !l.IsEOF() {
+			buf.WriteRune(l.Next())
+		} else if r == '"' {
+			str, err := strconv.Unquote(buf.String())
+			return str, err == nil
+		}
+	}
+	return "", false
+}
+
+func efdslIsNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
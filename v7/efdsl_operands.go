@@ -0,0 +1,266 @@
+package sameriver
+
+/*
+Operands are the arguments of the comparison predicates (Eq, Ne, Lt, Le,
+Gt, Ge), which unlike the other predicates don't have a fixed signature:
+each side is a literal, an identifier, or arithmetic on them, eg.
+
+	Gt(self<gold>, 2 * mind.bestOffer)
+	Eq(bb.village.alarm, true)
+	Lt(mind.enemy<health>, 0.5 * mind.enemy<maxHealth>)
+	Ge(self<gold>, x<price> + 1)
+
+and the sides are compared according to what they resolve to:
+
+  - numbers (any int or float kind, or pointers to them) numerically
+  - strings lexically
+  - bools and other values (eg. *Entity, compared by pointer) only by Eq
+    and Ne; an *Entity also equals its ID, which is what "self" and "x"
+    resolve to
+
+Anything unresolved (nil), or sides which can't be compared, fail the
+predicate. Arithmetic is on numbers (/ always gives a float64, + also
+concatenates strings), and is nil otherwise.
+*/
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// efdslLiteral parses the Source() of a literal argument node
+func efdslLiteral(arg string) (any, bool) {
+	if strings.HasPrefix(arg, `"`) {
+		str, err := strconv.Unquote(arg)
+		return str, err == nil
+	}
+	if arg == "true" || arg == "false" {
+		return arg == "true", true
+	}
+	digits := strings.TrimPrefix(arg, "-")
+	if digits == "" || !unicode.IsDigit(rune(digits[0])) {
+		return nil, false
+	}
+	if i, err := strconv.Atoi(arg); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(arg, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+// efdslUnquote gives the string of a quoted string literal, or else the
+// arg as it is (strings needn't be quoted where a string is expected)
+func efdslUnquote(arg string) string {
+	if str, err := strconv.Unquote(arg); err == nil && strings.HasPrefix(arg, `"`) {
+		return str
+	}
+	return arg
+}
+
+// resolves any identifier to a literal's value, so literals can be passed
+// where IdentResolve<T> is expected
+type efdslLiteralResolver struct {
+	value any
+}
+
+func (r efdslLiteralResolver) Resolve(identifier string) any {
+	return r.value
+}
+
+// Operand compiles an argument into a function giving its value for the
+// entity being tested
+func (e *EFDSLEvaluator) Operand(arg string, resolver IdentifierResolver) (func(x *Entity) any, error) {
+	n, err := (&EFDSLParser{}).ParseArg(arg)
+	if err != nil {
+		return nil, fmt.Errorf("bad operand %s: %s", arg, err)
+	}
+	return e.compileOperand(n, resolver), nil
+}
+
+func (e *EFDSLEvaluator) compileOperand(n *Node, resolver IdentifierResolver) func(x *Entity) any {
+	switch n.Type {
+	case NodeLiteral:
+		v := n.Literal
+		return func(x *Entity) any {
+			return v
+		}
+	case NodeArith:
+		left := e.compileOperand(n.Children[0], resolver)
+		right := e.compileOperand(n.Children[1], resolver)
+		op := n.Value
+		return func(x *Entity) any {
+			return efdslArith(op, left(x), right(x))
+		}
+	default:
+		identifier := n.Value
+		if efdslIsX(identifier) {
+			return func(x *Entity) any {
+				return efdslResolveX(e.w, x, identifier)
+			}
+		}
+		v := resolver.Resolve(identifier)
+		return func(x *Entity) any {
+			return v
+		}
+	}
+}
+
+// efdslComparison makes a comparison predicate passing if test() of the
+// comparison of its operands (<0, 0, >0) is true. If ordered, the operands
+// must be numbers or strings
+func (e *EFDSLEvaluator) efdslComparison(name string, ordered bool, test func(cmp int) bool) EFDSLPredicate {
+	return func(args []string, resolver IdentifierResolver) func(*Entity) bool {
+		if efdslSignatures(resolver, EFDSL_OPERAND+", "+EFDSL_OPERAND) {
+			return nil
+		}
+		if len(args) != 2 {
+			logDSLError("%s takes 2 operands, got %d: %s", name, len(args), strings.Join(args, ", "))
+			return nil
+		}
+		a, err := e.Operand(args[0], resolver)
+		if err != nil {
+			logDSLError("%s: %s", name, err)
+			return nil
+		}
+		b, err := e.Operand(args[1], resolver)
+		if err != nil {
+			logDSLError("%s: %s", name, err)
+			return nil
+		}
+		return func(x *Entity) bool {
+			cmp, ok := efdslCompare(a(x), b(x), ordered)
+			return ok && test(cmp)
+		}
+	}
+}
+
+// efdslNumber gives the value of an int or float kind (or pointer to one),
+// and whether it's an integer
+func efdslNumber(v any) (f float64, i int64, isInt bool, ok bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), rv.Int(), true, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), int64(rv.Uint()), true, true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), 0, false, true
+	}
+	return 0, 0, false, false
+}
+
+// dereference pointers to strings and bools
+func efdslDeref(v any) any {
+	switch p := v.(type) {
+	case *string:
+		if p != nil {
+			return *p
+		}
+	case *bool:
+		if p != nil {
+			return *p
+		}
+	}
+	return v
+}
+
+func efdslArith(op string, a, b any) any {
+	if a == nil || b == nil {
+		return nil
+	}
+	af, ai, aInt, aOk := efdslNumber(a)
+	bf, bi, bInt, bOk := efdslNumber(b)
+	if !aOk || !bOk {
+		as, aStr := efdslDeref(a).(string)
+		bs, bStr := efdslDeref(b).(string)
+		if op == "+" && aStr && bStr {
+			return as + bs
+		}
+		return nil
+	}
+	if aInt && bInt && op != "/" {
+		switch op {
+		case "+":
+			return int(ai + bi)
+		case "-":
+			return int(ai - bi)
+		case "*":
+			return int(ai * bi)
+		}
+	}
+	switch op {
+	case "+":
+		return af + bf
+	case "-":
+		return af - bf
+	case "*":
+		return af * bf
+	case "/":
+		if bf == 0 {
+			return nil
+		}
+		return af / bf
+	}
+	return nil
+}
+
+func efdslCompare(a, b any, ordered bool) (cmp int, ok bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	af, ai, aInt, aOk := efdslNumber(a)
+	bf, bi, bInt, bOk := efdslNumber(b)
+	if aOk && bOk {
+		if aInt && bInt {
+			return efdslSign(float64(ai - bi)), true
+		}
+		return efdslSign(af - bf), true
+	}
+	a, b = efdslDeref(a), efdslDeref(b)
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return strings.Compare(as, bs), true
+		}
+		return 0, false
+	}
+	if ordered {
+		return 0, false
+	}
+	// an entity equals its ID
+	if ae, ok := a.(*Entity); ok && bOk && bInt {
+		return efdslEq(int64(ae.ID) == bi), true
+	}
+	if be, ok := b.(*Entity); ok && aOk && aInt {
+		return efdslEq(int64(be.ID) == ai), true
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb || !ta.Comparable() {
+		return 0, false
+	}
+	return efdslEq(a == b), true
+}
+
+func efdslSign(x float64) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+func efdslEq(eq bool) int {
+	if eq {
+		return 0
+	}
+	return 1
+}
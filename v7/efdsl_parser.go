@@ -8,10 +8,11 @@ F(x)
 F(x, y)
 F(x, y) && G(z)
 F(x, y) && G(z); H(q)
+Within(self, 50); F(x, y); H(q), Desc(x<gold>); Limit(3)
 
-it's just ultimately this grammar, a kind of "SQL" in the sense of WHERE, ORDER BY:
+it's just ultimately this grammar, a kind of "SQL" in the sense of FROM, WHERE, ORDER BY, LIMIT:
 
-Expr            := PredicateExpr (Semicolon SortExpr)?
+Expr            := (SpatialExpr Semicolon)? PredicateExpr (Semicolon SortExpr)? (Semicolon PageExpr)*
 PredicateExpr   := Not? Function (And PredicateExpr | Or PredicateExpr)?
 Function        := Identifier OpenParen Args CloseParen
 Args            := Identifier (Comma Identifier)*
@@ -36,7 +37,7 @@ strings-between-commas-in-parens (Identifiers) as
 
 - Atoi if it's expected to be an int
 - ParseFloat() if they expect a float
-- as a string if it's just a string (no quotes!)
+- as a string if it's just a string (quotes are optional, and unquoted)
 
 	OR they try to use the evaluator's passed-in *resolver* strategy
 	such as EntityResolver or WorldResolver.
@@ -73,48 +74,98 @@ entity_filter_dsl_resolver_strategies.go
 */
 
 import (
-	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"text/scanner"
+	"unicode"
 )
 
 /*
 grammar:
 
-Expr            := PredicateExpr (Semicolon SortExpr)?
+Expr            := (SpatialExpr Semicolon)? PredicateExpr (Semicolon SortExpr)? (Semicolon PageExpr)*
+SpatialExpr     := Function
 PredicateExpr   := Not? Function (And PredicateExpr | Or PredicateExpr)?
-Function        := Identifier OpenParen Args CloseParen
-Args            := Identifier (Comma Identifier)*
+SortExpr        := Function (Comma Function)*
+PageExpr        := PageFunction (Comma PageFunction)*
+PageFunction    := (Limit | Offset) OpenParen Arg CloseParen
+Function        := (Subject Dot)? Identifier OpenParen Args CloseParen
+Args            := Arg (Comma Arg)*
+Arg             := Term ((Plus | Minus) Term)*
+Term            := Factor ((Star | Slash) Factor)*
+Factor          := Minus Factor | Number | String | Bool | Identifier | OpenParen Arg CloseParen
+
+so an argument is an identifier, a literal (2, 0.5, "a string", true), or
+arithmetic on them (2 * x<price>). Note that && and || group to the right,
+so P && Q || R is P && (Q || R).
+
+A SpatialExpr is a leading function named in EFDSLSpatialFunctions (eg.
+Within), which narrows the candidates using the SpatialHasher before the
+predicate runs. A SortExpr of several functions sorts by each in turn, ties
+going to the next. The PageExprs (all merged into one NodePageExpr) skip
+Offset(n) of the sorted results and keep Limit(n) of the rest.
 */
 
+// the functions of a PageExpr
+var efdslPageFunctions = map[string]bool{
+	"Limit":  true,
+	"Offset": true,
+}
+
+// EFDSLSpatialFunctions are the names of the functions which, leading an
+// expression, are parsed as a spatial clause (see
+// EFDSLEvaluator.RegisterSpatials())
+var EFDSLSpatialFunctions = map[string]bool{
+	"Within": true,
+}
+
 type NodeType int
 
 const (
 	NodeExpr NodeType = iota
 	NodePredicateExpr
 	NodeSortExpr
+	NodeSpatialExpr
+	NodePageExpr
 	NodeNot
 	NodeAnd
 	NodeOr
 	NodeFunction
 	NodeIdentifier
+	// a Number, String or Bool, its value in Literal
+	NodeLiteral
+	// Value is the operator (+, -, * or /) and Children the operands
+	NodeArith
 )
 
 var nodeTypeStrings = map[NodeType]string{
 	NodeExpr:          "NodeExpr",
 	NodePredicateExpr: "NodePredicateExpr",
 	NodeSortExpr:      "NodeSortExpr",
+	NodeSpatialExpr:   "NodeSpatialExpr",
+	NodePageExpr:      "NodePageExpr",
 	NodeNot:           "NodeNot",
 	NodeAnd:           "NodeAnd",
 	NodeOr:            "NodeOr",
 	NodeFunction:      "NodeFunction",
 	NodeIdentifier:    "NodeIdentifier",
+	NodeLiteral:       "NodeLiteral",
+	NodeArith:         "NodeArith",
 }
 
 type Node struct {
 	Type     NodeType
 	Value    string
 	Children []*Node
+	// for NodeLiteral, the int, float64, string or bool
+	Literal any
+	// for a NodeFunction called on an entity other than the candidate, the
+	// identifier of the entity, eg. "self" for self.HasTag(ox) ("x" for
+	// x.HasTag(ox) is the same as HasTag(ox))
+	Subject string
+	// where the node starts in the expression
+	Pos scanner.Position
 }
 
 func (n *Node) String() string {
@@ -125,8 +176,46 @@ func (n *Node) String() string {
 			chStr += " , "
 		}
 	}
+	value := n.Value
+	if n.Subject != "" {
+		value = n.Subject + "." + value
+	}
 	return fmt.Sprintf("N{<%s>%s; ch: [%s]}",
-		nodeTypeStrings[n.Type], n.Value, chStr)
+		nodeTypeStrings[n.Type], value, chStr)
+}
+
+// Source gives the text of an argument node, as passed to predicates and
+// sorts: identifiers as they are, strings quoted, and arithmetic
+// parenthesized where it's nested, eg. `2 * (x<price> + 1)`
+func (n *Node) Source() string {
+	switch n.Type {
+	case NodeLiteral:
+		if str, ok := n.Literal.(string); ok {
+			return strconv.Quote(str)
+		}
+		return n.Value
+	case NodeArith:
+		operand := func(ch *Node) string {
+			if ch.Type == NodeArith {
+				return "(" + ch.Source() + ")"
+			}
+			return ch.Source()
+		}
+		return operand(n.Children[0]) + " " + n.Value + " " + operand(n.Children[1])
+	default:
+		return n.Value
+	}
+}
+
+// Clause gives the child of a NodeExpr of the type (NodeSpatialExpr,
+// NodePredicateExpr, NodeSortExpr or NodePageExpr), or nil
+func (n *Node) Clause(t NodeType) *Node {
+	for _, ch := range n.Children {
+		if ch.Type == t {
+			return ch
+		}
+	}
+	return nil
 }
 
 func (n *Node) AddChild(child *Node) {
@@ -138,45 +227,101 @@ type EFDSLParser struct {
 	token EFDSLToken
 }
 
+// Parse returns the AST of the expression, or an *EFDSLError saying where
+// it went wrong
 func (p *EFDSLParser) Parse(input string) (*Node, error) {
+	p.init(input)
+	node, err := p.parseExpr()
+	if err == nil && p.token != EOF {
+		err = p.errorf("unexpected %v after expression", p.token)
+	}
+	return p.result(node, err)
+}
+
+func (p *EFDSLParser) init(input string) {
 	p.lexer = &EFDSLLexer{}
 	p.lexer.Init(strings.NewReader(input))
+	p.lexer.Error = func(s *scanner.Scanner, msg string) {}
 	p.token = p.lexer.Lex()
-	node, err := p.parseExpr()
+}
+
+// the node, or the first error (an illegal character skipped by the lexer
+// comes before the parse error it likely caused)
+func (p *EFDSLParser) result(node *Node, err error) (*Node, error) {
+	if len(p.lexer.illegal) > 0 {
+		illegal := p.lexer.illegal[0]
+		if perr, ok := err.(*EFDSLError); err == nil || (ok && illegal.Offset <= perr.Offset) {
+			return nil, illegal
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
-	if p.token != EOF {
-		return nil, errors.New("unexpected token after expression")
-	}
 	return node, nil
 }
 
+func (p *EFDSLParser) errorf(format string, args ...any) *EFDSLError {
+	return efdslErrorf(p.lexer.TokenPos(), format, args...)
+}
+
 func (p *EFDSLParser) parseExpr() (*Node, error) {
-	node := &Node{Type: NodeExpr}
+	node := &Node{Type: NodeExpr, Pos: p.lexer.TokenPos()}
 	child, err := p.parsePredicateExpr()
 	if err != nil {
 		return nil, err
 	}
-	node.AddChild(child)
-
-	if p.token == Semicolon {
+	// a lone spatial function before a semicolon is the spatial clause
+	if p.token == Semicolon && len(child.Children) == 1 &&
+		child.Children[0].Subject == "" && EFDSLSpatialFunctions[child.Children[0].Value] {
+		node.AddChild(&Node{Type: NodeSpatialExpr, Children: child.Children, Pos: child.Pos})
 		p.token = p.lexer.Lex()
-		child, err := p.parseSortExpr()
+		child, err = p.parsePredicateExpr()
 		if err != nil {
 			return nil, err
 		}
-		node.AddChild(child)
+	}
+	node.AddChild(child)
+
+	for p.token == Semicolon {
+		p.token = p.lexer.Lex()
+		page := p.token == Function && efdslPageFunctions[p.lexer.TokenText()]
+		switch {
+		case page:
+			child, err := p.parseClause(NodePageExpr, func(fn *Node) error {
+				if !efdslPageFunctions[fn.Value] {
+					return efdslErrorf(fn.Pos, "expected Limit or Offset, got %s", fn.Value)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			if existing := node.Clause(NodePageExpr); existing != nil {
+				existing.Children = append(existing.Children, child.Children...)
+			} else {
+				node.AddChild(child)
+			}
+		case node.Clause(NodeSortExpr) == nil && node.Clause(NodePageExpr) == nil:
+			child, err := p.parseSortExpr()
+			if err != nil {
+				return nil, err
+			}
+			node.AddChild(child)
+		case node.Clause(NodeSortExpr) == nil && p.token == Function:
+			return nil, p.errorf("sort should come before Limit and Offset")
+		default:
+			return nil, p.errorf("expected Limit or Offset, got: %v", p.token)
+		}
 	}
 
 	return node, nil
 }
 
 func (p *EFDSLParser) parsePredicateExpr() (*Node, error) {
-	node := &Node{Type: NodePredicateExpr}
+	node := &Node{Type: NodePredicateExpr, Pos: p.lexer.TokenPos()}
 
 	if p.token == Not {
-		node.AddChild(&Node{Type: NodeNot})
+		node.AddChild(&Node{Type: NodeNot, Pos: p.lexer.TokenPos()})
 		p.token = p.lexer.Lex()
 	}
 
@@ -187,7 +332,10 @@ func (p *EFDSLParser) parsePredicateExpr() (*Node, error) {
 	node.AddChild(funcNode)
 
 	if p.token == And || p.token == Or {
-		op := &Node{Type: NodeType(p.token)}
+		op := &Node{Type: NodeAnd, Pos: p.lexer.TokenPos()}
+		if p.token == Or {
+			op.Type = NodeOr
+		}
 		node.AddChild(op)
 		p.token = p.lexer.Lex()
 		child, err := p.parsePredicateExpr()
@@ -201,28 +349,38 @@ func (p *EFDSLParser) parsePredicateExpr() (*Node, error) {
 }
 
 func (p *EFDSLParser) parseFunction() (*Node, error) {
-	if p.token != Function {
-		return nil, fmt.Errorf("expected function, got: %v", p.token)
+	node := &Node{Type: NodeFunction, Value: p.lexer.TokenText(), Pos: p.lexer.TokenPos()}
+	// subject.Function
+	if p.token == Identifier {
+		dot := strings.LastIndex(node.Value, ".")
+		if dot == -1 || dot == len(node.Value)-1 || !unicode.IsUpper(rune(node.Value[dot+1])) {
+			return nil, p.errorf("expected function, got: %v", p.token)
+		}
+		node.Subject, node.Value = node.Value[:dot], node.Value[dot+1:]
+	} else if p.token != Function {
+		return nil, p.errorf("expected function, got: %v", p.token)
 	}
-
-	node := &Node{Type: NodeFunction, Value: p.lexer.TokenText()}
 	p.token = p.lexer.Lex()
 
 	if p.token != OpenParen {
-		return nil, fmt.Errorf("expected open parenthesis, got: %v", p.token)
+		return nil, p.errorf("expected open parenthesis, got: %v", p.token)
 	}
 	p.token = p.lexer.Lex()
 
-	for p.token == Identifier {
-		node.AddChild(&Node{Type: NodeIdentifier, Value: p.lexer.TokenText()})
-		p.token = p.lexer.Lex()
-		if p.token == Comma {
-			p.token = p.lexer.Lex()
+	for p.token != CloseParen && p.token != EOF {
+		arg, err := p.parseArg()
+		if err != nil {
+			return nil, err
 		}
+		node.AddChild(arg)
+		if p.token != Comma {
+			break
+		}
+		p.token = p.lexer.Lex()
 	}
 
 	if p.token != CloseParen {
-		return nil, fmt.Errorf("expected close parenthesis, got: %v", p.token)
+		return nil, p.errorf("expected close parenthesis, got: %v", p.token)
 	}
 	p.token = p.lexer.Lex()
 
@@ -230,11 +388,130 @@ func (p *EFDSLParser) parseFunction() (*Node, error) {
 }
 
 func (p *EFDSLParser) parseSortExpr() (*Node, error) {
-	node := &Node{Type: NodeSortExpr}
-	child, err := p.parseFunction()
+	return p.parseClause(NodeSortExpr, func(fn *Node) error {
+		if efdslPageFunctions[fn.Value] {
+			return efdslErrorf(fn.Pos, "%s should be in a clause of its own, after a semicolon", fn.Value)
+		}
+		return nil
+	})
+}
+
+// a clause of functions separated by commas, each checked by check
+func (p *EFDSLParser) parseClause(t NodeType, check func(fn *Node) error) (*Node, error) {
+	node := &Node{Type: t, Pos: p.lexer.TokenPos()}
+	for {
+		child, err := p.parseFunction()
+		if err != nil {
+			return nil, err
+		}
+		if err := check(child); err != nil {
+			return nil, err
+		}
+		node.AddChild(child)
+		if p.token != Comma {
+			return node, nil
+		}
+		p.token = p.lexer.Lex()
+	}
+}
+
+// ParseArg parses a single function argument, such as the Source() of an
+// argument node
+func (p *EFDSLParser) ParseArg(input string) (*Node, error) {
+	p.init(input)
+	node, err := p.parseArg()
+	if err == nil && p.token != EOF {
+		err = p.errorf("unexpected %v after argument", p.token)
+	}
+	return p.result(node, err)
+}
+
+func (p *EFDSLParser) parseArg() (*Node, error) {
+	return p.parseBinary([]EFDSLToken{Plus, Minus}, p.parseTerm)
+}
+
+func (p *EFDSLParser) parseTerm() (*Node, error) {
+	return p.parseBinary([]EFDSLToken{Star, Slash}, p.parseFactor)
+}
+
+var efdslArithOps = map[EFDSLToken]string{
+	Plus:  "+",
+	Minus: "-",
+	Star:  "*",
+	Slash: "/",
+}
+
+// operands separated by the given operators, grouping to the left
+func (p *EFDSLParser) parseBinary(ops []EFDSLToken, operand func() (*Node, error)) (*Node, error) {
+	left, err := operand()
 	if err != nil {
 		return nil, err
 	}
-	node.AddChild(child)
-	return node, nil
+	for {
+		isOp := false
+		for _, op := range ops {
+			isOp = isOp || p.token == op
+		}
+		if !isOp {
+			return left, nil
+		}
+		op := efdslArithOps[p.token]
+		p.token = p.lexer.Lex()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &Node{Type: NodeArith, Value: op, Children: []*Node{left, right}, Pos: left.Pos}
+	}
+}
+
+func (p *EFDSLParser) parseFactor() (*Node, error) {
+	text := p.lexer.TokenText()
+	pos := p.lexer.TokenPos()
+	switch p.token {
+	case Minus:
+		p.token = p.lexer.Lex()
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		// fold negative numbers into their literal
+		switch x := operand.Literal.(type) {
+		case int:
+			return &Node{Type: NodeLiteral, Value: "-" + operand.Value, Literal: -x, Pos: pos}, nil
+		case float64:
+			return &Node{Type: NodeLiteral, Value: "-" + operand.Value, Literal: -x, Pos: pos}, nil
+		}
+		zero := &Node{Type: NodeLiteral, Value: "0", Literal: 0, Pos: pos}
+		return &Node{Type: NodeArith, Value: "-", Children: []*Node{zero, operand}, Pos: pos}, nil
+	case Number:
+		p.token = p.lexer.Lex()
+		if i, err := strconv.Atoi(text); err == nil {
+			return &Node{Type: NodeLiteral, Value: text, Literal: i, Pos: pos}, nil
+		}
+		f, _ := strconv.ParseFloat(text, 64)
+		return &Node{Type: NodeLiteral, Value: text, Literal: f, Pos: pos}, nil
+	case String:
+		p.token = p.lexer.Lex()
+		return &Node{Type: NodeLiteral, Value: text, Literal: text, Pos: pos}, nil
+	case Bool:
+		p.token = p.lexer.Lex()
+		return &Node{Type: NodeLiteral, Value: text, Literal: text == "true", Pos: pos}, nil
+	case Identifier:
+		p.token = p.lexer.Lex()
+		return &Node{Type: NodeIdentifier, Value: text, Pos: pos}, nil
+	case OpenParen:
+		p.token = p.lexer.Lex()
+		node, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		if p.token != CloseParen {
+			return nil, p.errorf("expected close parenthesis, got: %v", p.token)
+		}
+		p.token = p.lexer.Lex()
+		return node, nil
+	default:
+		return nil, p.errorf("expected argument, got: %v", p.token)
+	}
 }
@@ -4,30 +4,15 @@ func EFDSLPredicatesBase(e *EFDSLEvaluator) EFDSLPredicateMap {
 
 	return EFDSLPredicateMap{
 
-		//TODO
-		// generic Eq
-		// generic Lt, Le, Gt, Ge
-		/*
-			signature IdentResolve<int>,IdentResolve<int>
-			"Gt(self<martialarts.skill>, mind.martialarts.prospectiveOpponent<martialarts.skill>)"
-
-			signature IdentResolve<int>,IdentResolve<int>
-			Lt(mind.trading.lowestBargain, mind.trading.other.offer)
-		*/
-
-		// we want to be able to do something like:
-		// Eq(self<martialarts.skill>, <martialarts.skill>)
-		// and overload for whether these are bool,bool, int,int, etc.
-		/*
-			"Eq": e.Predicate(
-				"what EFDSL signature string goes here?"
-				func(    TODO: what func signature goes here?     ) func(*Entity) bool {
-					return func(x *Entity) bool {
-						// what code happens in here?
-					}
-				},
-			),
-		*/
+		// comparisons of operands: literals, identifiers, or arithmetic on
+		// them (see efdsl_operands.go), eg.
+		// Gt(self<martialarts.skill>, mind.opponent<martialarts.skill>)
+		"Eq": e.efdslComparison("Eq", false, func(cmp int) bool { return cmp == 0 }),
+		"Ne": e.efdslComparison("Ne", false, func(cmp int) bool { return cmp != 0 }),
+		"Lt": e.efdslComparison("Lt", true, func(cmp int) bool { return cmp < 0 }),
+		"Le": e.efdslComparison("Le", true, func(cmp int) bool { return cmp <= 0 }),
+		"Gt": e.efdslComparison("Gt", true, func(cmp int) bool { return cmp > 0 }),
+		"Ge": e.efdslComparison("Ge", true, func(cmp int) bool { return cmp >= 0 }),
 
 		"CanBe": e.Predicate(
 			"string, int",
@@ -107,6 +92,9 @@ func EFDSLPredicatesBase(e *EFDSLEvaluator) EFDSLPredicateMap {
 		),
 
 		"RectOverlap": func(args []string, resolver IdentifierResolver) func(*Entity) bool {
+			if efdslSignatures(resolver, "Vec2D, Vec2D, Vec2D, Vec2D") {
+				return nil
+			}
 			argsTyped, err := DSLAssertArgTypes("Vec2D, Vec2D, Vec2D, Vec2D", args, resolver)
 			if err != nil {
 				logDSLError("%s", err)
@@ -0,0 +1,194 @@
+package sameriver
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+/*
+EFDSLQuery is a parsed EFDSL expression, which can be run any number of
+times with different resolvers without parsing it again, eg.
+
+	q, err := w.EFDSL.Compile("Within(self, 50); HasTag(food); Closest(self)")
+	...
+	food := q.FilterSort(&EntityResolver{e: e, w: w})
+
+If the expression has a spatial clause, the candidates are the entities it
+finds using the SpatialHasher rather than all entities. Compile() caches
+queries by expression text (the last EFDSL_QUERY_CACHE_SIZE used), so
+callers evaluating the same expressions every frame needn't keep the
+queries themselves.
+*/
+type EFDSLQuery struct {
+	Expr string
+	AST  *Node
+	e    *EFDSLEvaluator
+}
+
+// Compile parses the expression, or returns the cached query
+func (e *EFDSLEvaluator) Compile(expr string) (*EFDSLQuery, error) {
+	if q, ok := e.queries.Get(expr); ok {
+		return q, nil
+	}
+	ast, err := (&EFDSLParser{}).Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expr: %s", err)
+	}
+	if err := e.checkRegistered(ast); err != nil {
+		return nil, err
+	}
+	q := &EFDSLQuery{Expr: expr, AST: ast, e: e}
+	e.queries.Put(expr, q)
+	return q, nil
+}
+
+// checkRegistered checks that the functions of the expression are
+// registered (whether their args resolve depends on the resolver, so is
+// only known when it's evaluated, upon which a predicate which can't be
+// made matches nothing)
+func (e *EFDSLEvaluator) checkRegistered(ast *Node) error {
+	for _, clause := range ast.Children {
+		switch clause.Type {
+		case NodeSpatialExpr:
+			name := clause.Children[0].Value
+			if _, ok := e.spatials[name]; !ok {
+				return fmt.Errorf("spatial clause %s is not registered", name)
+			}
+		case NodeSortExpr:
+			for _, fn := range clause.Children {
+				if _, ok := e.sorts[fn.Value]; !ok {
+					return fmt.Errorf("sort %s is not registered", fn.Value)
+				}
+			}
+		case NodePredicateExpr:
+			if err := e.checkPredicatesRegistered(clause); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *EFDSLEvaluator) checkPredicatesRegistered(n *Node) error {
+	for _, ch := range n.Children {
+		switch ch.Type {
+		case NodeFunction:
+			if _, ok := e.predicates[ch.Value]; !ok {
+				return fmt.Errorf("predicate %s is not registered", ch.Value)
+			}
+		case NodeAnd, NodeOr:
+			if err := e.checkPredicatesRegistered(ch.Children[0]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetQueryCacheSize sets how many compiled queries are kept
+func (e *EFDSLEvaluator) SetQueryCacheSize(size int) {
+	e.queries.Resize(size)
+}
+
+// Spatial is whether the query narrows its candidates by a spatial clause
+func (q *EFDSLQuery) Spatial() bool {
+	return q.AST.Clause(NodeSpatialExpr) != nil
+}
+
+// Evaluate gives the filter (including the spatial clause, if any) and sort
+// (or nil) of the query, as EFDSLEvaluator.Evaluate()
+func (q *EFDSLQuery) Evaluate(resolver IdentifierResolver) (func(*Entity) bool, func(xs []*Entity) func(i, j int) bool) {
+	return q.e.Evaluate(q.AST, resolver)
+}
+
+// Filter gives the active entities matching the query, unsorted
+func (q *EFDSLQuery) Filter(resolver IdentifierResolver) []*Entity {
+	filter := q.e.evaluateFilter(q.AST.Clause(NodePredicateExpr), resolver)
+	spatialNode := q.AST.Clause(NodeSpatialExpr)
+	if spatialNode == nil {
+		return q.e.w.FilterAllEntities(filter)
+	}
+	candidates, _ := q.e.evaluateSpatial(spatialNode, resolver)
+	results := make([]*Entity, 0)
+	for _, x := range candidates() {
+		if filter(x) {
+			results = append(results, x)
+		}
+	}
+	return results
+}
+
+// FilterSort gives the active entities matching the query, sorted if it
+// has a sort and paged if it has Offset() or Limit(). With a limit, only
+// the entities on the page are fully sorted
+func (q *EFDSLQuery) FilterSort(resolver IdentifierResolver) []*Entity {
+	results := q.Filter(resolver)
+	offset, limit := 0, -1
+	if pageNode := q.AST.Clause(NodePageExpr); pageNode != nil {
+		offset, limit = q.e.evaluatePage(pageNode, resolver)
+	}
+	var less func(i, j int) bool
+	if sortNode := q.AST.Clause(NodeSortExpr); sortNode != nil {
+		if sortf := q.e.evaluateSort(sortNode, resolver); sortf != nil {
+			less = sortf(results)
+		}
+	}
+	switch {
+	case less == nil:
+	case limit == -1 || offset+limit >= len(results):
+		sort.Slice(results, less)
+	default:
+		results = efdslPartialSort(results, less, offset+limit)
+	}
+	return efdslPage(results, offset, limit)
+}
+
+// the first n of xs as sorted by less, keeping the n least in a max-heap
+// (so O(len(xs) log n) rather than sorting them all)
+func efdslPartialSort(xs []*Entity, less func(i, j int) bool, n int) []*Entity {
+	h := &efdslIndexHeap{less: less}
+	for i := range xs {
+		if h.Len() < n {
+			heap.Push(h, i)
+		} else if n > 0 && less(i, h.ixs[0]) {
+			h.ixs[0] = i
+			heap.Fix(h, 0)
+		}
+	}
+	sort.Slice(h.ixs, func(a, b int) bool {
+		return less(h.ixs[a], h.ixs[b])
+	})
+	result := make([]*Entity, len(h.ixs))
+	for k, i := range h.ixs {
+		result[k] = xs[i]
+	}
+	return result
+}
+
+// indices of entities, greatest first
+type efdslIndexHeap struct {
+	ixs  []int
+	less func(i, j int) bool
+}
+
+func (h *efdslIndexHeap) Len() int           { return len(h.ixs) }
+func (h *efdslIndexHeap) Less(a, b int) bool { return h.less(h.ixs[b], h.ixs[a]) }
+func (h *efdslIndexHeap) Swap(a, b int)      { h.ixs[a], h.ixs[b] = h.ixs[b], h.ixs[a] }
+func (h *efdslIndexHeap) Push(x any)         { h.ixs = append(h.ixs, x.(int)) }
+func (h *efdslIndexHeap) Pop() any {
+	x := h.ixs[len(h.ixs)-1]
+	h.ixs = h.ixs[:len(h.ixs)-1]
+	return x
+}
+
+func efdslPage(xs []*Entity, offset int, limit int) []*Entity {
+	if offset >= len(xs) {
+		return xs[:0]
+	}
+	xs = xs[offset:]
+	if limit != -1 && limit < len(xs) {
+		xs = xs[:limit]
+	}
+	return xs
+}
@@ -27,7 +27,14 @@ or
 "mind.friend<mood>" (state access),
 
 ... allows the user to access specific components or state values associated with
-the entity.
+the entity, and fields of them, like "self[position].X" or "x[needs].hunger"
+(see efdslAccess()).
+
+"x" refers to the candidate entity being tested, like "x<health>", and is
+bound by the evaluator as each candidate is tested, so an argument referring
+to x is resolved for each candidate rather than once. A predicate can also
+be called on an entity other than the candidate, like "self.HasTag(ox)" or
+"mind.friend.HasTag(ox)" ("x.HasTag(ox)" is just "HasTag(ox)").
 
 This file contains two resolver types: EntityResolver and
 WorldResolver, which implement the IdentifierResolver interface and provide
@@ -65,13 +72,27 @@ type WorldResolver struct {
 }
 
 func valueOrEntityAccess(w *World, value any, identifier string) any {
-	bracket := ""
-	switch {
-	case strings.ContainsRune(identifier, '['):
-		bracket = "["
-	case strings.ContainsRune(identifier, '<'):
-		bracket = "<"
-	default:
+	return efdslAccess(w, value, identifier, true)
+}
+
+/*
+efdslAccess applies the accessor and fields of identifier to value, the
+resolved object, eg. for
+
+	mind.friend<mood>     the state key mood of the entity mind.friend
+	self[position].X      X of the position component of self
+	x[needs].hunger       the key hunger of the IntMap/FloatMap/StringMap
+	                      component needs of the candidate x
+
+Component names are matched as registered, or else in upper case
+("position" is POSITION). If complain, missing components and state keys
+are logged as errors; they're expected of some candidates x, so aren't for
+those.
+*/
+func efdslAccess(w *World, value any, identifier string, complain bool) any {
+	object := efdslObject(identifier)
+	rest := identifier[len(object):]
+	if rest == "" {
 		// if identifier matches bb.* return the value from the blackboard
 		if strings.HasPrefix(identifier, "bb.") {
 			accessor := identifier[3:]
@@ -83,49 +104,181 @@ func valueOrEntityAccess(w *World, value any, identifier string) any {
 		return value
 	}
 
-	split := strings.Split(identifier, bracket)
-	object, accessor := split[0], split[1]
-	accessor = accessor[:len(accessor)-1]
+	bracket := rest[0]
+	closing := map[byte]string{'[': "]", '<': ">"}[bracket]
+	end := strings.Index(rest, closing)
+	if end == -1 {
+		logDSLError("unterminated accessor in DSL expression \"%s\"", identifier)
+		return nil
+	}
+	accessor, fields := rest[1:end], rest[end+1:]
+	if fields != "" && fields[0] != '.' {
+		logDSLError("expected .field after accessor in DSL expression \"%s\"", identifier)
+		return nil
+	}
 
 	entity, entityOk := value.(*Entity)
-	if !entityOk {
-		logDSLError("for expression %s, what appears to be entity access notation did not have an entity as its object (%s is not an entity)", identifier, object)
+	if !entityOk || entity == nil {
+		if complain {
+			logDSLError("for expression %s, what appears to be entity access notation did not have an entity as its object (%s is not an entity)", identifier, object)
+		}
 		return nil
 	}
 
 	switch bracket {
-	case "[":
+	case '[':
 		ct := w.Em.ComponentsTable
 		componentID, ok := ct.StringsRev[accessor]
+		if !ok {
+			componentID, ok = ct.StringsRev[strings.ToUpper(accessor)]
+		}
 		if !ok {
 			logDSLError("Component %s doesn't exist for DSL expression \"%s\"", accessor, identifier)
 			return nil
 		}
-		return w.GetVal(entity, componentID)
-	case "<":
+		if !w.EntityHasComponent(entity, componentID) {
+			if complain {
+				logDSLError("Entity %s doesn't have component %s to resolve DSL expression \"%s\"", entity, accessor, identifier)
+			}
+			return nil
+		}
+		value = w.GetVal(entity, componentID)
+	case '<':
 		key := accessor
-		state := w.GetIntMap(entity, STATE_)
-		if !state.Has(key) {
-			logDSLError("Entity %s doesn't have state key %s to resolve DSL expression \"%s\"", entity, accessor, identifier)
+		if !w.EntityHasComponent(entity, STATE_) || !w.GetIntMap(entity, STATE_).Has(key) {
+			if complain {
+				logDSLError("Entity %s doesn't have state key %s to resolve DSL expression \"%s\"", entity, accessor, identifier)
+			}
 			return nil
 		}
-		return state.Get(key)
+		value = w.GetIntMap(entity, STATE_).Get(key)
+	}
+
+	if fields != "" {
+		for _, field := range strings.Split(fields[1:], ".") {
+			value = efdslField(value, field)
+			if value == nil {
+				if complain {
+					logDSLError("can't access field %s for DSL expression \"%s\"", field, identifier)
+				}
+				return nil
+			}
+		}
 	}
+	return value
+}
 
+// efdslField gives a field of a Vec2D (X or Y) or the value of a key of an
+// IntMap, FloatMap, StringMap or map[string]any, or nil
+func efdslField(value any, field string) any {
+	switch v := value.(type) {
+	case *Vec2D:
+		return efdslField(*v, field)
+	case Vec2D:
+		switch field {
+		case "X", "x":
+			return v.X
+		case "Y", "y":
+			return v.Y
+		}
+	case *IntMap:
+		if v.Has(field) {
+			return v.Get(field)
+		}
+	case *FloatMap:
+		if v.Has(field) {
+			return v.Get(field)
+		}
+	case *StringMap:
+		if s, ok := v.M[field]; ok {
+			return s
+		}
+	case map[string]any:
+		return v[field]
+	}
 	return nil
 }
 
+// efdslIsX is whether an identifier refers to the candidate entity x
+func efdslIsX(identifier string) bool {
+	object := efdslObject(identifier)
+	return object == "x" || strings.HasPrefix(object, "x.")
+}
+
+// efdslRefersToX is whether an argument's Source() refers to x anywhere
+func efdslRefersToX(arg string) bool {
+	var l EFDSLLexer
+	l.Init(strings.NewReader(arg))
+	for tok := l.Lex(); tok != EOF; tok = l.Lex() {
+		if tok == Identifier && efdslIsX(l.TokenText()) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+efdslCandidateResolver resolves identifiers referring to "x" as the
+candidate entity being tested by a predicate (so it's made anew for each
+candidate), and the rest with the resolver the expression was evaluated
+with, eg.
+
+	x                 the candidate's ID (like self)
+	x<health>         its state key health
+	x[position].X     X of its position
+*/
+type efdslCandidateResolver struct {
+	IdentifierResolver
+	w *World
+	x *Entity
+}
+
+func (r *efdslCandidateResolver) Resolve(identifier string) any {
+	if efdslIsX(identifier) {
+		return efdslResolveX(r.w, r.x, identifier)
+	}
+	return r.IdentifierResolver.Resolve(identifier)
+}
+
+// like "self", a bare "x" resolves to the entity's ID
+func efdslResolveX(w *World, x *Entity, identifier string) any {
+	if identifier == "x" {
+		return x.ID
+	}
+	return efdslAccess(w, x, identifier, false)
+}
+
+// efdslEntity gives the entity of a resolved value, which may be an ID
+// (as "self" resolves to)
+func efdslEntity(w *World, value any) *Entity {
+	switch v := value.(type) {
+	case *Entity:
+		return v
+	case int:
+		return w.GetEntity(v)
+	}
+	return nil
+}
+
+// the identifier without its accessor, eg. "mind.friend" for
+// "mind.friend<mood>"
+func efdslObject(identifier string) string {
+	if i := strings.IndexAny(identifier, "[<"); i != -1 {
+		return identifier[:i]
+	}
+	return identifier
+}
+
 func (er *EntityResolver) Resolve(identifier string) any {
-	parts := strings.SplitN(identifier, ".", 2)
+	parts := strings.SplitN(efdslObject(identifier), ".", 2)
 
 	switch parts[0] {
 	case "x":
-		if len(parts) > 1 {
-			// what do we do here? how do we do valueOrEntityAccess on x? we don't have it
-		}
-		// TODO: what do we return here? we don't have access to x?
+		// x, the candidate entity, is bound by the evaluator as each
+		// candidate is tested (see efdslCandidateResolver)
+		return nil
 	case "self":
-		if len(parts) > 1 {
+		if identifier != "self" {
 			return valueOrEntityAccess(er.w, er.e, identifier)
 		}
 		return er.e.ID
@@ -149,7 +302,7 @@ func (er *EntityResolver) Resolve(identifier string) any {
 }
 
 func (wr *WorldResolver) Resolve(identifier string) any {
-	parts := strings.SplitN(identifier, ".", 2)
+	parts := strings.SplitN(efdslObject(identifier), ".", 2)
 
 	if parts[0] == "bb" {
 		if len(parts) > 1 {
@@ -13,18 +13,104 @@ func EFDSLSortsBase(e *EFDSLEvaluator) EFDSLSortMap {
 
 	return EFDSLSortMap{
 
-		"Closest": func(args []string, resolver IdentifierResolver) func(xs []*Entity) func(i, j int) bool {
+		"Closest":  e.efdslDistanceSort(false),
+		"Farthest": e.efdslDistanceSort(true),
+
+		// by the value of an operand (see efdsl_operands.go) for each
+		// entity, eg. Desc(x<gold>), Asc(x[position].X); numbers or
+		// strings, entities it doesn't resolve for going last
+		"Asc":  e.efdslValueSort("Asc", false),
+		"Desc": e.efdslValueSort("Desc", true),
+
+		// a shuffle which is the same for the same seed, eg. Random(7),
+		// Random(mind.seed)
+		"Random": func(args []string, resolver IdentifierResolver) func(xs []*Entity) func(i, j int) bool {
+			if efdslSignatures(resolver, "IdentResolve<int>") {
+				return nil
+			}
 			argsTyped, err := DSLAssertArgTypes("IdentResolve<int>", args, resolver)
 			if err != nil {
 				logDSLError("%s", err)
+				return nil
 			}
-			poleID := argsTyped[0].(int)
-			pole := e.w.GetEntity(poleID)
+			seed := argsTyped[0].(int)
 			return func(xs []*Entity) func(i, j int) bool {
 				return func(i, j int) bool {
-					return e.w.EntityDistanceFrom(xs[i], pole) < e.w.EntityDistanceFrom(xs[j], pole)
+					return efdslRandomKey(seed, xs[i].ID) < efdslRandomKey(seed, xs[j].ID)
 				}
 			}
 		},
 	}
 }
+
+func (e *EFDSLEvaluator) efdslDistanceSort(farthest bool) EFDSLSort {
+	return func(args []string, resolver IdentifierResolver) func(xs []*Entity) func(i, j int) bool {
+		if efdslSignatures(resolver, "IdentResolve<int>") {
+			return nil
+		}
+		argsTyped, err := DSLAssertArgTypes("IdentResolve<int>", args, resolver)
+		if err != nil {
+			logDSLError("%s", err)
+			return nil
+		}
+		poleID := argsTyped[0].(int)
+		pole := e.w.GetEntity(poleID)
+		return func(xs []*Entity) func(i, j int) bool {
+			return func(i, j int) bool {
+				if farthest {
+					return e.w.EntityDistanceFrom(xs[i], pole) > e.w.EntityDistanceFrom(xs[j], pole)
+				}
+				return e.w.EntityDistanceFrom(xs[i], pole) < e.w.EntityDistanceFrom(xs[j], pole)
+			}
+		}
+	}
+}
+
+func (e *EFDSLEvaluator) efdslValueSort(name string, desc bool) EFDSLSort {
+	return func(args []string, resolver IdentifierResolver) func(xs []*Entity) func(i, j int) bool {
+		if efdslSignatures(resolver, EFDSL_OPERAND) {
+			return nil
+		}
+		if len(args) != 1 {
+			logDSLError("%s takes 1 operand, got %d", name, len(args))
+			return nil
+		}
+		operand, err := e.Operand(args[0], resolver)
+		if err != nil {
+			logDSLError("%s: %s", name, err)
+			return nil
+		}
+		return func(xs []*Entity) func(i, j int) bool {
+			// each entity's value, resolved once
+			values := make(map[*Entity]any, len(xs))
+			value := func(x *Entity) any {
+				v, ok := values[x]
+				if !ok {
+					v = operand(x)
+					values[x] = v
+				}
+				return v
+			}
+			return func(i, j int) bool {
+				a, b := value(xs[i]), value(xs[j])
+				cmp, ok := efdslCompare(a, b, true)
+				if !ok {
+					// those with a value go before those without
+					return a != nil && b == nil
+				}
+				if desc {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+		}
+	}
+}
+
+// a hash of the seed and ID (splitmix64)
+func efdslRandomKey(seed int, id int) uint64 {
+	z := uint64(seed)*0x9e3779b97f4a7c15 + uint64(id)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
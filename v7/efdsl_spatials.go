@@ -0,0 +1,91 @@
+package sameriver
+
+import "strings"
+
+// EFDSLSpatialsBase are the spatial clauses, which narrow the candidates of
+// an expression using the SpatialHasher, eg.
+//
+//	Within(self, 50); HasTag(food); Closest(self)
+//	Within(bb.village.center, bb.village.size, 100); HasTag(wolf)
+//
+// Since the SpatialHasher is updated in World.Update(), entities spawned or
+// moved into range since then may be missed (though the distance itself is
+// measured from their current position)
+func EFDSLSpatialsBase(e *EFDSLEvaluator) EFDSLSpatialMap {
+
+	return EFDSLSpatialMap{
+
+		"Within": func(args []string, resolver IdentifierResolver) (func() []*Entity, func(*Entity) bool) {
+			if efdslSignatures(resolver, "IdentResolve<int>, float64", "IdentResolve<Vec2D>, IdentResolve<Vec2D>, float64") {
+				return nil, nil
+			}
+			var pos, box *Vec2D
+			var d float64
+			if len(args) == 2 {
+				argsTyped, err := DSLAssertArgTypes("IdentResolve<int>, float64", args, resolver)
+				if err != nil {
+					logDSLError("%s", err)
+					return efdslNowhere()
+				}
+				y := e.w.GetEntity(argsTyped[0].(int))
+				if y == nil || !e.w.EntityHasComponents(y, POSITION_, BOX_) {
+					logDSLError("Within(%s, ...): entity has no position", args[0])
+					return efdslNowhere()
+				}
+				pos, box, d = e.w.GetVec2D(y, POSITION_), e.w.GetVec2D(y, BOX_), argsTyped[1].(float64)
+			} else {
+				// the position and box may resolve to a Vec2D or, as
+				// components, a *Vec2D
+				argsTyped, err := DSLAssertArgTypes("string, string, float64", args, resolver)
+				if err != nil {
+					logDSLError("%s", err)
+					return efdslNowhere()
+				}
+				var posOk, boxOk bool
+				pos, posOk = efdslVec2D(resolver.Resolve(args[0]))
+				box, boxOk = efdslVec2D(resolver.Resolve(args[1]))
+				if !posOk || !boxOk {
+					logDSLError("Within(%s): expected a position and box", strings.Join(args, ", "))
+					return efdslNowhere()
+				}
+				d = argsTyped[2].(float64)
+			}
+			candidates := func() []*Entity {
+				return e.w.EntitiesWithinDistanceFilter(*pos, *box, d, func(x *Entity) bool {
+					return x.Active
+				})
+			}
+			// the same test as the SpatialHasher's
+			filter := func(x *Entity) bool {
+				if !e.w.EntityHasComponents(x, POSITION_, BOX_) {
+					return false
+				}
+				xPos, xBox := *e.w.GetVec2D(x, POSITION_), *e.w.GetVec2D(x, BOX_)
+				return RectWithinDistanceOfRect(
+					pos.ShiftedCenterToBottomLeft(*box), *box,
+					xPos.ShiftedCenterToBottomLeft(xBox), xBox,
+					d)
+			}
+			return candidates, filter
+		},
+	}
+}
+
+func efdslVec2D(v any) (*Vec2D, bool) {
+	switch v := v.(type) {
+	case *Vec2D:
+		return v, v != nil
+	case Vec2D:
+		return &v, true
+	}
+	return nil, false
+}
+
+// a spatial clause which couldn't be evaluated matches nothing
+func efdslNowhere() (func() []*Entity, func(*Entity) bool) {
+	return func() []*Entity {
+			return []*Entity{}
+		}, func(*Entity) bool {
+			return false
+		}
+}
@@ -2,6 +2,7 @@ package sameriver
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -82,3 +83,506 @@ func TestEFDSLEvaluator(t *testing.T) {
 	}
 	Logger.Printf("result of HasTags(ox): %v", result)
 }
+
+func TestEFDSLLexerLiterals(t *testing.T) {
+	var l EFDSLLexer
+	l.Init(strings.NewReader(`Gt(self<gold>, 2 * mind.price - 0.5); Eq("say \"hi\"", true)`))
+	tokens := make([]string, 0)
+	for tok := l.Lex(); tok != EOF; tok = l.Lex() {
+		tokens = append(tokens, fmt.Sprintf("%s:%s", tok, l.TokenText()))
+	}
+	expected := "Function:Gt OpenParen: Identifier:self<gold> Comma: Number:2 Star: " +
+		"Identifier:mind.price Minus: Number:0.5 CloseParen: Semicolon: " +
+		`Function:Eq OpenParen: String:say "hi" Comma: Bool:true CloseParen:`
+	if strings.Join(tokens, " ") != expected {
+		t.Fatalf("unexpected tokens: %s", strings.Join(tokens, " "))
+	}
+}
+
+func TestEFDSLParserArith(t *testing.T) {
+	for arg, source := range map[string]string{
+		`2 + 3 * x`:        `2 + (3 * x)`,
+		`(2 + 3) * x`:      `(2 + 3) * x`,
+		`a - b - c`:        `(a - b) - c`,
+		`-1.5 * -mind.n`:   `-1.5 * (0 - mind.n)`,
+		` "a, b" `:         `"a, b"`,
+		`self[position]/2`: `self[position] / 2`,
+	} {
+		n, err := (&EFDSLParser{}).ParseArg(arg)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %s", arg, err)
+		}
+		if n.Source() != source {
+			t.Fatalf("%s should parse as %s, got %s", arg, source, n.Source())
+		}
+	}
+	for _, expr := range []string{`Eq(1 +, 2)`, `Eq((1, 2)`, `Eq("unterminated, 2)`} {
+		if _, err := (&EFDSLParser{}).Parse(expr); err == nil {
+			t.Fatalf("%s should fail to parse", expr)
+		}
+	}
+}
+
+func TestEFDSLComparisons(t *testing.T) {
+	w := testingWorld()
+	w.CreateBlackboard("village").Set("alarm", true)
+	self := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			STATE_: map[string]int{"gold": 10},
+		},
+		"tags": []string{"merchant"},
+	})
+	self.Mind.Set("price", 4)
+	self.Mind.Set("discount", 0.5)
+	self.Mind.Set("name", "Bob")
+	self.Mind.Set("target", self)
+	ox := w.Spawn(map[string]any{"tags": []string{"ox"}})
+
+	for expr, expected := range map[string]bool{
+		`Gt(self<gold>, 2 * mind.price)`:                true,
+		`Gt(self<gold>, 3 * mind.price)`:                false,
+		`Ge(self<gold>, 2 * (mind.price + 1))`:          true,
+		`Lt(self<gold> * mind.discount, mind.price)`:    false,
+		`Le(self<gold> / 4, 2.5)`:                       true,
+		`Eq(mind.price - 5, -1)`:                        true,
+		`Eq(mind.name, "Bob")`:                          true,
+		`Lt(mind.name, "Alice")`:                        false,
+		`Eq(mind.name + "!", "Bob!")`:                   true,
+		`Eq(bb.village.alarm, true)`:                    true,
+		`Ne(bb.village.alarm, false)`:                   true,
+		`Eq(mind.target, self)`:                         true,
+		`Ne(mind.target, self)`:                         false,
+		`Lt(mind.name, 2)`:                              false,
+		`Lt(bb.village.alarm, true)`:                    false,
+		`Eq(mind.missing, mind.missing)`:                false,
+		`Eq(mind.price / 0, 1)`:                         false,
+		`!Gt(self<gold>, 2 * mind.price)`:               false,
+		`!Gt(self<gold>, 3 * mind.price)`:               true,
+		`Gt(self<gold>, 100) \|\| Eq(mind.name, "Bob")`: true,
+	} {
+		expr = strings.ReplaceAll(expr, `\|\|`, "||")
+		result, err := w.EFDSLFilterEntity(self, expr+` && HasTag("ox")`)
+		if err != nil {
+			t.Fatalf("failed to evaluate %s: %s", expr, err)
+		}
+		if (len(result) == 1 && result[0] == ox) != expected {
+			t.Fatalf("%s should be %t, got %v", expr, expected, result)
+		}
+	}
+}
+
+func TestEFDSLCandidateX(t *testing.T) {
+	w := testingWorld()
+	const (
+		NEEDS_ = GENERICTAGS_ + 1 + iota
+		SKILLS_
+		NAMES_
+	)
+	w.RegisterComponents([]any{
+		NEEDS_, INTMAP, "NEEDS",
+		SKILLS_, FLOATMAP, "SKILLS",
+		NAMES_, STRINGMAP, "NAMES",
+	})
+	self := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{0, 0},
+			BOX_:      Vec2D{1, 1},
+			STATE_:    map[string]int{"gold": 10},
+			SKILLS_:   map[string]float64{"fishing": 0.5},
+		},
+		"tags": []string{"merchant"},
+	})
+	spawnOx := func(x float64, price int, hunger int, name string) *Entity {
+		return w.Spawn(map[string]any{
+			"components": map[ComponentID]any{
+				POSITION_: Vec2D{x, 0},
+				BOX_:      Vec2D{1, 1},
+				STATE_:    map[string]int{"price": price},
+				NEEDS_:    map[string]int{"hunger": hunger},
+				SKILLS_:   map[string]float64{"fishing": x / 100},
+				NAMES_:    map[string]string{"given": name},
+			},
+			"tags": []string{"ox"},
+		})
+	}
+	cheap := spawnOx(10, 3, 5, "Babe")
+	dear := spawnOx(50, 8, 0, "Blue")
+	// no components to access, so never matches x accessors
+	poor := w.Spawn(map[string]any{"tags": []string{"ox"}})
+	self.Mind.Set("favourite", dear)
+
+	for expr, expected := range map[string][]*Entity{
+		`Ge(self<gold>, 2 * x<price>)`:                                    {cheap},
+		`Lt(x<price>, self<gold>)`:                                        {cheap, dear},
+		`Gt(x[position].X, 20)`:                                           {dear},
+		`HasTag(ox) && Le(x[POSITION].x, self[position].X + 10)`:          {cheap},
+		`Gt(x[needs].hunger, 0)`:                                          {cheap},
+		`HasTag(ox) && Gt(x[skills].fishing, self[skills].fishing - 0.1)`: {dear},
+		`Eq(x[names].given, "Babe")`:                                      {cheap},
+		`Eq(x, mind.favourite)`:                                           {dear},
+		`x.HasTag(ox) && Ne(x, mind.favourite) && Gt(x<price>, 0)`:        {cheap},
+		`self.HasTag(merchant) && Eq(x<price>, 8)`:                        {dear},
+		`self.HasTag(ox) && Eq(x<price>, 8)`:                              {},
+		`mind.favourite.HasTag(ox) && HasTag(ox) && Lt(x<price>, 5)`:      {cheap},
+		`Is(x) && HasTag(ox) && Ne(x, self)`:                              {cheap, dear, poor},
+		`HasTag(ox) && State(price, 3)`:                                   {cheap},
+	} {
+		result, err := w.EFDSLFilterEntity(self, expr)
+		if err != nil {
+			t.Fatalf("failed to evaluate %s: %s", expr, err)
+		}
+		if len(result) != len(expected) {
+			t.Fatalf("%s should match %v, got %v", expr, expected, result)
+		}
+		for _, e := range expected {
+			found := false
+			for _, r := range result {
+				found = found || r == e
+			}
+			if !found {
+				t.Fatalf("%s should match %v, got %v", expr, expected, result)
+			}
+		}
+	}
+}
+
+func TestEFDSLQuerySpatial(t *testing.T) {
+	w := testingWorld()
+	spawn := func(pos Vec2D, tags ...string) *Entity {
+		return w.Spawn(map[string]any{
+			"components": map[ComponentID]any{
+				POSITION_: pos,
+				BOX_:      Vec2D{1, 1},
+			},
+			"tags": tags,
+		})
+	}
+	self := spawn(Vec2D{0, 0})
+	far := spawn(Vec2D{0, 30}, "food")
+	near := spawn(Vec2D{0, 10}, "food")
+	spawn(Vec2D{0, 100}, "food")
+	spawn(Vec2D{0, 5}, "rock")
+	w.SpatialHasher.Update()
+
+	expr := "Within(self, 50); HasTag(food); Closest(self)"
+	ast, err := (&EFDSLParser{}).Parse(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ast.Clause(NodeSpatialExpr) == nil || ast.Clause(NodeSortExpr) == nil {
+		t.Fatalf("should parse the spatial clause, got %s", ast)
+	}
+	q, err := w.EFDSL.Compile(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver := &EntityResolver{e: self, w: w}
+	result := q.FilterSort(resolver)
+	if len(result) != 2 || result[0] != near || result[1] != far {
+		t.Fatalf("should find the food within 50, closest first, got %v", result)
+	}
+	// the candidates come from the hasher, as of its last update
+	late := spawn(Vec2D{0, 20}, "food")
+	if len(q.Filter(resolver)) != 2 {
+		t.Fatal("entity spawned since the hasher's update shouldn't be a candidate")
+	}
+	// evaluated as a filter, the clause is a distance test
+	filter, _ := q.Evaluate(resolver)
+	if !filter(late) || filter(w.GetEntity(3)) {
+		t.Fatal("filter should test the distance")
+	}
+	w.SpatialHasher.Update()
+	if result, _ := w.EFDSLFilterSortEntity(self, expr); len(result) != 3 || result[1] != late {
+		t.Fatalf("should find the late food once the hasher updates, got %v", result)
+	}
+	// inactive entities aren't candidates
+	w.Despawn(near)
+	if result := q.Filter(resolver); len(result) != 2 {
+		t.Fatalf("despawned entity shouldn't be a candidate, got %v", result)
+	}
+	// a function which isn't spatial leading the expression is the predicate
+	ast, _ = (&EFDSLParser{}).Parse("HasTag(food); Closest(self)")
+	if ast.Clause(NodeSpatialExpr) != nil {
+		t.Fatal("HasTag shouldn't parse as a spatial clause")
+	}
+}
+
+func TestEFDSLQueryCache(t *testing.T) {
+	w := testingWorld()
+	q1, err := w.EFDSL.Compile("HasTag(ox)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q2, _ := w.EFDSL.Compile("HasTag(ox)")
+	if q1 != q2 {
+		t.Fatal("should reuse the compiled query")
+	}
+	w.EFDSL.SetQueryCacheSize(2)
+	w.EFDSL.Compile("HasTag(cow)")
+	w.EFDSL.Compile("HasTag(ox)")
+	w.EFDSL.Compile("HasTag(pig)")
+	if q, _ := w.EFDSL.Compile("HasTag(ox)"); q != q1 {
+		t.Fatal("recently used query shouldn't be evicted")
+	}
+	if w.EFDSL.queries.Len() != 2 {
+		t.Fatalf("cache should hold 2 queries, has %d", w.EFDSL.queries.Len())
+	}
+	if _, ok := w.EFDSL.queries.Get("HasTag(cow)"); ok {
+		t.Fatal("least recently used query should be evicted")
+	}
+	if _, err := w.EFDSL.Compile("HasTag(ox"); err == nil {
+		t.Fatal("should fail to compile a bad expression")
+	}
+	for _, expr := range []string{"Nope(self)", "HasTag(ox) || !Nope(x)", "HasTag(ox); Nearest(self)"} {
+		if _, err := w.EFDSL.Compile(expr); err == nil {
+			t.Fatalf("%s should fail to compile with an unregistered function", expr)
+		}
+	}
+}
+
+func TestEFDSLQueryBadArgs(t *testing.T) {
+	w := testingWorld()
+	self := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{0, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"tags": []string{"ox"},
+	})
+	resolver := &EntityResolver{e: self, w: w}
+	// far isn't a float64, so the predicate can't be made; the query
+	// matches nothing (even negated) rather than panicking
+	for _, expr := range []string{
+		"WithinDistance(self, far)",
+		"!WithinDistance(self, far)",
+		"HasTag(ox) && WithinDistance(self, far); Closest(self)",
+		"HasTag(ox) || WithinDistance(self, far)",
+	} {
+		q, err := w.EFDSL.Compile(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := q.FilterSort(resolver); len(result) != 0 {
+			t.Fatalf("%s should match nothing, got %v", expr, result)
+		}
+		if filter, _ := q.Evaluate(resolver); filter(self) {
+			t.Fatalf("%s's filter should match nothing", expr)
+		}
+	}
+}
+
+func TestEFDSLSortChainsAndPaging(t *testing.T) {
+	w := testingWorld()
+	spawn := func(x float64, gold int, tags ...string) *Entity {
+		return w.Spawn(map[string]any{
+			"components": map[ComponentID]any{
+				POSITION_: Vec2D{x, 0},
+				BOX_:      Vec2D{1, 1},
+				STATE_:    map[string]int{"gold": gold},
+			},
+			"tags": tags,
+		})
+	}
+	self := spawn(0, 0)
+	a := spawn(40, 10, "merchant")
+	b := spawn(10, 30, "merchant")
+	c := spawn(20, 10, "merchant")
+	d := spawn(30, 5, "merchant")
+	e := spawn(50, 20, "merchant")
+	// no state, so no gold to sort by
+	f := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{60, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"tags": []string{"merchant"},
+	})
+	self.Mind.Set("seed", 7)
+
+	for expr, expected := range map[string][]*Entity{
+		"HasTag(merchant); Closest(self)":                                     {b, c, d, a, e, f},
+		"HasTag(merchant); Farthest(self)":                                    {f, e, a, d, c, b},
+		"HasTag(merchant); Desc(x<gold>), Closest(self)":                      {b, e, c, a, d, f},
+		"HasTag(merchant); Asc(x<gold>), Farthest(self)":                      {d, a, c, e, b, f},
+		"HasTag(merchant); Desc(x<gold>), Closest(self); Limit(3)":            {b, e, c},
+		"HasTag(merchant); Desc(x<gold>), Closest(self); Offset(2); Limit(2)": {c, a},
+		"HasTag(merchant); Desc(x<gold>); Offset(5)":                          {f},
+		"HasTag(merchant); Closest(self); Offset(9)":                          {},
+		"HasTag(merchant) && Gt(x<gold>, 5); Asc(x[position].X)":              {b, c, a, e},
+	} {
+		result, err := w.EFDSLFilterSortEntity(self, expr)
+		if err != nil {
+			t.Fatalf("failed to evaluate %s: %s", expr, err)
+		}
+		if len(result) != len(expected) {
+			t.Fatalf("%s should give %v, got %v", expr, expected, result)
+		}
+		for i := range expected {
+			if result[i] != expected[i] {
+				t.Fatalf("%s should give %v, got %v", expr, expected, result)
+			}
+		}
+	}
+
+	// the same seed gives the same order, which the page is a window of
+	all, _ := w.EFDSLFilterSortEntity(self, "HasTag(merchant); Random(mind.seed)")
+	again, _ := w.EFDSLFilterSortEntity(self, "HasTag(merchant); Random(7)")
+	page, _ := w.EFDSLFilterSortEntity(self, "HasTag(merchant); Random(7); Offset(1); Limit(3)")
+	if len(all) != 6 || len(page) != 3 {
+		t.Fatalf("should find all merchants, got %v and page %v", all, page)
+	}
+	for i := range all {
+		if all[i] != again[i] || (i >= 1 && i < 4 && page[i-1] != all[i]) {
+			t.Fatalf("same seed should give the same order, got %v, %v and page %v", all, again, page)
+		}
+	}
+	// without a sort, the page is of the unsorted matches
+	if result, _ := w.EFDSLFilterSortEntity(self, "HasTag(merchant); Limit(2)"); len(result) != 2 {
+		t.Fatalf("should limit unsorted matches, got %v", result)
+	}
+
+	for expr, expected := range map[string]string{
+		"HasTag(ox); Limit(2); Closest(self)":       "1:23: sort should come before Limit and Offset",
+		"HasTag(ox); Closest(self), Limit(2)":       "1:28: Limit should be in a clause of its own, after a semicolon",
+		"HasTag(ox); Closest(self); Farthest(self)": "1:28: expected Limit or Offset, got: Function",
+	} {
+		_, err := (&EFDSLParser{}).Parse(expr)
+		if err == nil || err.Error() != expected {
+			t.Errorf("%s: expected error %q, got %v", expr, expected, err)
+		}
+	}
+	v := NewEFDSLValidator()
+	if errs := v.Validate("HasTag(ox); Desc(x<gold>), Closest(self); Limit(mind.n)"); len(errs) != 0 {
+		t.Fatalf("should be valid, got %v", errs)
+	}
+	if errs := v.Validate("HasTag(ox); Desc(x<gold>), Nearest(self); Limit(2.5)"); len(errs) != 2 {
+		t.Fatalf("should find 2 problems, got %v", errs)
+	}
+}
+
+func TestEFDSLParseErrorPositions(t *testing.T) {
+	cases := map[string]string{
+		"HasTag(ox) & Is(self)":           "1:12: expected &&",
+		"HasTag(ox) && Is(":               "1:18: expected close parenthesis, got: EOF",
+		"HasTag(ox); Closest(self) Is(x)": "1:27: unexpected Function after expression",
+		"HasTag(ox) && Is(self, @)":       "1:24: unexpected character '@'",
+	}
+	for expr, expected := range cases {
+		_, err := (&EFDSLParser{}).Parse(expr)
+		if err == nil || err.Error() != expected {
+			t.Errorf("%s: expected error %q, got %v", expr, expected, err)
+		}
+	}
+}
+
+func TestEFDSLValidator(t *testing.T) {
+	v := NewEFDSLValidator()
+	v.Components = map[string]bool{"NEEDS": true}
+	v.States = map[string]bool{"hunger": true}
+	cases := map[string][]string{
+		"Within(self, 50); HasTag(food); Closest(self)":                        nil,
+		"HasTag(item.agricultural) && !Ge(mind.friend<hunger>, 2 * x<hunger>)": nil,
+		"HasTag(ox) && Gt(x[needz].hunger, 2)": {
+			"1:18: unknown component needz in x[needz].hunger",
+		},
+		"Foo(self) || Is(friend)": {
+			"1:1: unknown predicate Foo",
+			"1:17: unknown identifier friend (expected self, x, mind.* or bb.*)",
+		},
+		"WithinDistance(self, far) && Eq(self<mood>, 2)": {
+			"1:22: WithinDistance: expected float64 for argument far",
+			"1:33: unknown state mood in self<mood>",
+		},
+		"HasTag(ox); Closest(self, 2)": {
+			"1:13: no signature of Closest matches (self, 2), expected one of: Closest(IdentResolve<int>)",
+		},
+		"Is(2 * self); Nearest(self)": {
+			"1:4: Is: arithmetic (2 * self) is only allowed in comparisons",
+			"1:15: unknown sort Nearest",
+		},
+		"HasTag(ox) &&": {
+			"1:14: expected function, got: EOF",
+		},
+	}
+	for expr, expected := range cases {
+		errs := v.Validate(expr)
+		got := make([]string, len(errs))
+		for i, err := range errs {
+			got[i] = err.Error()
+		}
+		if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("%s:\nexpected %q\ngot      %q", expr, expected, got)
+		}
+	}
+
+	// a world's validator knows its components and registered functions
+	w := testingWorld()
+	w.EFDSL.RegisterPredicates(EFDSLPredicateMap{
+		"Hungry": w.EFDSL.Predicate("IdentResolve<int>", func(int) func(*Entity) bool {
+			return func(*Entity) bool { return true }
+		}),
+	})
+	wv := w.EFDSL.Validator()
+	if errs := wv.Validate("Hungry(self) && Gt(x[position].X, 2)"); len(errs) != 0 {
+		t.Fatalf("should be valid, got %v", errs)
+	}
+	if errs := wv.Validate("Hungry(2.5) && Gt(x[nope].X, 2)"); len(errs) != 2 {
+		t.Fatalf("should find 2 problems, got %v", errs)
+	}
+}
+
+type testingEFDSLFaction struct {
+	name string
+}
+
+func TestEFDSLDispatch(t *testing.T) {
+	w := testingWorld()
+	RegisterEFDSLType[*testingEFDSLFaction]("*Faction")
+	red := &testingEFDSLFaction{"red"}
+	self := w.Spawn(map[string]any{"tags": []string{"red"}})
+	self.Mind.Set("faction", red)
+	w.Spawn(map[string]any{"tags": []string{"blue"}})
+	w.EFDSL.RegisterPredicates(EFDSLPredicateMap{
+		// unregistered signature, called by reflection
+		"OfFaction": w.EFDSL.Predicate(
+			"IdentResolve<*Faction>",
+			func(f *testingEFDSLFaction) func(*Entity) bool {
+				return func(x *Entity) bool {
+					return w.EntityHasTag(x, f.name)
+				}
+			},
+		),
+		// more args than the generated switches allowed
+		"InBox": w.EFDSL.Predicate(
+			"int, int, int, int",
+			func(x0, y0, x1, y1 int) func(*Entity) bool {
+				return func(x *Entity) bool {
+					return x0 < x1 && y0 < y1
+				}
+			},
+		),
+	})
+	result, err := w.EFDSLFilterEntity(self, "OfFaction(mind.faction) && InBox(0, 0, 2, 2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || result[0] != self {
+		t.Fatalf("should find only self, got %v", result)
+	}
+	// registering the signature makes the call direct
+	RegisterEFDSLSignature[*testingEFDSLFaction]()
+	f := func(*testingEFDSLFaction) func(*Entity) bool { return nil }
+	if _, ok := efdslPredicateDispatcher.adapters[reflect.TypeOf(f)]; !ok {
+		t.Fatal("signature should be registered")
+	}
+
+	// a function not matching its signature panics when the factory is made
+	defer func() {
+		if recover() == nil {
+			t.Fatal("mismatched signature should panic")
+		}
+	}()
+	w.EFDSL.Predicate("IdentResolve<int>, float64", func(id int, d int) func(*Entity) bool {
+		return nil
+	})
+}
@@ -0,0 +1,375 @@
+package sameriver
+
+/*
+EFDSLValidator checks expressions before they're run, without a world:
+the syntax, that the functions are registered, that their arguments match
+one of their signatures (as far as can be told without resolving
+identifiers), that identifiers are rooted in something a resolver knows
+(self, x, mind.*, bb.*), and, if given the names, that accessors name known
+components and state keys, eg.
+
+	v := NewEFDSLValidator()
+	v.Components = map[string]bool{"POSITION": true, "NEEDS": true}
+	for _, err := range v.Validate("HasTag(ox) && Gt(x[needz].hunger, 2)") {
+		fmt.Println(err) // 1:18: unknown component needz in x[needz].hunger
+	}
+
+The signatures are those the functions report when probed (see
+efdslSignatures()); those built with EFDSLEvaluator.Predicate() and Sort()
+always do. A function which doesn't is only checked to be registered.
+*/
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/scanner"
+)
+
+// EFDSLError is a problem with an expression, at a 1-based line and
+// column of it (Offset is 0-based, in bytes)
+type EFDSLError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Offset  int    `json:"offset"`
+	Message string `json:"message"`
+}
+
+func (err *EFDSLError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+func efdslErrorf(pos scanner.Position, format string, args ...any) *EFDSLError {
+	return &EFDSLError{
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Offset:  pos.Offset,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// the pseudo-type of the operands of comparisons (see efdsl_operands.go),
+// which may be any literal, identifier or arithmetic on them
+const EFDSL_OPERAND = "Operand"
+
+type EFDSLValidator struct {
+	// the signatures of the functions by name; nil if a function doesn't
+	// report its signatures
+	Predicates map[string][]string
+	Sorts      map[string][]string
+	Spatials   map[string][]string
+	// the names of the components and state keys accessors may use, or nil
+	// to not check them
+	Components map[string]bool
+	States     map[string]bool
+}
+
+// NewEFDSLValidator validates against the base predicates, sorts and
+// spatial clauses, not checking component or state names
+func NewEFDSLValidator() *EFDSLValidator {
+	return NewEFDSLEvaluator(nil).Validator()
+}
+
+// Validator validates against the functions registered with the evaluator
+// and, if it has a world, the world's components
+func (e *EFDSLEvaluator) Validator() *EFDSLValidator {
+	v := &EFDSLValidator{
+		Predicates: make(map[string][]string),
+		Sorts:      make(map[string][]string),
+		Spatials:   make(map[string][]string),
+	}
+	for name, f := range e.predicates {
+		v.Predicates[name] = efdslProbe(func(probe *efdslSignatureProbe) { f(nil, probe) })
+	}
+	for name, f := range e.sorts {
+		v.Sorts[name] = efdslProbe(func(probe *efdslSignatureProbe) { f(nil, probe) })
+	}
+	for name, f := range e.spatials {
+		v.Spatials[name] = efdslProbe(func(probe *efdslSignatureProbe) { f(nil, probe) })
+	}
+	if e.w != nil {
+		v.Components = make(map[string]bool)
+		for name := range e.w.Em.ComponentsTable.StringsRev {
+			v.Components[name] = true
+		}
+	}
+	return v
+}
+
+// passed as the resolver to a function to ask for its signatures
+type efdslSignatureProbe struct {
+	signatures []string
+}
+
+func (probe *efdslSignatureProbe) Resolve(identifier string) any {
+	return nil
+}
+
+// efdslSignatures is called first thing by a predicate, sort or spatial
+// clause with its signatures, returning true (upon which it should return
+// nil) if it's being probed for them by a validator
+func efdslSignatures(resolver IdentifierResolver, signatures ...string) bool {
+	probe, ok := resolver.(*efdslSignatureProbe)
+	if ok {
+		probe.signatures = signatures
+	}
+	return ok
+}
+
+// functions which don't expect to be probed may panic on no args
+func efdslProbe(call func(probe *efdslSignatureProbe)) (signatures []string) {
+	defer func() {
+		if recover() != nil {
+			signatures = nil
+		}
+	}()
+	probe := &efdslSignatureProbe{}
+	call(probe)
+	return probe.signatures
+}
+
+// Validate returns the problems with the expression, in order
+func (v *EFDSLValidator) Validate(expr string) []*EFDSLError {
+	ast, err := (&EFDSLParser{}).Parse(expr)
+	if err != nil {
+		if perr, ok := err.(*EFDSLError); ok {
+			return []*EFDSLError{perr}
+		}
+		return []*EFDSLError{{Line: 1, Column: 1, Message: err.Error()}}
+	}
+	errs := make([]*EFDSLError, 0)
+	add := func(pos scanner.Position, format string, args ...any) {
+		errs = append(errs, efdslErrorf(pos, format, args...))
+	}
+	for _, clause := range ast.Children {
+		switch clause.Type {
+		case NodeSpatialExpr:
+			v.validateFunction(clause.Children[0], "spatial clause", v.Spatials, add)
+		case NodeSortExpr:
+			for _, ch := range clause.Children {
+				v.validateFunction(ch, "sort", v.Sorts, add)
+			}
+		case NodePageExpr:
+			for _, ch := range clause.Children {
+				v.validateFunction(ch, "page clause", efdslPageSignatures, add)
+			}
+		case NodePredicateExpr:
+			v.validatePredicate(clause, add)
+		}
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Offset < errs[j].Offset
+	})
+	return errs
+}
+
+// Limit() and Offset() aren't registered functions
+var efdslPageSignatures = map[string][]string{
+	"Limit":  {"IdentResolve<int>"},
+	"Offset": {"IdentResolve<int>"},
+}
+
+type efdslAddError func(pos scanner.Position, format string, args ...any)
+
+func (v *EFDSLValidator) validatePredicate(n *Node, add efdslAddError) {
+	for _, ch := range n.Children {
+		switch ch.Type {
+		case NodeFunction:
+			v.validateFunction(ch, "predicate", v.Predicates, add)
+		case NodeAnd, NodeOr:
+			v.validatePredicate(ch.Children[0], add)
+		}
+	}
+}
+
+func (v *EFDSLValidator) validateFunction(n *Node, kind string, functions map[string][]string, add efdslAddError) {
+	signatures, ok := functions[n.Value]
+	if !ok {
+		add(n.Pos, "unknown %s %s", kind, n.Value)
+		return
+	}
+	if n.Subject != "" && n.Subject != "x" {
+		v.validateIdentifier(n.Subject, n.Pos, add)
+	}
+	if signatures == nil {
+		v.validateAccesses(n, add)
+		return
+	}
+	// the arg problems of each signature with the right number of args
+	candidates := make([][]*EFDSLError, 0)
+	for _, signature := range signatures {
+		types, err := ExtractTypesFromSignature(signature)
+		if err != nil || len(types) != len(n.Children) {
+			continue
+		}
+		problems := make([]*EFDSLError, 0)
+		for i, arg := range n.Children {
+			if problem := efdslCheckArgType(arg, types[i]); problem != "" {
+				problems = append(problems, efdslErrorf(arg.Pos, "%s: %s", n.Value, problem))
+			}
+		}
+		if len(problems) == 0 {
+			// identifiers are resolved where the signature says so
+			for i, arg := range n.Children {
+				if types[i] == EFDSL_OPERAND || strings.HasPrefix(types[i], "IdentResolve<") {
+					v.validateIdentifiers(arg, add)
+				}
+			}
+			return
+		}
+		candidates = append(candidates, problems)
+	}
+	v.validateAccesses(n, add)
+	if len(candidates) == 1 {
+		for _, err := range candidates[0] {
+			add(scanner.Position{Line: err.Line, Column: err.Column, Offset: err.Offset}, "%s", err.Message)
+		}
+		return
+	}
+	args := make([]string, len(n.Children))
+	for i, arg := range n.Children {
+		args[i] = arg.Source()
+	}
+	add(n.Pos, "no signature of %s matches (%s), expected one of: %s",
+		n.Value, strings.Join(args, ", "), strings.Join(efdslSignatureStrings(n.Value, signatures), ", "))
+}
+
+func efdslSignatureStrings(name string, signatures []string) []string {
+	result := make([]string, len(signatures))
+	for i, signature := range signatures {
+		result[i] = fmt.Sprintf("%s(%s)", name, signature)
+	}
+	return result
+}
+
+// efdslCheckArgType mirrors DSLAssertArgTypes as far as it can without a
+// resolver, giving the problem with the arg, or ""
+func efdslCheckArgType(arg *Node, expected string) string {
+	if expected == EFDSL_OPERAND {
+		return ""
+	}
+	if arg.Type == NodeArith {
+		return fmt.Sprintf("arithmetic (%s) is only allowed in comparisons", arg.Source())
+	}
+	source := arg.Source()
+	parts := strings.Split(expected, "<")
+	if _, ok := typeResolveFuncs[parts[0]]; ok && len(parts) > 1 {
+		typeName := strings.TrimSuffix(parts[1], ">")
+		if source == "self" || source == "x" {
+			return ""
+		}
+		if _, ok := IdentResolveTypeAssertMap[typeName]; !ok {
+			return fmt.Sprintf("unsupported type in signature: %s", expected)
+		}
+		if arg.Type == NodeLiteral {
+			if got := efdslLiteralType(arg.Literal); got != typeName {
+				return fmt.Sprintf("expected %s for argument %s, got %s", expected, source, got)
+			}
+		}
+		return ""
+	}
+	var err error
+	switch expected {
+	case "bool":
+		_, err = strconv.ParseBool(source)
+	case "int":
+		_, err = strconv.Atoi(source)
+	case "float64":
+		_, err = strconv.ParseFloat(source, 64)
+	case "string", "[]string":
+	default:
+		return fmt.Sprintf("unsupported type in signature: %s", expected)
+	}
+	if err != nil {
+		return fmt.Sprintf("expected %s for argument %s", expected, source)
+	}
+	return ""
+}
+
+func efdslLiteralType(literal any) string {
+	switch literal.(type) {
+	case int:
+		return "int"
+	case float64:
+		return "float64"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	}
+	return fmt.Sprintf("%T", literal)
+}
+
+func (v *EFDSLValidator) validateIdentifiers(n *Node, add efdslAddError) {
+	switch n.Type {
+	case NodeIdentifier:
+		v.validateIdentifier(n.Value, n.Pos, add)
+	case NodeArith:
+		for _, ch := range n.Children {
+			v.validateIdentifiers(ch, add)
+		}
+	}
+}
+
+// where it's not known which args are resolved, those with an accessor
+// surely are (others may just be strings, like the ox of HasTag(ox))
+func (v *EFDSLValidator) validateAccesses(n *Node, add efdslAddError) {
+	for _, arg := range n.Children {
+		if arg.Type == NodeIdentifier && strings.ContainsAny(arg.Value, "[<") {
+			v.validateIdentifier(arg.Value, arg.Pos, add)
+		}
+	}
+}
+
+// validateIdentifier checks that the identifier is rooted in something a
+// resolver knows, and its accessor, if any (see efdslAccess())
+func (v *EFDSLValidator) validateIdentifier(identifier string, pos scanner.Position, add efdslAddError) {
+	object := efdslObject(identifier)
+	parts := strings.Split(object, ".")
+	switch parts[0] {
+	case "self", "x":
+		if len(parts) > 1 {
+			add(pos, "%s has no fields, did you mean %s[%s]?", parts[0], parts[0], strings.Join(parts[1:], "."))
+			return
+		}
+	case "mind":
+		if len(parts) < 2 || parts[1] == "" {
+			add(pos, "expected mind.<key> in %s", identifier)
+			return
+		}
+	case "bb":
+		if len(parts) < 3 || parts[1] == "" || parts[2] == "" {
+			add(pos, "expected bb.<blackboard>.<key> in %s", identifier)
+			return
+		}
+	default:
+		add(pos, "unknown identifier %s (expected self, x, mind.* or bb.*)", identifier)
+		return
+	}
+	rest := identifier[len(object):]
+	if rest == "" {
+		return
+	}
+	closing := map[byte]string{'[': "]", '<': ">"}[rest[0]]
+	end := strings.Index(rest, closing)
+	if end == -1 {
+		add(pos, "unterminated accessor in %s", identifier)
+		return
+	}
+	accessor, fields := rest[1:end], rest[end+1:]
+	if fields != "" && fields[0] != '.' {
+		add(pos, "expected .field after accessor in %s", identifier)
+		return
+	}
+	switch rest[0] {
+	case '[':
+		if v.Components != nil && !v.Components[accessor] && !v.Components[strings.ToUpper(accessor)] {
+			add(pos, "unknown component %s in %s", accessor, identifier)
+		}
+	case '<':
+		if v.States != nil && !v.States[accessor] {
+			add(pos, "unknown state %s in %s", accessor, identifier)
+		}
+	}
+}
@@ -7,6 +7,8 @@
 package sameriver
 
 import (
+	"sync"
+
 	"go.uber.org/atomic"
 )
 
@@ -17,11 +19,17 @@ type Event struct {
 
 type EventBus struct {
 	name string
-	// channels is a map of lists of EventChannels
+	// channels holds the EventChannels by the patterns of their filters
 	// Each EventFilter's Predicate will be tested against the events
-	// that are published for the matching type (and thus the predicates
+	// that are published for a matching type (and thus the predicates
 	// can safely assert the type of the Data member of the event)
-	channels map[string][]*EventChannel
+	channels *eventTrie[*EventChannel]
+	// the EventHandlers, by pattern (see event_handler.go)
+	handlers *eventHandlers
+	// taps see every event they match as it's published (see event_tap.go)
+	taps []*EventTap
+	// subscribing and unsubscribing may happen while events are published
+	mutex sync.RWMutex
 	// number of goroutines spawned to publish events to subscriber channels
 	// that are full
 	nHanging atomic.Int32
@@ -29,7 +37,8 @@ type EventBus struct {
 
 func NewEventBus(name string) *EventBus {
 	b := &EventBus{name: name}
-	b.channels = make(map[string][]*EventChannel)
+	b.channels = newEventTrie[*EventChannel]()
+	b.handlers = newEventHandlers()
 	return b
 }
 
@@ -39,31 +48,53 @@ func (b *EventBus) Publish(t string, data any) {
 
 // Subscribe to listen for game events defined by a Filter
 func (b *EventBus) Subscribe(q *EventFilter) *EventChannel {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
 
 	// Create a channel to return to the user
 	c := NewEventChannel(q)
-	// Add the channel to the subscriber list for its type
-	b.channels[q.eventType] = append(
-		b.channels[q.eventType], c)
+	// Add the channel to the subscribers for each of its patterns
+	for _, pattern := range q.patterns {
+		b.channels.insert(pattern, c)
+	}
 	// return the channel to the caller
 	return c
 }
 
 // Remove a subscriber
 func (b *EventBus) Unsubscribe(c *EventChannel) {
-	eventType := c.filter.eventType
-	channels, ok := b.channels[eventType]
-	if ok {
-		channels = removeEventChannelFromSlice(channels, c)
-		b.channels[eventType] = channels
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, pattern := range c.filter.patterns {
+		b.channels.remove(pattern, c)
 	}
 }
 
+// the active channels whose filters match the type of e, once each
+func (b *EventBus) subscribers(e Event) []*EventChannel {
+	b.mutex.RLock()
+	matched := b.channels.match(e.Type, make([]*EventChannel, 0, 4))
+	b.mutex.RUnlock()
+	result := uniqueEventSubscribers(matched)
+	active := result[:0]
+	for _, c := range result {
+		if c.IsActive() {
+			active = append(active, c)
+		}
+	}
+	return active
+}
+
 // notify subscribers to a certain event
 func (b *EventBus) notifySubscribers(e Event) {
-	// TODO: create a special system which listens on *all* events,
-	// printing them if it's turned on
 	logEvents("⚹: %s", e.Type)
+	b.notifyTaps(e)
+	// the handlers called as it's published come first, and may consume it
+	if callEventHandlers(b.matchHandlers(e, false), e) {
+		return
+	}
+	b.queueDeferred(e)
 
 	var notifyFull = func(c *EventChannel) {
 		logWarning("event subscriber channel for events of type %s is full; possibly sending too many events; consider throttling or increase capacity\n", e.Type)
@@ -73,35 +104,35 @@ func (b *EventBus) notifySubscribers(e Event) {
 		logWarning("/!\\ /!\\ /!\\ number of goroutines waiting for an event channel (of event type %s) to go below max capacity is now greater than capacity (%d); you're sending too many events", e.Type, EVENT_SUBSCRIBER_CHANNEL_CAPACITY)
 	}
 
-	logEvents("len(b.channels[e.Type])=%d", len(b.channels[e.Type]))
-	for _, c := range b.channels[e.Type] {
+	subscribers := b.subscribers(e)
+	logEvents("len(subscribers)=%d", len(subscribers))
+	for _, c := range subscribers {
 		logEvents("| Channel: %p", c)
-		if !c.IsActive() {
+		// the type matches, so only the predicate is left to test
+		passes := c.filter.predicate == nil || c.filter.predicate(e)
+		logEvents("--> channel.filter.predicate(e) = %t", passes)
+		if !passes {
 			continue
 		}
-		logEvents("--> channel.filter.Test(e) = %t", c.filter.Test(e))
-		if c.filter.Test(e) {
-			if len(c.C) >= EVENT_SUBSCRIBER_CHANNEL_CAPACITY {
-				notifyFull(c)
-				// spawn a goroutine to do the channel send since we don't
-				// want a hang here to affect other subscribers
-				// (note: if you severely overrun, even these goroutines
-				// will add up and cause problems)
-				// TODO: count how many of these are waiting and warn if too high
-				go func() {
-					b.nHanging.Add(1)
-					if b.nHanging.Load() > EVENT_SUBSCRIBER_CHANNEL_CAPACITY {
-						notifyExtraFull()
-					}
-					logEvents("---- event channel put <- %s.%v", e.Type, e.Data)
-					c.C <- e
-					b.nHanging.Add(-1)
-				}()
-			} else {
-				logEvents("---- event channel put <- %s.%v", e.Type, e.Data)
-				c.C <- e
-				logEvents("---- len(<%p>.C) = %d", c, len(c.C))
-			}
+		logEvents("---- event channel put <- %s.%v", e.Type, e.Data)
+		if c.send(e) {
+			logEvents("---- len(<%p>.C) = %d", c, len(c.C))
+			continue
 		}
+		// full, with the policy EVENT_OVERFLOW_BLOCK
+		notifyFull(c)
+		// spawn a goroutine to do the channel send since we don't
+		// want a hang here to affect other subscribers
+		// (note: if you severely overrun, even these goroutines
+		// will add up and cause problems; consider another
+		// EventOverflowPolicy or an EventHandler)
+		go func() {
+			b.nHanging.Add(1)
+			if b.nHanging.Load() > EVENT_SUBSCRIBER_CHANNEL_CAPACITY {
+				notifyExtraFull()
+			}
+			c.C <- e
+			b.nHanging.Add(-1)
+		}()
 	}
 }
@@ -123,3 +123,34 @@ func TestEventBusUnsubscribe(t *testing.T) {
 		break
 	}
 }
+
+func TestEventBusPatternSubscriptions(t *testing.T) {
+	ev := NewEventBus("testing")
+	combat := ev.Subscribe(PatternEventFilter("combat.*"))
+	all := ev.Subscribe(AllEventsFilter())
+	// overlapping patterns, still sent each event once
+	overlap := ev.Subscribe(PatternEventFilter("combat.hit", "combat.**", "*.hit"))
+	exact := ev.Subscribe(SimpleEventFilter("collision"))
+
+	ev.Publish("combat.hit", nil)
+	ev.Publish("combat.hit.crit", nil)
+	ev.Publish("collision", nil)
+	ev.Publish("trade.offer", nil)
+	for c, expected := range map[*EventChannel]int{combat: 1, all: 4, overlap: 2, exact: 1} {
+		if len(c.C) != expected {
+			t.Fatalf("%v should have been sent %d events, got %d", c.filter.Patterns(), expected, len(c.C))
+		}
+		c.DrainChannel()
+	}
+
+	ev.Unsubscribe(overlap)
+	ev.Unsubscribe(combat)
+	ev.Publish("combat.hit", nil)
+	if len(overlap.C) != 0 || len(combat.C) != 0 || len(all.C) != 1 {
+		t.Fatal("unsubscribed channels shouldn't receive events")
+	}
+	// the trie is pruned of the patterns no longer subscribed
+	if _, ok := ev.channels.root.children["combat"]; ok {
+		t.Fatal("combat patterns should have been pruned")
+	}
+}
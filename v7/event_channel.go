@@ -1,20 +1,47 @@
 package sameriver
 
 import (
+	"sync"
+
 	"go.uber.org/atomic"
 )
 
+// EventOverflowPolicy is what's done with an event for an EventChannel
+// whose buffer is full
+type EventOverflowPolicy int
+
+const (
+	// spawn a goroutine to send the event once there's room (the default)
+	EVENT_OVERFLOW_BLOCK EventOverflowPolicy = iota
+	// drop the oldest event waiting in the channel to make room
+	EVENT_OVERFLOW_DROP_OLDEST
+	// drop the event being sent
+	EVENT_OVERFLOW_DROP_NEWEST
+	// merge the event into the latest waiting event of the same type (see
+	// SetCoalesce()), or if there's none, drop the oldest
+	EVENT_OVERFLOW_COALESCE
+)
+
 type EventChannel struct {
 	active *atomic.Uint32
 	C      chan Event
 	filter *EventFilter
+	// set before events are published to the channel
+	overflow EventOverflowPolicy
+	coalesce func(pending Event, latest Event) Event
+	// the events dropped by the overflow policy
+	dropped *atomic.Int32
+	// held while coalescing, which takes the waiting events out and puts
+	// them back
+	mutex sync.Mutex
 }
 
 func NewEventChannel(q *EventFilter) *EventChannel {
 	return &EventChannel{
-		active: atomic.NewUint32(1),
-		C:      make(chan (Event), EVENT_SUBSCRIBER_CHANNEL_CAPACITY),
-		filter: q}
+		active:  atomic.NewUint32(1),
+		C:       make(chan (Event), EVENT_SUBSCRIBER_CHANNEL_CAPACITY),
+		filter:  q,
+		dropped: atomic.NewInt32(0)}
 }
 
 func (c *EventChannel) Activate() {
@@ -35,3 +62,101 @@ func (c *EventChannel) DrainChannel() {
 		<-c.C
 	}
 }
+
+// SetOverflowPolicy sets what's done with events sent while C is full
+func (c *EventChannel) SetOverflowPolicy(policy EventOverflowPolicy) {
+	c.overflow = policy
+}
+
+// SetCoalesce sets the overflow policy to EVENT_OVERFLOW_COALESCE, merging
+// an event sent while C is full into the latest waiting event of the same
+// type with merge (nil to just replace it with the newer event)
+func (c *EventChannel) SetCoalesce(merge func(pending Event, latest Event) Event) {
+	c.overflow = EVENT_OVERFLOW_COALESCE
+	c.coalesce = merge
+}
+
+// Dropped returns how many events the overflow policy has dropped
+func (c *EventChannel) Dropped() int {
+	return int(c.dropped.Load())
+}
+
+// send sends e without blocking, applying the overflow policy if C is
+// full, returning false if it's full and the policy is to block
+func (c *EventChannel) send(e Event) bool {
+	switch c.overflow {
+	case EVENT_OVERFLOW_DROP_NEWEST:
+		select {
+		case c.C <- e:
+		default:
+			c.dropped.Inc()
+		}
+	case EVENT_OVERFLOW_DROP_OLDEST:
+		for {
+			select {
+			case c.C <- e:
+				return true
+			default:
+				select {
+				case <-c.C:
+					c.dropped.Inc()
+				default:
+				}
+			}
+		}
+	case EVENT_OVERFLOW_COALESCE:
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		select {
+		case c.C <- e:
+		default:
+			c.coalesceFull(e)
+		}
+	default:
+		select {
+		case c.C <- e:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (c *EventChannel) coalesceFull(e Event) {
+	n := len(c.C)
+	pending := make([]Event, 0, n+1)
+	for i := 0; i < n; i++ {
+		select {
+		case p := <-c.C:
+			pending = append(pending, p)
+		default:
+		}
+	}
+	merged := false
+	for i := len(pending) - 1; i >= 0; i-- {
+		if pending[i].Type == e.Type {
+			if c.coalesce != nil {
+				pending[i] = c.coalesce(pending[i], e)
+			} else {
+				pending[i] = e
+			}
+			merged = true
+			break
+		}
+	}
+	if !merged {
+		pending = append(pending, e)
+	}
+	// oldest first, dropping those there's no room for
+	if over := len(pending) - cap(c.C); over > 0 {
+		c.dropped.Add(int32(over))
+		pending = pending[over:]
+	}
+	for _, p := range pending {
+		select {
+		case c.C <- p:
+		default:
+			c.dropped.Inc()
+		}
+	}
+}
@@ -5,7 +5,7 @@ import (
 )
 
 func TestNewEventChannel(t *testing.T) {
-	q := &EventFilter{eventType: "spawn-request"}
+	q := &EventFilter{patterns: []string{"spawn-request"}}
 	ec := NewEventChannel(q)
 	if !(ec.IsActive() &&
 		ec.C != nil &&
@@ -36,3 +36,63 @@ func TestEventChannelDrain(t *testing.T) {
 		t.Fatal("drain channel did not remove all events")
 	}
 }
+
+func TestEventChannelOverflowPolicies(t *testing.T) {
+	ev := NewEventBus("testing")
+	fill := func() {
+		for i := 0; i < EVENT_SUBSCRIBER_CHANNEL_CAPACITY; i++ {
+			ev.Publish("tick", i)
+		}
+	}
+
+	oldest := ev.Subscribe(SimpleEventFilter("tick"))
+	oldest.SetOverflowPolicy(EVENT_OVERFLOW_DROP_OLDEST)
+	fill()
+	ev.Publish("tick", -1)
+	if first := <-oldest.C; first.Data != 1 || oldest.Dropped() != 1 {
+		t.Fatalf("should have dropped the oldest event, got %v first", first)
+	}
+	ev.Unsubscribe(oldest)
+
+	newest := ev.Subscribe(SimpleEventFilter("tick"))
+	newest.SetOverflowPolicy(EVENT_OVERFLOW_DROP_NEWEST)
+	fill()
+	ev.Publish("tick", -1)
+	if first := <-newest.C; first.Data != 0 || newest.Dropped() != 1 || len(newest.C) != EVENT_SUBSCRIBER_CHANNEL_CAPACITY-1 {
+		t.Fatalf("should have dropped the newest event, got %v first", first)
+	}
+	newest.DrainChannel()
+	ev.Unsubscribe(newest)
+
+	coalesced := ev.Subscribe(PatternEventFilter("tick", "move"))
+	coalesced.SetCoalesce(func(pending Event, latest Event) Event {
+		return Event{pending.Type, pending.Data.(int) + latest.Data.(int)}
+	})
+	ev.Publish("move", 10)
+	for i := 0; i < EVENT_SUBSCRIBER_CHANNEL_CAPACITY-1; i++ {
+		ev.Publish("tick", 1)
+	}
+	ev.Publish("move", 5)
+	ev.Publish("tick", 100)
+	if len(coalesced.C) != EVENT_SUBSCRIBER_CHANNEL_CAPACITY || coalesced.Dropped() != 0 {
+		t.Fatal("coalescing shouldn't drop events")
+	}
+	if first := <-coalesced.C; first.Type != "move" || first.Data != 15 {
+		t.Fatalf("move events should have merged in place, got %v", first)
+	}
+	events := make([]Event, 0)
+	for len(coalesced.C) > 0 {
+		events = append(events, <-coalesced.C)
+	}
+	if last := events[len(events)-1]; last.Data != 101 {
+		t.Fatalf("latest tick should have merged into the last waiting tick, got %v", last)
+	}
+	// with no waiting event of the same type, the oldest is dropped
+	for i := 0; i < EVENT_SUBSCRIBER_CHANNEL_CAPACITY; i++ {
+		ev.Publish("tick", i)
+	}
+	ev.Publish("move", 1)
+	if first := <-coalesced.C; first.Data != 1 || coalesced.Dropped() != 1 {
+		t.Fatalf("should have dropped the oldest event, got %v first", first)
+	}
+}
@@ -1,21 +1,50 @@
 package sameriver
 
+import (
+	"fmt"
+	"strings"
+)
+
 type EventPredicate func(e Event) bool
 
+// EventFilter matches events whose type matches one of its patterns, and
+// which pass its predicate, if it has one.
+//
+// A pattern is an event type, or one with wildcards for its dotted parts:
+// "*" matches any one part and "**" any number of parts (including none),
+// eg. "combat.*" matches "combat.hit" but not "combat" or
+// "combat.hit.crit", "combat.**" matches all three, and "**" matches
+// every event.
 type EventFilter struct {
-	eventType string
+	patterns  []string
 	predicate func(e Event) bool
 }
 
-// for simple event queries, predicate is never tested
+// for simple event queries, predicate is never tested (and it's only
+// tested on events whose type matches, so it can assert the type of Data)
 func (q *EventFilter) Test(e Event) bool {
-	return q.eventType == e.Type && (q.predicate == nil || q.predicate(e))
+	return q.matchesType(e.Type) && (q.predicate == nil || q.predicate(e))
+}
+
+func (q *EventFilter) matchesType(eventType string) bool {
+	parts := strings.Split(eventType, ".")
+	for _, pattern := range q.patterns {
+		if eventPatternMatch(strings.Split(pattern, "."), parts) {
+			return true
+		}
+	}
+	return false
+}
+
+// Patterns returns the patterns of the filter
+func (q *EventFilter) Patterns() []string {
+	return q.patterns
 }
 
 // Construct a new EventFilter which only asks about
 // the Type of the event
 func SimpleEventFilter(Type string) *EventFilter {
-	return &EventFilter{Type, nil}
+	return PatternEventFilter(Type)
 }
 
 // Construct a new EventFilter which asks about Type and
@@ -23,5 +52,63 @@ func SimpleEventFilter(Type string) *EventFilter {
 func PredicateEventFilter(
 	Type string, predicate func(e Event) bool) *EventFilter {
 
-	return &EventFilter{Type, predicate}
+	return PredicatePatternEventFilter([]string{Type}, predicate)
+}
+
+// Construct a new EventFilter matching events of any of the given types or
+// patterns, eg. PatternEventFilter("combat.*", "spawn-request")
+func PatternEventFilter(patterns ...string) *EventFilter {
+	return PredicatePatternEventFilter(patterns, nil)
+}
+
+// Construct a new EventFilter matching events of any of the given types or
+// patterns which pass a user-given predicate
+func PredicatePatternEventFilter(
+	patterns []string, predicate func(e Event) bool) *EventFilter {
+
+	if len(patterns) == 0 {
+		panic("EventFilter needs at least one event type or pattern")
+	}
+	for _, pattern := range patterns {
+		if err := validateEventPattern(pattern); err != nil {
+			panic(err)
+		}
+	}
+	return &EventFilter{patterns, predicate}
+}
+
+// Construct a new EventFilter matching every event
+func AllEventsFilter() *EventFilter {
+	return PatternEventFilter("**")
+}
+
+func validateEventPattern(pattern string) error {
+	for _, part := range strings.Split(pattern, ".") {
+		switch {
+		case part == "":
+			return fmt.Errorf("event pattern %q has an empty part", pattern)
+		case part != "*" && part != "**" && strings.Contains(part, "*"):
+			return fmt.Errorf("event pattern %q: wildcards should be a whole part (* or **), got %s", pattern, part)
+		}
+	}
+	return nil
+}
+
+func eventPatternMatch(pattern []string, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	switch pattern[0] {
+	case "**":
+		for i := 0; i <= len(parts); i++ {
+			if eventPatternMatch(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(parts) > 0 && eventPatternMatch(pattern[1:], parts[1:])
+	default:
+		return len(parts) > 0 && pattern[0] == parts[0] && eventPatternMatch(pattern[1:], parts[1:])
+	}
 }
@@ -44,3 +44,62 @@ func TestEventFilterCollision(t *testing.T) {
 		t.Fatal("filter did not match, should have")
 	}
 }
+
+func TestEventFilterPatterns(t *testing.T) {
+	cases := map[string]map[string]bool{
+		"combat.*": {
+			"combat": false, "combat.hit": true, "combat.hit.crit": false, "trade.hit": false,
+		},
+		"combat.**": {
+			"combat": true, "combat.hit": true, "combat.hit.crit": true, "trade.hit": false,
+		},
+		"*.hit": {
+			"combat.hit": true, "trade.hit": true, "hit": false, "combat.hit.crit": false,
+		},
+		"combat.**.crit": {
+			"combat.crit": true, "combat.hit.crit": true, "combat.a.b.crit": true, "combat.hit": false,
+		},
+		"**": {
+			"collision": true, "combat.hit.crit": true,
+		},
+		"collision": {
+			"collision": true, "collision.start": false,
+		},
+	}
+	for pattern, types := range cases {
+		f := PatternEventFilter(pattern)
+		for eventType, expected := range types {
+			if f.Test(Event{Type: eventType}) != expected {
+				t.Errorf("%s should match %s: %t", pattern, eventType, expected)
+			}
+		}
+	}
+
+	f := PatternEventFilter("collision", "combat.*")
+	if !f.Test(Event{Type: "collision"}) || !f.Test(Event{Type: "combat.hit"}) || f.Test(Event{Type: "spawn-request"}) {
+		t.Fatal("should match any of its patterns")
+	}
+	for _, bad := range []string{"combat.", "com*", "a..b"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%q should be rejected", bad)
+				}
+			}()
+			PatternEventFilter(bad)
+		}()
+	}
+}
+
+func TestEventFilterPatternPredicate(t *testing.T) {
+	// the predicate is only tested on events whose type matches
+	f := PredicatePatternEventFilter([]string{"combat.*"}, func(e Event) bool {
+		return e.Data.(int) > 2
+	})
+	if f.Test(Event{Type: "trade.hit", Data: "not an int"}) {
+		t.Fatal("shouldn't match the wrong type")
+	}
+	if !f.Test(Event{Type: "combat.hit", Data: 3}) || f.Test(Event{Type: "combat.hit", Data: 1}) {
+		t.Fatal("should test the predicate")
+	}
+}
@@ -0,0 +1,166 @@
+package sameriver
+
+import (
+	"sort"
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+/*
+EventHandler is the other way to hear events, besides an EventChannel: a
+function called with each event matching its filter, in order of priority
+(highest first, those of equal priority in the order they were added),
+which can consume the event by returning true, eg.
+
+	w.Events.Handle(SimpleEventFilter("damage"), 10,
+		func(e Event) bool {
+			// shields absorb the damage, so nothing else hears of it
+			return absorb(e.Data.(DamageData))
+		})
+
+Handlers added with Handle() are called as the event is published, on the
+goroutine publishing it (so a handler for events published by parallel
+systems, like collisions, should expect to be called concurrently), and
+consuming the event stops it going to the lower-priority handlers, the
+EventChannels and the deferred handlers.
+
+Handlers added with HandleDeferred() are called when the bus is dispatched
+(see Dispatch(); the world's bus and blackboards' buses are dispatched in
+World.Update(), before the systems update), with the events published since,
+in order; consuming an event stops it going to the lower-priority deferred
+handlers.
+
+Either way no goroutines are spawned and there's no buffer to fill up.
+*/
+type EventHandler struct {
+	filter   *EventFilter
+	priority int
+	deferred bool
+	f        func(e Event) (consumed bool)
+	// the order handlers were added in, for equal priorities
+	seq uint64
+}
+
+// the handlers of a bus, by pattern
+type eventHandlers struct {
+	immediate *eventTrie[*EventHandler]
+	deferred  *eventTrie[*EventHandler]
+	seq       atomic.Uint64
+	// the events published since the last Dispatch() with deferred handlers
+	queueMutex sync.Mutex
+	queue      []Event
+}
+
+func newEventHandlers() *eventHandlers {
+	return &eventHandlers{
+		immediate: newEventTrie[*EventHandler](),
+		deferred:  newEventTrie[*EventHandler](),
+	}
+}
+
+// Handle adds a handler called as each event matching filter is published
+func (b *EventBus) Handle(filter *EventFilter, priority int, f func(e Event) (consumed bool)) *EventHandler {
+	return b.addHandler(filter, priority, false, f)
+}
+
+// HandleDeferred adds a handler called with each event matching filter
+// when the bus is next dispatched
+func (b *EventBus) HandleDeferred(filter *EventFilter, priority int, f func(e Event) (consumed bool)) *EventHandler {
+	return b.addHandler(filter, priority, true, f)
+}
+
+func (b *EventBus) addHandler(filter *EventFilter, priority int, deferred bool, f func(e Event) bool) *EventHandler {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	h := &EventHandler{
+		filter:   filter,
+		priority: priority,
+		deferred: deferred,
+		f:        f,
+		seq:      b.handlers.seq.Inc(),
+	}
+	for _, pattern := range filter.patterns {
+		b.handlers.trie(deferred).insert(pattern, h)
+	}
+	return h
+}
+
+// RemoveHandler removes a handler (events already queued for a deferred
+// handler aren't given to it)
+func (b *EventBus) RemoveHandler(h *EventHandler) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, pattern := range h.filter.patterns {
+		b.handlers.trie(h.deferred).remove(pattern, h)
+	}
+}
+
+func (hs *eventHandlers) trie(deferred bool) *eventTrie[*EventHandler] {
+	if deferred {
+		return hs.deferred
+	}
+	return hs.immediate
+}
+
+// the handlers whose filters match the type of e, in the order they're
+// called
+func (b *EventBus) matchHandlers(e Event, deferred bool) []*EventHandler {
+	b.mutex.RLock()
+	matched := b.handlers.trie(deferred).match(e.Type, nil)
+	b.mutex.RUnlock()
+	if len(matched) == 0 {
+		return nil
+	}
+	handlers := uniqueEventSubscribers(matched)
+	sort.Slice(handlers, func(i, j int) bool {
+		if handlers[i].priority != handlers[j].priority {
+			return handlers[i].priority > handlers[j].priority
+		}
+		return handlers[i].seq < handlers[j].seq
+	})
+	return handlers
+}
+
+// callEventHandlers calls the handlers in order, returning whether one
+// consumed the event
+func callEventHandlers(handlers []*EventHandler, e Event) bool {
+	for _, h := range handlers {
+		if h.filter.predicate != nil && !h.filter.predicate(e) {
+			continue
+		}
+		if h.f(e) {
+			logEvents("---- %s consumed by handler of priority %d", e.Type, h.priority)
+			return true
+		}
+	}
+	return false
+}
+
+// queue e for Dispatch() if it has deferred handlers
+func (b *EventBus) queueDeferred(e Event) {
+	b.mutex.RLock()
+	handled := len(b.handlers.deferred.match(e.Type, nil)) > 0
+	b.mutex.RUnlock()
+	if !handled {
+		return
+	}
+	b.handlers.queueMutex.Lock()
+	b.handlers.queue = append(b.handlers.queue, e)
+	b.handlers.queueMutex.Unlock()
+}
+
+// Dispatch calls the deferred handlers with the events published since the
+// last Dispatch(), in order. Events published by the handlers are
+// dispatched next time.
+func (b *EventBus) Dispatch() {
+	b.handlers.queueMutex.Lock()
+	queue := b.handlers.queue
+	b.handlers.queue = nil
+	b.handlers.queueMutex.Unlock()
+	for _, e := range queue {
+		callEventHandlers(b.matchHandlers(e, true), e)
+	}
+}
@@ -0,0 +1,95 @@
+package sameriver
+
+import (
+	"testing"
+)
+
+func TestEventHandlerPriority(t *testing.T) {
+	ev := NewEventBus("testing")
+	order := make([]string, 0)
+	handler := func(name string, consume bool) func(e Event) bool {
+		return func(e Event) bool {
+			order = append(order, name)
+			return consume && e.Data.(int) > 2
+		}
+	}
+	ev.Handle(SimpleEventFilter("damage"), 0, handler("armour", false))
+	ev.Handle(PatternEventFilter("damage", "*"), 10, handler("shield", true))
+	ev.Handle(SimpleEventFilter("damage"), 0, handler("health", false))
+	ev.Handle(PredicatePatternEventFilter([]string{"damage"}, func(e Event) bool {
+		return e.Data.(int) > 100
+	}), 20, handler("death", false))
+	ec := ev.Subscribe(SimpleEventFilter("damage"))
+
+	ev.Publish("damage", 1)
+	expected := []string{"shield", "armour", "health"}
+	if len(order) != 3 || order[0] != expected[0] || order[1] != expected[1] || order[2] != expected[2] {
+		t.Fatalf("should call handlers by priority, then in the order added, got %v", order)
+	}
+	if len(ec.C) != 1 {
+		t.Fatal("unconsumed event should reach the channels")
+	}
+
+	// the shield consumes damage above 2
+	order = order[:0]
+	ev.Publish("damage", 3)
+	if len(order) != 1 || len(ec.C) != 1 {
+		t.Fatalf("consumed event should go no further, got %v", order)
+	}
+	order = order[:0]
+	ev.Publish("damage", 200)
+	if len(order) != 2 || order[0] != "death" {
+		t.Fatalf("handler's predicate should be tested, got %v", order)
+	}
+}
+
+func TestEventHandlerDeferred(t *testing.T) {
+	w := testingWorld()
+	seen := make([]int, 0)
+	h := w.Events.HandleDeferred(SimpleEventFilter("damage"), 0, func(e Event) bool {
+		seen = append(seen, e.Data.(int))
+		// published while dispatching, so handled next update
+		if e.Data.(int) == 2 {
+			w.Events.Publish("damage", 3)
+		}
+		return false
+	})
+	consumer := w.Events.Handle(SimpleEventFilter("damage"), 0, func(e Event) bool {
+		return e.Data.(int) == 0
+	})
+	w.Events.Publish("damage", 0)
+	w.Events.Publish("damage", 1)
+	w.Events.Publish("damage", 2)
+	if len(seen) != 0 {
+		t.Fatal("deferred handler shouldn't be called until dispatched")
+	}
+	w.Update(FRAME_MS / 2)
+	if len(seen) != 2 || seen[0] != 1 || seen[1] != 2 {
+		t.Fatalf("should handle the unconsumed events in order on update, got %v", seen)
+	}
+	w.Update(FRAME_MS / 2)
+	if len(seen) != 3 || seen[2] != 3 {
+		t.Fatalf("should handle events published while dispatching on the next update, got %v", seen)
+	}
+
+	w.Events.RemoveHandler(consumer)
+	w.Events.RemoveHandler(h)
+	w.Events.Publish("damage", 0)
+	w.Update(FRAME_MS / 2)
+	if len(seen) != 3 {
+		t.Fatal("removed handler shouldn't be called")
+	}
+
+	// blackboards' buses are dispatched too
+	bb := w.CreateBlackboard("village")
+	heard := false
+	bb.Events.HandleDeferred(SimpleEventFilter("raid"), 0, func(e Event) bool {
+		heard = true
+		return true
+	})
+	bb.Events.Publish("raid", nil)
+	w.Update(FRAME_MS / 2)
+	if !heard {
+		t.Fatal("blackboard's deferred handler should be called on update")
+	}
+}
@@ -0,0 +1,119 @@
+package sameriver
+
+import (
+	"sync"
+)
+
+// EventTap sees the events an EventBus publishes which match its filter
+// (every event, for AllEventsFilter()), for debugging: it can log them and
+// keep the last so many, eg.
+//
+//	tap := w.Events.Tap(PatternEventFilter("combat.**"), 100)
+//	tap.SetLogging(true)
+//	...
+//	for _, e := range tap.Events() { ... }
+//
+// Unlike an EventChannel, a tap is called as each event is published, so
+// it can't fill up, and it needn't be drained.
+type EventTap struct {
+	bus     *EventBus
+	filter  *EventFilter
+	mutex   sync.Mutex
+	logging bool
+	// the last len(ring) events, the oldest at next once it's full
+	ring  []Event
+	next  int
+	count int
+}
+
+// Tap adds a tap keeping the last capacity events matching filter (0 to
+// keep none, eg. for a tap that only logs)
+func (b *EventBus) Tap(filter *EventFilter, capacity int) *EventTap {
+	t := &EventTap{
+		bus:    b,
+		filter: filter,
+		ring:   make([]Event, 0, capacity),
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	// copied rather than appended to in place, since publishers iterate
+	// over the slice they saw outside the lock
+	b.taps = append(append(make([]*EventTap, 0, len(b.taps)+1), b.taps...), t)
+	return t
+}
+
+// Untap removes the tap from its bus
+func (t *EventTap) Untap() {
+	b := t.bus
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	taps := make([]*EventTap, 0, len(b.taps))
+	for _, tap := range b.taps {
+		if tap != t {
+			taps = append(taps, tap)
+		}
+	}
+	b.taps = taps
+}
+
+func (b *EventBus) notifyTaps(e Event) {
+	b.mutex.RLock()
+	taps := b.taps
+	b.mutex.RUnlock()
+	for _, t := range taps {
+		if t.filter.Test(e) {
+			t.see(e)
+		}
+	}
+}
+
+func (t *EventTap) see(e Event) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.count++
+	if t.logging {
+		Logger.Printf("[EventTap %s] %s: %+v", t.bus.name, e.Type, e.Data)
+	}
+	if cap(t.ring) == 0 {
+		return
+	}
+	if len(t.ring) < cap(t.ring) {
+		t.ring = append(t.ring, e)
+		return
+	}
+	t.ring[t.next] = e
+	t.next = (t.next + 1) % len(t.ring)
+}
+
+// SetLogging sets whether the tap logs the events it sees
+func (t *EventTap) SetLogging(logging bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.logging = logging
+}
+
+// Events returns the events kept, oldest first
+func (t *EventTap) Events() []Event {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	events := make([]Event, 0, len(t.ring))
+	events = append(events, t.ring[t.next:]...)
+	return append(events, t.ring[:t.next]...)
+}
+
+// Count returns how many events the tap has seen, including those no
+// longer kept
+func (t *EventTap) Count() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.count
+}
+
+// Clear forgets the events kept and seen
+func (t *EventTap) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.ring = t.ring[:0]
+	t.next = 0
+	t.count = 0
+}
@@ -0,0 +1,33 @@
+package sameriver
+
+import (
+	"testing"
+)
+
+func TestEventTap(t *testing.T) {
+	ev := NewEventBus("testing")
+	tap := ev.Tap(AllEventsFilter(), 3)
+	combat := ev.Tap(PatternEventFilter("combat.**"), 0)
+	tap.SetLogging(true)
+	for _, eventType := range []string{"collision", "combat.hit", "spawn-request", "combat.miss"} {
+		ev.Publish(eventType, nil)
+	}
+	events := tap.Events()
+	if tap.Count() != 4 || len(events) != 3 {
+		t.Fatalf("should have seen 4 events and kept 3, got %d and %v", tap.Count(), events)
+	}
+	// oldest first
+	if events[0].Type != "combat.hit" || events[2].Type != "combat.miss" {
+		t.Fatalf("should keep the last 3 in order, got %v", events)
+	}
+	if combat.Count() != 2 || len(combat.Events()) != 0 {
+		t.Fatal("tap with no capacity should count but keep nothing")
+	}
+
+	tap.Clear()
+	combat.Untap()
+	ev.Publish("combat.hit", nil)
+	if tap.Count() != 1 || len(tap.Events()) != 1 || combat.Count() != 2 {
+		t.Fatal("cleared tap should start over and untapped tap should see nothing")
+	}
+}
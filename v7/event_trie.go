@@ -0,0 +1,111 @@
+package sameriver
+
+import (
+	"strings"
+)
+
+// eventTrie holds subscribers (channels or handlers) by the dotted parts
+// of their filters' patterns, so that publishing an event only visits the
+// nodes of patterns which could match its type, rather than testing every
+// subscription
+type eventTrie[T comparable] struct {
+	root *eventTrieNode[T]
+}
+
+type eventTrieNode[T comparable] struct {
+	children map[string]*eventTrieNode[T]
+	// the subscribers whose pattern ends here, in the order they subscribed
+	subscribers []T
+}
+
+func newEventTrie[T comparable]() *eventTrie[T] {
+	return &eventTrie[T]{root: newEventTrieNode[T]()}
+}
+
+func newEventTrieNode[T comparable]() *eventTrieNode[T] {
+	return &eventTrieNode[T]{children: make(map[string]*eventTrieNode[T])}
+}
+
+func (t *eventTrie[T]) insert(pattern string, x T) {
+	n := t.root
+	for _, part := range strings.Split(pattern, ".") {
+		child, ok := n.children[part]
+		if !ok {
+			child = newEventTrieNode[T]()
+			n.children[part] = child
+		}
+		n = child
+	}
+	n.subscribers = append(n.subscribers, x)
+}
+
+func (t *eventTrie[T]) remove(pattern string, x T) {
+	t.root.remove(strings.Split(pattern, "."), x)
+}
+
+// remove returns whether the node is now empty (and so can be pruned)
+func (n *eventTrieNode[T]) remove(parts []string, x T) bool {
+	if len(parts) == 0 {
+		for i, subscriber := range n.subscribers {
+			if subscriber == x {
+				n.subscribers = append(n.subscribers[:i:i], n.subscribers[i+1:]...)
+				break
+			}
+		}
+	} else if child, ok := n.children[parts[0]]; ok && child.remove(parts[1:], x) {
+		delete(n.children, parts[0])
+	}
+	return len(n.subscribers) == 0 && len(n.children) == 0
+}
+
+// match appends the subscribers whose patterns match the event type (one
+// subscribed with several matching patterns appears once per pattern)
+func (t *eventTrie[T]) match(eventType string, result []T) []T {
+	return t.root.match(strings.Split(eventType, "."), result)
+}
+
+func (n *eventTrieNode[T]) match(parts []string, result []T) []T {
+	if len(parts) == 0 {
+		result = append(result, n.subscribers...)
+	} else {
+		if child, ok := n.children[parts[0]]; ok {
+			result = child.match(parts[1:], result)
+		}
+		if child, ok := n.children["*"]; ok {
+			result = child.match(parts[1:], result)
+		}
+	}
+	if child, ok := n.children["**"]; ok {
+		// ** consumes any number of the parts
+		for i := 0; i <= len(parts); i++ {
+			result = child.match(parts[i:], result)
+		}
+	}
+	return result
+}
+
+// uniqueEventSubscribers removes the repeats of subscribers matched by
+// several patterns, keeping the first (scanning what came before is cheaper
+// than a map until there are many)
+func uniqueEventSubscribers[T comparable](matched []T) []T {
+	var seen map[T]bool
+	if len(matched) > 16 {
+		seen = make(map[T]bool, len(matched))
+	}
+	result := make([]T, 0, len(matched))
+	for i, x := range matched {
+		duplicate := false
+		if seen != nil {
+			duplicate = seen[x]
+			seen[x] = true
+		} else {
+			for _, prev := range matched[:i] {
+				duplicate = duplicate || prev == x
+			}
+		}
+		if !duplicate {
+			result = append(result, x)
+		}
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package sameriver
+
+import (
+	"runtime"
+	"sync"
+)
+
+// FlowFieldSystem steers entities with a FLOWFIELD component (the position
+// they're heading to) along the flow field for that target. Fields are
+// cached per target cell, so any number of entities sharing a target cost
+// one field build plus an O(1) lookup each per frame.
+//
+// Followers should not also have a MOVEMENTTARGET, or the SteeringSystem
+// will steer them toward that too.
+type FlowFieldSystem struct {
+	w         *World
+	steering  *SteeringSystem `sameriver-system-dependency:"-"`
+	Fields    *FlowFieldCache
+	followers *UpdatedEntityList
+	// distance from the target at which followers begin to slow down
+	SlowingRadius float64
+}
+
+func NewFlowFieldSystem(grid *PathGrid) *FlowFieldSystem {
+	return &FlowFieldSystem{
+		Fields:        NewFlowFieldCache(grid),
+		SlowingRadius: 30,
+	}
+}
+
+func (s *FlowFieldSystem) GetComponentDeps() []any {
+	return []any{
+		POSITION_, VEC2D, "POSITION",
+		VELOCITY_, VEC2D, "VELOCITY",
+		MAXVELOCITY_, FLOAT64, "MAXVELOCITY",
+		STEER_, VEC2D, "STEER",
+		MASS_, FLOAT64, "MASS",
+		FLOWFIELD_, VEC2D, "FLOWFIELD",
+	}
+}
+
+func (s *FlowFieldSystem) LinkWorld(w *World) {
+	s.w = w
+	s.followers = w.GetUpdatedEntityList(
+		w.EntityFilterFromComponentBitArray(
+			"flowfield",
+			w.Em.ComponentsTable.BitArrayFromIDs(
+				[]ComponentID{
+					POSITION_, VELOCITY_, MAXVELOCITY_, STEER_, MASS_, FLOWFIELD_,
+				})))
+}
+
+func (s *FlowFieldSystem) Update(dt_ms float64) {
+	s.buildFields()
+	s.ParallelUpdate()
+}
+
+// build any fields the followers need which aren't cached, so that the
+// per-entity steering only ever reads the cache
+func (s *FlowFieldSystem) buildFields() {
+	targets := make([]Vec2D, 0)
+	for _, e := range s.followers.entities {
+		target := *s.w.GetVec2D(e, FLOWFIELD_)
+		if _, ok := s.Fields.Cached(target); !ok {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) > 0 {
+		s.Fields.Build(targets)
+	}
+}
+
+func (s *FlowFieldSystem) ParallelUpdate() {
+	// divide the entities into N segments,
+	// where N is the number of CPU cores
+	numWorkers := runtime.NumCPU()
+	entitiesPerWorker := len(s.followers.entities) / numWorkers
+	remainder := len(s.followers.entities) % numWorkers
+
+	wg := sync.WaitGroup{}
+	wg.Add(numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
+		startIndex := i * entitiesPerWorker
+		endIndex := (i + 1) * entitiesPerWorker
+		if i == numWorkers-1 {
+			endIndex += remainder
+		}
+
+		go func(startIndex, endIndex int) {
+			for j := startIndex; j < endIndex; j++ {
+				e := s.followers.entities[j]
+				s.Follow(e)
+				s.steering.Apply(e)
+			}
+			wg.Done()
+		}(startIndex, endIndex)
+	}
+
+	wg.Wait()
+}
+
+func (s *FlowFieldSystem) SingleThreadUpdate() {
+	for _, e := range s.followers.entities {
+		s.Follow(e)
+		s.steering.Apply(e)
+	}
+}
+
+// Follow adds to the entity's STEER the force needed to move along the flow
+// field toward its FLOWFIELD target, slowing as it arrives
+func (s *FlowFieldSystem) Follow(e *Entity) {
+	pos := s.w.GetVec2D(e, POSITION_)
+	target := s.w.GetVec2D(e, FLOWFIELD_)
+	v := s.w.GetVec2D(e, VELOCITY_)
+	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+	st := s.w.GetVec2D(e, STEER_)
+	field, ok := s.Fields.Cached(*target)
+	if !ok {
+		return
+	}
+	dir, ok := field.direction(*pos, *target)
+	if !ok {
+		// at the target or unable to reach it; brake
+		st.Inc(v.Scale(-1))
+		return
+	}
+	desired := dir.Scale(*maxV)
+	distance := target.Sub(*pos).Magnitude()
+	if distance <= s.SlowingRadius {
+		desired = desired.Scale(distance / s.SlowingRadius)
+	}
+	st.Inc(desired.Sub(*v))
+}
+
+func (s *FlowFieldSystem) Expand(n int) {
+	// nil?
+}
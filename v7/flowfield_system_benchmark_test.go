@@ -0,0 +1,48 @@
+package sameriver
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func BenchmarkFlowFieldBuild(b *testing.B) {
+	g := NewPathGrid(256, 256, 4)
+	for i := 0; i < 256*256/8; i++ {
+		g.SetCost(rand.Intn(256), rand.Intn(256), PATH_COST_IMPASSABLE)
+	}
+	target := g.CellCenter(128, 128)
+	g.SetCost(128, 128, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewFlowField(g, target)
+	}
+}
+
+func benchmarkFlowFieldSystem(b *testing.B) (*World, *FlowFieldSystem) {
+	w := testingWorld()
+	ss := NewSteeringSystem()
+	fs := NewFlowFieldSystem(NewPathGrid(64, 64, 16))
+	w.RegisterSystems(ss, fs)
+	target := Vec2D{512, 512}
+	for i := 0; i < 1000; i++ {
+		testingSpawnFlowFieldFollower(w, Vec2D{rand.Float64() * 1024, rand.Float64() * 1024}, target)
+	}
+	w.Update(FRAME_MS / 2)
+	return w, fs
+}
+
+func BenchmarkFlowFieldSystemManySingleThreadUpdate(b *testing.B) {
+	_, fs := benchmarkFlowFieldSystem(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fs.SingleThreadUpdate()
+	}
+}
+
+func BenchmarkFlowFieldSystemManyParallelUpdate(b *testing.B) {
+	_, fs := benchmarkFlowFieldSystem(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fs.ParallelUpdate()
+	}
+}
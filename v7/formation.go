@@ -0,0 +1,124 @@
+package sameriver
+
+import (
+	"math"
+)
+
+// A FormationShape gives the slot offsets for n members (not counting the
+// leader) spaced spacing apart. Offsets are in the leader's frame: +X is
+// the direction the leader is heading, +Y is to its left
+type FormationShape func(n int, spacing float64) []Vec2D
+
+// members abreast of the leader, alternating left and right
+func FormationLine(n int, spacing float64) []Vec2D {
+	offsets := make([]Vec2D, n)
+	for i := range offsets {
+		rank := float64(i/2 + 1)
+		if i%2 == 0 {
+			offsets[i] = Vec2D{0, rank * spacing}
+		} else {
+			offsets[i] = Vec2D{0, -rank * spacing}
+		}
+	}
+	return offsets
+}
+
+// members in single file behind the leader
+func FormationColumn(n int, spacing float64) []Vec2D {
+	offsets := make([]Vec2D, n)
+	for i := range offsets {
+		offsets[i] = Vec2D{-float64(i+1) * spacing, 0}
+	}
+	return offsets
+}
+
+// a V behind the leader, alternating left and right arms
+func FormationWedge(n int, spacing float64) []Vec2D {
+	offsets := make([]Vec2D, n)
+	for i := range offsets {
+		rank := float64(i/2 + 1)
+		side := 1.0
+		if i%2 == 1 {
+			side = -1
+		}
+		offsets[i] = Vec2D{-rank * spacing, side * rank * spacing}
+	}
+	return offsets
+}
+
+// members evenly around the leader, at a radius which keeps them spacing
+// apart (and at least spacing from the leader)
+func FormationCircle(n int, spacing float64) []Vec2D {
+	offsets := make([]Vec2D, n)
+	radius := math.Max(spacing, float64(n)*spacing/(2*math.Pi))
+	for i := range offsets {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		offsets[i] = Vec2D{radius * math.Cos(angle), radius * math.Sin(angle)}
+	}
+	return offsets
+}
+
+// FormationCustom makes a shape from fixed slot offsets (in the leader's
+// frame, as above; spacing is ignored). Members beyond the given slots
+// trail behind in a column
+func FormationCustom(slots []Vec2D) FormationShape {
+	return func(n int, spacing float64) []Vec2D {
+		offsets := make([]Vec2D, n)
+		copy(offsets, slots)
+		if n > len(slots) {
+			last := Vec2D{0, 0}
+			if len(slots) > 0 {
+				last = slots[len(slots)-1]
+			}
+			for i := len(slots); i < n; i++ {
+				offsets[i] = last.Sub(Vec2D{float64(i-len(slots)+1) * spacing, 0})
+			}
+		}
+		return offsets
+	}
+}
+
+// Formation is a group of entities moving together relative to a leader.
+// Members[i] occupies slot i of the shape
+type Formation struct {
+	Leader  *Entity
+	Members []*Entity
+	Shape   FormationShape
+	Spacing float64
+	// the direction the formation faces; follows the leader's VELOCITY when
+	// it's moving, and holds its last value when it stops
+	Heading Vec2D
+}
+
+// Slot returns the slot index of e, or -1 if it isn't a member (the leader
+// has no slot)
+func (f *Formation) Slot(e *Entity) int {
+	for i, m := range f.Members {
+		if m == e {
+			return i
+		}
+	}
+	return -1
+}
+
+// Has returns whether e is the leader or a member
+func (f *Formation) Has(e *Entity) bool {
+	return e == f.Leader || f.Slot(e) != -1
+}
+
+// Size counts the leader and members
+func (f *Formation) Size() int {
+	return len(f.Members) + 1
+}
+
+// the world positions of each slot given the leader's position
+func (f *Formation) slotPositions(leaderPos Vec2D) []Vec2D {
+	offsets := f.Shape(len(f.Members), f.Spacing)
+	forward := f.Heading
+	left := Vec2D{-forward.Y, forward.X}
+	positions := make([]Vec2D, len(offsets))
+	for i, o := range offsets {
+		positions[i] = leaderPos.Add(forward.Scale(o.X)).Add(left.Scale(o.Y))
+	}
+	return positions
+}
@@ -0,0 +1,255 @@
+package sameriver
+
+import (
+	"math"
+)
+
+// FormationSystem moves groups of entities in formation: each Update(), the
+// MOVEMENTTARGET of every member is set to its slot's position relative to
+// the leader (the SteeringSystem does the moving).
+//
+// Each member's mind holds "formation.leader" (the leader's ID) and
+// "formation.slot", so the leader can be bound as a GOAP node with
+//
+//	planner.BindEntitySelectors(map[string]any{"leader": "mind.formation.leader"})
+//
+// and formations can be queried in EFDSL with InFormation(), FormationLeader()
+// and FormationSlot().
+type FormationSystem struct {
+	w *World
+	// formations by leader
+	formations map[*Entity]*Formation
+	// the formation each member (not leader) belongs to
+	memberOf map[*Entity]*Formation
+}
+
+func NewFormationSystem() *FormationSystem {
+	return &FormationSystem{
+		formations: make(map[*Entity]*Formation),
+		memberOf:   make(map[*Entity]*Formation),
+	}
+}
+
+func (s *FormationSystem) GetComponentDeps() []any {
+	return []any{
+		POSITION_, VEC2D, "POSITION",
+		VELOCITY_, VEC2D, "VELOCITY",
+		MOVEMENTTARGET_, VEC2D, "MOVEMENTTARGET",
+	}
+}
+
+func (s *FormationSystem) LinkWorld(w *World) {
+	s.w = w
+	w.AddDespawnCallback(s.onDespawn)
+	w.EFDSL.RegisterPredicates(s.efdslPredicates(w.EFDSL))
+}
+
+func (s *FormationSystem) Update(dt_ms float64) {
+	for _, f := range s.formations {
+		if s.w.EntityHasComponent(f.Leader, VELOCITY_) {
+			v := s.w.GetVec2D(f.Leader, VELOCITY_)
+			if v.Magnitude() > 1e-9 {
+				f.Heading = v.Unit()
+			}
+		}
+		for i, pos := range f.slotPositions(*s.w.GetVec2D(f.Leader, POSITION_)) {
+			*s.w.GetVec2D(f.Members[i], MOVEMENTTARGET_) = pos
+		}
+	}
+}
+
+// NewFormation makes leader the leader of a new formation, taking it out of
+// any formation it was in. If it already leads a formation, that one is
+// returned with its shape and spacing updated
+func (s *FormationSystem) NewFormation(
+	leader *Entity, shape FormationShape, spacing float64) *Formation {
+
+	if f, ok := s.formations[leader]; ok {
+		f.Shape = shape
+		f.Spacing = spacing
+		s.reslot(f)
+		return f
+	}
+	s.RemoveMember(leader)
+	f := &Formation{
+		Leader:  leader,
+		Members: make([]*Entity, 0),
+		Shape:   shape,
+		Spacing: spacing,
+		Heading: Vec2D{1, 0},
+	}
+	s.formations[leader] = f
+	return f
+}
+
+// AddMember adds e to f, taking it out of any other formation it was in
+func (s *FormationSystem) AddMember(f *Formation, e *Entity) {
+	if f.Has(e) {
+		return
+	}
+	s.RemoveMember(e)
+	if led, ok := s.formations[e]; ok {
+		s.Disband(led)
+	}
+	f.Members = append(f.Members, e)
+	s.memberOf[e] = f
+	s.reslot(f)
+}
+
+// RemoveMember takes e out of the formation it's a member of (if any), and
+// re-slots the rest
+func (s *FormationSystem) RemoveMember(e *Entity) {
+	f, ok := s.memberOf[e]
+	if !ok {
+		return
+	}
+	delete(s.memberOf, e)
+	removeEntityFromSlice(&f.Members, e)
+	e.Mind.Remove("formation.leader")
+	e.Mind.Remove("formation.slot")
+	s.reslot(f)
+}
+
+// Disband removes the formation, freeing all its members
+func (s *FormationSystem) Disband(f *Formation) {
+	for _, m := range f.Members {
+		delete(s.memberOf, m)
+		m.Mind.Remove("formation.leader")
+		m.Mind.Remove("formation.slot")
+	}
+	f.Members = f.Members[:0]
+	delete(s.formations, f.Leader)
+}
+
+// FormationOf returns the formation e leads or is a member of
+func (s *FormationSystem) FormationOf(e *Entity) (f *Formation, ok bool) {
+	if f, ok = s.formations[e]; ok {
+		return f, true
+	}
+	f, ok = s.memberOf[e]
+	return f, ok
+}
+
+// LeaderOf returns the leader of e's formation (e itself if it leads one),
+// or nil if it isn't in one
+func (s *FormationSystem) LeaderOf(e *Entity) *Entity {
+	if f, ok := s.FormationOf(e); ok {
+		return f.Leader
+	}
+	return nil
+}
+
+// LeaderSelector returns a GOAP/entity selector matching the leader of e's
+// formation
+func (s *FormationSystem) LeaderSelector(e *Entity) func(*Entity) bool {
+	return func(x *Entity) bool {
+		leader := s.LeaderOf(e)
+		return leader != nil && x == leader
+	}
+}
+
+// MemberSelector returns a GOAP/entity selector matching the members of
+// the formation led by leader
+func (s *FormationSystem) MemberSelector(leader *Entity) func(*Entity) bool {
+	return func(x *Entity) bool {
+		f, ok := s.memberOf[x]
+		return ok && f.Leader == leader
+	}
+}
+
+// SlotPosition returns where e's slot currently is
+func (s *FormationSystem) SlotPosition(e *Entity) (pos Vec2D, ok bool) {
+	f, ok := s.memberOf[e]
+	if !ok {
+		return Vec2D{}, false
+	}
+	return f.slotPositions(*s.w.GetVec2D(f.Leader, POSITION_))[f.Slot(e)], true
+}
+
+// reassign members to slots, giving each slot in turn to the nearest
+// unassigned member so that re-slotting doesn't send members across the
+// formation
+func (s *FormationSystem) reslot(f *Formation) {
+	positions := f.slotPositions(*s.w.GetVec2D(f.Leader, POSITION_))
+	unassigned := f.Members
+	assigned := make([]*Entity, 0, len(f.Members))
+	for _, slotPos := range positions {
+		nearest := -1
+		nearestDistance := math.Inf(+1)
+		for j, m := range unassigned {
+			d := s.w.GetVec2D(m, POSITION_).Sub(slotPos).Magnitude()
+			if d < nearestDistance {
+				nearest, nearestDistance = j, d
+			}
+		}
+		assigned = append(assigned, unassigned[nearest])
+		unassigned = append(unassigned[:nearest:nearest], unassigned[nearest+1:]...)
+	}
+	f.Members = assigned
+	for i, m := range f.Members {
+		m.Mind.Set("formation.leader", f.Leader.ID)
+		m.Mind.Set("formation.slot", i)
+	}
+}
+
+func (s *FormationSystem) onDespawn(e *Entity) {
+	if f, ok := s.formations[e]; ok {
+		delete(s.formations, e)
+		if len(f.Members) == 0 {
+			return
+		}
+		// the member in the front slot takes over
+		f.Leader = f.Members[0]
+		f.Members = f.Members[1:]
+		delete(s.memberOf, f.Leader)
+		f.Leader.Mind.Remove("formation.leader")
+		f.Leader.Mind.Remove("formation.slot")
+		s.formations[f.Leader] = f
+		s.reslot(f)
+		return
+	}
+	s.RemoveMember(e)
+}
+
+func (s *FormationSystem) efdslPredicates(e *EFDSLEvaluator) EFDSLPredicateMap {
+	return EFDSLPredicateMap{
+
+		// x is in the same formation as y (leader or member)
+		"InFormation": e.Predicate(
+			"IdentResolve<int>",
+			func(yID int) func(*Entity) bool {
+				y := e.w.GetEntity(yID)
+				return func(x *Entity) bool {
+					f, ok := s.FormationOf(y)
+					return ok && f.Has(x)
+				}
+			},
+		),
+
+		// x leads y's formation
+		"FormationLeader": e.Predicate(
+			"IdentResolve<int>",
+			func(yID int) func(*Entity) bool {
+				y := e.w.GetEntity(yID)
+				return func(x *Entity) bool {
+					return s.LeaderOf(y) == x
+				}
+			},
+		),
+
+		// x occupies the given slot of a formation
+		"FormationSlot": e.Predicate(
+			"int",
+			func(slot int) func(*Entity) bool {
+				return func(x *Entity) bool {
+					f, ok := s.memberOf[x]
+					return ok && f.Slot(x) == slot
+				}
+			},
+		),
+	}
+}
+
+func (s *FormationSystem) Expand(n int) {
+	// nil?
+}
@@ -0,0 +1,190 @@
+package sameriver
+
+import (
+	"math"
+	"testing"
+)
+
+func testingSpawnFormationMember(w *World, pos Vec2D) *Entity {
+	return w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_:       pos,
+			VELOCITY_:       Vec2D{0, 0},
+			MOVEMENTTARGET_: pos,
+			BOX_:            Vec2D{1, 1},
+		}})
+}
+
+func testingFormationWorld() (*World, *FormationSystem) {
+	w := testingWorld()
+	fs := NewFormationSystem()
+	w.RegisterSystems(fs)
+	return w, fs
+}
+
+func TestFormationShapes(t *testing.T) {
+	for name, shape := range map[string]FormationShape{
+		"line":   FormationLine,
+		"column": FormationColumn,
+		"wedge":  FormationWedge,
+		"circle": FormationCircle,
+		"custom": FormationCustom([]Vec2D{{-5, 0}, {-5, 5}}),
+	} {
+		offsets := shape(5, 10)
+		if len(offsets) != 5 {
+			t.Fatalf("%s should give 5 offsets, gave %d", name, len(offsets))
+		}
+		for i := range offsets {
+			if offsets[i].Magnitude() < 1 {
+				t.Fatalf("%s slot %d is on top of the leader", name, i)
+			}
+			for j := i + 1; j < len(offsets); j++ {
+				if offsets[i].Sub(offsets[j]).Magnitude() < 1 {
+					t.Fatalf("%s slots %d and %d overlap: %v", name, i, j, offsets)
+				}
+			}
+		}
+	}
+	if FormationCustom([]Vec2D{{-5, 0}, {-5, 5}})(2, 10)[1] != (Vec2D{-5, 5}) {
+		t.Fatal("custom shape should use the given slots")
+	}
+}
+
+func TestFormationSlotTargets(t *testing.T) {
+	w, fs := testingFormationWorld()
+	leader := testingSpawnFormationMember(w, Vec2D{100, 100})
+	f := fs.NewFormation(leader, FormationColumn, 10)
+	member := testingSpawnFormationMember(w, Vec2D{50, 50})
+	fs.AddMember(f, member)
+	// heading +x, the column trails behind in -x
+	w.Update(FRAME_MS / 2)
+	if *w.GetVec2D(member, MOVEMENTTARGET_) != (Vec2D{90, 100}) {
+		t.Fatalf("member should be sent behind the leader, sent to %v",
+			*w.GetVec2D(member, MOVEMENTTARGET_))
+	}
+	// the formation turns with the leader
+	*w.GetVec2D(leader, VELOCITY_) = Vec2D{0, 1}
+	w.Update(FRAME_MS / 2)
+	target := *w.GetVec2D(member, MOVEMENTTARGET_)
+	if math.Abs(target.X-100) > 1e-9 || math.Abs(target.Y-90) > 1e-9 {
+		t.Fatalf("slot should rotate with the leader's heading, got %v", target)
+	}
+	// and holds its heading when the leader stops
+	*w.GetVec2D(leader, VELOCITY_) = Vec2D{0, 0}
+	w.Update(FRAME_MS / 2)
+	if *w.GetVec2D(member, MOVEMENTTARGET_) != target {
+		t.Fatal("formation should keep its heading when the leader stops")
+	}
+}
+
+func TestFormationReslotOnDespawn(t *testing.T) {
+	w, fs := testingFormationWorld()
+	leader := testingSpawnFormationMember(w, Vec2D{100, 100})
+	f := fs.NewFormation(leader, FormationColumn, 10)
+	a := testingSpawnFormationMember(w, Vec2D{90, 100})
+	b := testingSpawnFormationMember(w, Vec2D{80, 100})
+	c := testingSpawnFormationMember(w, Vec2D{70, 100})
+	for _, m := range []*Entity{c, a, b} {
+		fs.AddMember(f, m)
+	}
+	// members take the slots nearest them
+	if f.Slot(a) != 0 || f.Slot(b) != 1 || f.Slot(c) != 2 {
+		t.Fatalf("members should be slotted by proximity: %d %d %d",
+			f.Slot(a), f.Slot(b), f.Slot(c))
+	}
+	w.Despawn(a)
+	if f.Size() != 3 || f.Slot(b) != 0 || f.Slot(c) != 1 {
+		t.Fatal("members should move up when one despawns")
+	}
+	if b.Mind.Get("formation.slot") != 0 {
+		t.Fatal("member's mind should know its slot")
+	}
+	// the leader despawning promotes the front member
+	w.Despawn(leader)
+	if fs.LeaderOf(c) != b {
+		t.Fatal("front member should take over as leader")
+	}
+	if b.Mind.Has("formation.leader") {
+		t.Fatal("new leader should no longer have a leader in mind")
+	}
+	if c.Mind.Get("formation.leader") != b.ID {
+		t.Fatal("remaining member should know its new leader")
+	}
+}
+
+func TestFormationMembership(t *testing.T) {
+	w, fs := testingFormationWorld()
+	l1 := testingSpawnFormationMember(w, Vec2D{100, 100})
+	l2 := testingSpawnFormationMember(w, Vec2D{200, 100})
+	f1 := fs.NewFormation(l1, FormationLine, 10)
+	f2 := fs.NewFormation(l2, FormationLine, 10)
+	m := testingSpawnFormationMember(w, Vec2D{100, 110})
+	fs.AddMember(f1, m)
+	fs.AddMember(f2, m)
+	if f1.Has(m) || !f2.Has(m) {
+		t.Fatal("joining a formation should leave the previous one")
+	}
+	// a leader joining another formation disbands its own
+	fs.AddMember(f1, m)
+	fs.AddMember(f2, l1)
+	if _, ok := fs.FormationOf(m); ok {
+		t.Fatal("members of a disbanded formation should be free")
+	}
+	if fs.LeaderOf(l1) != l2 {
+		t.Fatal("leader should have joined the other formation")
+	}
+	fs.RemoveMember(l1)
+	if _, ok := fs.FormationOf(l1); ok || l1.Mind.Has("formation.leader") {
+		t.Fatal("removed member should be free")
+	}
+}
+
+func TestFormationEFDSL(t *testing.T) {
+	w, fs := testingFormationWorld()
+	leader := testingSpawnFormationMember(w, Vec2D{100, 100})
+	f := fs.NewFormation(leader, FormationWedge, 10)
+	a := testingSpawnFormationMember(w, Vec2D{90, 110})
+	b := testingSpawnFormationMember(w, Vec2D{90, 90})
+	fs.AddMember(f, a)
+	fs.AddMember(f, b)
+	testingSpawnFormationMember(w, Vec2D{500, 500})
+
+	inFormation, err := w.EFDSLFilterEntity(a, "InFormation(self)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inFormation) != 3 {
+		t.Fatalf("should find the leader and both members, found %v", inFormation)
+	}
+	leaders, _ := w.EFDSLFilterEntity(a, "FormationLeader(self)")
+	if len(leaders) != 1 || leaders[0] != leader {
+		t.Fatalf("should find the leader, found %v", leaders)
+	}
+	front, _ := w.EFDSLFilter("FormationSlot(0)")
+	if len(front) != 1 || front[0] != a {
+		t.Fatalf("should find the member in slot 0 (wedge left arm), found %v", front)
+	}
+}
+
+func TestFormationGOAPNode(t *testing.T) {
+	w, fs := testingFormationWorld()
+	leader := testingSpawnFormationMember(w, Vec2D{100, 100})
+	f := fs.NewFormation(leader, FormationCircle, 10)
+	member := testingSpawnFormationMember(w, Vec2D{90, 100})
+	other := testingSpawnFormationMember(w, Vec2D{95, 100})
+	fs.AddMember(f, member)
+
+	p := NewGOAPPlanner(member, w)
+	p.BindEntitySelectors(map[string]any{
+		"leader":  "mind.formation.leader",
+		"leader2": fs.LeaderSelector(member),
+	})
+	for _, node := range []string{"leader", "leader2"} {
+		if !p.boundSelectors[node](leader) || p.boundSelectors[node](other) {
+			t.Fatalf("node %s should select the formation's leader", node)
+		}
+	}
+	if !fs.MemberSelector(leader)(member) || fs.MemberSelector(leader)(other) {
+		t.Fatal("member selector should select members")
+	}
+}
@@ -8,6 +8,7 @@ require (
 	github.com/stretchr/testify v1.3.0
 	github.com/veandco/go-sdl2 v0.4.30
 	go.uber.org/atomic v1.10.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -2,6 +2,7 @@ package sameriver
 
 import (
 	"fmt"
+	"math"
 
 	"strings"
 )
@@ -52,24 +53,34 @@ type GOAPAction struct {
 	// k: varName, v: op
 	// eg, "health": "+"
 	ops map[string]string
+
+	// quantities of resources claimed per count of the action, when
+	// planning with GOAPReservations
+	claims map[string]int
 }
 
 type GOAPEff struct {
-	val int
+	val float64
 	op  string
-	f   func(count int, x int) int
+	f   func(count int, x float64) float64
 }
 
-func GOAPEffFunc(op string, val int) func(count int, x int) int {
+func GOAPEffFunc(op string, val float64) func(count int, x float64) float64 {
 	switch op {
 	case "+":
-		return func(count int, x int) int { return count*val + x }
+		return func(count int, x float64) float64 { return float64(count)*val + x }
 	case "-":
-		return func(count int, x int) int { return x - count*val }
+		return func(count int, x float64) float64 { return x - float64(count)*val }
 	case "=":
-		return func(count int, x int) int { return val }
+		return func(count int, x float64) float64 { return val }
+	case "*":
+		return func(count int, x float64) float64 { return x * math.Pow(val, float64(count)) }
+	case "min":
+		return func(count int, x float64) float64 { return math.Min(x, val) }
+	case "max":
+		return func(count int, x float64) float64 { return math.Max(x, val) }
 	default:
-		panic("Got an unspecified op in GOAPEffFunc() [valid: +,-,=]")
+		panic("Got an unspecified op in GOAPEffFunc() [valid: +,-,=,*,min,max]")
 	}
 }
 
@@ -84,9 +95,21 @@ func NewGOAPAction(spec map[string]any) *GOAPAction {
 	if !ok {
 		otherNodes = []string{}
 	}
-	cost := spec["cost"].(int)
+	claims, ok := spec["claims"].(map[string]int)
+	if !ok {
+		claims = map[string]int{}
+	}
+	var cost IntOrFunc
+	switch c := spec["cost"].(type) {
+	case int:
+		cost = c
+	case func() int:
+		cost = c
+	default:
+		panic(fmt.Sprintf("cost of action %s should be int or func() int", name))
+	}
 	pres := spec["pres"]
-	effs := spec["effs"].(map[string]int)
+	effs := goapSpecVals(spec["effs"])
 
 	a := &GOAPAction{
 		spec:            spec,
@@ -101,6 +124,7 @@ func NewGOAPAction(spec map[string]any) *GOAPAction {
 		effModalSetters: make(map[string]func(ws *GOAPWorldState, op string, x int)), // set by GOAPEvaluator
 		effs:            make(map[string]*GOAPEff),
 		ops:             make(map[string]string),
+		claims:          claims,
 	}
 	for spec, val := range effs {
 		split := strings.Split(spec, ",")
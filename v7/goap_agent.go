@@ -0,0 +1,454 @@
+package sameriver
+
+import (
+	"sort"
+)
+
+// the result of running a GOAP action implementation for a tick
+type GOAPActionStatus int
+
+const (
+	GOAP_ACTION_RUNNING GOAPActionStatus = iota
+	GOAP_ACTION_SUCCESS
+	GOAP_ACTION_FAILURE
+)
+
+// GOAPActionContext is given to an action implementation while it runs
+type GOAPActionContext struct {
+	Agent  *GOAPAgent
+	Entity *Entity
+	// the entity bound to the action's node when the plan was made
+	Node   *Entity
+	Action *GOAPAction
+	// ms since the action started (not counting moving to its node)
+	Elapsed_ms float64
+	// scratch space for the implementation, fresh each time the action starts
+	State map[string]any
+}
+
+// GOAPActionImpl implements an action (by name) for a GOAPAgent. Update is
+// called every tick once the entity has reached the action's node, until it
+// returns success or failure. Start and Stop are optional; Stop is called
+// only if the action is interrupted by a replan
+type GOAPActionImpl struct {
+	Start  func(ctx *GOAPActionContext)
+	Update func(ctx *GOAPActionContext, dt_ms float64) GOAPActionStatus
+	Stop   func(ctx *GOAPActionContext)
+}
+
+// GOAPTimedAction takes duration_ms to do, then calls done (if non-nil),
+// failing if done returns false (eg. the tree was already chopped down)
+func GOAPTimedAction(
+	duration_ms float64, done func(ctx *GOAPActionContext) bool) *GOAPActionImpl {
+
+	return &GOAPActionImpl{
+		Update: func(ctx *GOAPActionContext, dt_ms float64) GOAPActionStatus {
+			if ctx.Elapsed_ms+dt_ms < duration_ms {
+				return GOAP_ACTION_RUNNING
+			}
+			if done != nil && !done(ctx) {
+				return GOAP_ACTION_FAILURE
+			}
+			return GOAP_ACTION_SUCCESS
+		},
+	}
+}
+
+// GOAPBTAction runs a behaviour tree as the action. Each tick the tree is
+// executed and run is called with the leaf it selects (run should call
+// Done() on the leaf when it's finished); the action succeeds when the root
+// completes, and fails if the tree fails to select a leaf
+func GOAPBTAction(
+	btr *BTRunner,
+	tree *BehaviourTree,
+	run func(ctx *GOAPActionContext, leaf *BTNode, dt_ms float64)) *GOAPActionImpl {
+
+	return &GOAPActionImpl{
+		Start: func(ctx *GOAPActionContext) {
+			tree.Reset()
+		},
+		Update: func(ctx *GOAPActionContext, dt_ms float64) GOAPActionStatus {
+			state := btr.ExecuteBT(ctx.Entity, tree)
+			if state == nil || state.Action == nil {
+				return GOAP_ACTION_FAILURE
+			}
+			if !tree.Root.Complete {
+				run(ctx, state.Action, dt_ms)
+			}
+			if tree.Root.Complete {
+				return GOAP_ACTION_SUCCESS
+			}
+			return GOAP_ACTION_RUNNING
+		},
+	}
+}
+
+// a goal a GOAPAgent can pursue; the agent pursues the highest-priority goal
+// it can find a plan for
+type GOAPAgentGoal struct {
+	Name string
+	// a goal spec as accepted by GOAPPlanner.Plan(), or a func() any giving
+	// one each time the agent plans
+	Spec any
+	// nil means a constant priority of 0
+	Priority func() float64
+}
+
+func (g *GOAPAgentGoal) spec() any {
+	if f, ok := g.Spec.(func() any); ok {
+		return f()
+	}
+	return g.Spec
+}
+
+func (g *GOAPAgentGoal) priority() float64 {
+	if g.Priority == nil {
+		return 0
+	}
+	return g.Priority()
+}
+
+// GOAPAgent executes the plans made by a GOAPPlanner for an entity. Each
+// action in the plan is run by the GOAPActionImpl bound to its name, after
+// the entity has reached the action's node (by setting its MOVEMENTTARGET,
+// if it has one). Actions with no implementation succeed as soon as the
+// node is reached.
+//
+// Before each step, the action's preconditions are re-checked against the
+// state the agent believes it's in (the state it planned from, plus the
+// effects of the actions done so far), overridden by any vals from the live
+// State() and by modal checks of the world as it is now. If they don't hold,
+// if the node has despawned, or if the action fails, the agent replans.
+// It also replans when a goal with higher priority than the current one
+// becomes plannable. Replanning goes through the same planner, so selectors
+// bound with BindEntitySelectors() are reused.
+//
+// With a GOAPNodeMemory (see SetNodeMemory()), the node of a failed action
+// is remembered so the replan avoids it for a while. If the planner has
+// GOAPReservations, the agent holds reservations for its plan.
+type GOAPAgent struct {
+	w *World
+	e *Entity
+
+	Planner *GOAPPlanner
+	// gives the live world state to plan from / check against; nil means
+	// an empty state (modal vals are filled in by the planner)
+	State func() *GOAPWorldState
+	// maxIter passed to GOAPPlanner.Plan()
+	MaxIter int
+	// how long to wait before trying again after failing to find a plan,
+	// and between checks for higher-priority goals
+	ReplanCooldown_ms float64
+
+	// called when a new plan is adopted
+	OnPlan func(goal *GOAPAgentGoal, path *GOAPPath)
+	// called when a goal's plan has been carried out
+	OnGoalDone func(goal *GOAPAgentGoal)
+
+	goals []*GOAPAgentGoal
+	impls map[string]*GOAPActionImpl
+
+	goal *GOAPAgentGoal
+	path *GOAPPath
+	step int
+	// the entities bound to nodes for the current plan
+	nodes map[string]*Entity
+	// the planned-from state with the effs of completed steps applied
+	believed *GOAPWorldState
+	// non-nil while the current step's action is running
+	ctx         *GOAPActionContext
+	cooldown_ms float64
+	nodeMemory  *GOAPNodeMemory
+}
+
+// NewGOAPAgent creates an agent executing plans from p for e, running as an
+// entity logic named "goap-agent"
+func NewGOAPAgent(e *Entity, w *World, p *GOAPPlanner) *GOAPAgent {
+	a := &GOAPAgent{
+		w:                 w,
+		e:                 e,
+		Planner:           p,
+		MaxIter:           500,
+		ReplanCooldown_ms: 500,
+		goals:             make([]*GOAPAgentGoal, 0),
+		impls:             make(map[string]*GOAPActionImpl),
+	}
+	w.AddEntityLogic(e, "goap-agent", a.Update)
+	return a
+}
+
+func (a *GOAPAgent) AddGoal(goal *GOAPAgentGoal) {
+	a.goals = append(a.goals, goal)
+}
+
+func (a *GOAPAgent) BindAction(name string, impl *GOAPActionImpl) {
+	a.impls[name] = impl
+}
+
+func (a *GOAPAgent) BindActions(impls map[string]*GOAPActionImpl) {
+	for name, impl := range impls {
+		a.BindAction(name, impl)
+	}
+}
+
+// Goal returns the goal being pursued, or nil if idle
+func (a *GOAPAgent) Goal() *GOAPAgentGoal {
+	return a.goal
+}
+
+// Path returns the plan being executed, or nil if idle
+func (a *GOAPAgent) Path() *GOAPPath {
+	return a.path
+}
+
+// Action returns the action of the current step, or nil if idle
+func (a *GOAPAgent) Action() *GOAPAction {
+	if a.path == nil {
+		return nil
+	}
+	return a.path.path[a.step]
+}
+
+// Interrupt abandons the current plan; the agent replans on its next update
+func (a *GOAPAgent) Interrupt() {
+	a.stopAction()
+	a.dropPlan()
+	a.cooldown_ms = 0
+}
+
+// forget the current plan, releasing its reservations
+func (a *GOAPAgent) dropPlan() {
+	a.path = nil
+	a.goal = nil
+	if a.Planner.reservations != nil {
+		a.Planner.reservations.ReleaseAll(a.e)
+	}
+}
+
+// InterruptFailed abandons the current plan as Interrupt() does, but
+// remembers the current step's node as failed (eg. when the agent had to
+// flee while leading an ox, that ox is avoided for a while)
+func (a *GOAPAgent) InterruptFailed() {
+	if a.path != nil {
+		action := a.path.path[a.step]
+		a.rememberFailure(action.Node, a.nodes[action.Node])
+	}
+	a.Interrupt()
+}
+
+// SetNodeMemory sets the memory of failed nodes for the agent and its
+// planner, updated as the agent updates (nil to not remember failures)
+func (a *GOAPAgent) SetNodeMemory(m *GOAPNodeMemory) {
+	a.nodeMemory = m
+	a.Planner.SetNodeMemory(m)
+}
+
+func (a *GOAPAgent) NodeMemory() *GOAPNodeMemory {
+	return a.nodeMemory
+}
+
+func (a *GOAPAgent) rememberFailure(node string, e *Entity) {
+	if a.nodeMemory != nil && e != nil && e != a.e {
+		a.nodeMemory.Fail(e, node)
+	}
+}
+
+func (a *GOAPAgent) Update(dt_ms float64) {
+	if a.e.Despawned {
+		return
+	}
+	if a.nodeMemory != nil {
+		a.nodeMemory.Update(dt_ms)
+	}
+	if a.cooldown_ms > 0 {
+		a.cooldown_ms -= dt_ms
+	}
+	if a.path != nil && a.cooldown_ms <= 0 {
+		a.preemptForHigherPriorityGoal()
+	}
+	if a.path == nil {
+		if a.cooldown_ms > 0 || !a.replan() {
+			return
+		}
+	}
+
+	action := a.path.path[a.step]
+	if a.ctx == nil {
+		node := a.nodes[action.Node]
+		if node == nil || node.Despawned || !a.presFulfilled(action) {
+			a.fail()
+			return
+		}
+		if !a.atNode(node) {
+			return
+		}
+		a.startAction(action, node)
+	}
+
+	impl, ok := a.impls[action.Name]
+	status := GOAP_ACTION_SUCCESS
+	if ok {
+		status = impl.Update(a.ctx, dt_ms)
+		a.ctx.Elapsed_ms += dt_ms
+	}
+	switch status {
+	case GOAP_ACTION_SUCCESS:
+		a.ctx = nil
+		a.believed = a.Planner.applyActionBasic(action, a.believed, true)
+		a.step++
+		if a.step == len(a.path.path) {
+			goal := a.goal
+			a.dropPlan()
+			if a.OnGoalDone != nil {
+				a.OnGoalDone(goal)
+			}
+		}
+	case GOAP_ACTION_FAILURE:
+		a.rememberFailure(action.Node, a.ctx.Node)
+		a.ctx = nil
+		a.fail()
+	}
+}
+
+func (a *GOAPAgent) liveState() *GOAPWorldState {
+	if a.State == nil {
+		return NewGOAPWorldState(nil)
+	}
+	return a.State()
+}
+
+// the goals sorted by descending priority
+func (a *GOAPAgent) goalsByPriority() []*GOAPAgentGoal {
+	goals := make([]*GOAPAgentGoal, len(a.goals))
+	copy(goals, a.goals)
+	priorities := make(map[*GOAPAgentGoal]float64, len(goals))
+	for _, g := range goals {
+		priorities[g] = g.priority()
+	}
+	sort.SliceStable(goals, func(i, j int) bool {
+		return priorities[goals[i]] > priorities[goals[j]]
+	})
+	return goals
+}
+
+// try to make a non-empty plan for goal, adopting it if found
+func (a *GOAPAgent) tryPlan(goal *GOAPAgentGoal) bool {
+	start := a.liveState()
+	path, ok := a.Planner.Plan(start, goal.spec(), a.MaxIter)
+	if !ok || len(path.path) == 0 {
+		return false
+	}
+	nodes := make(map[string]*Entity)
+	for node, e := range path.statesAlong[len(path.path)].ModalEntities {
+		nodes[node] = e
+	}
+	if r := a.Planner.reservations; r != nil && !r.reservePlan(a.e, path, nodes) {
+		return false
+	}
+	a.stopAction()
+	a.goal = goal
+	a.path = path
+	a.step = 0
+	a.nodes = nodes
+	a.believed = start.CopyOf()
+	if a.OnPlan != nil {
+		a.OnPlan(goal, path)
+	}
+	return true
+}
+
+// plan for the highest-priority goal we can, returning whether we found one
+func (a *GOAPAgent) replan() bool {
+	for _, goal := range a.goalsByPriority() {
+		if a.tryPlan(goal) {
+			return true
+		}
+	}
+	a.cooldown_ms = a.ReplanCooldown_ms
+	return false
+}
+
+func (a *GOAPAgent) preemptForHigherPriorityGoal() {
+	current := a.goal.priority()
+	for _, goal := range a.goalsByPriority() {
+		if goal == a.goal || goal.priority() <= current {
+			break
+		}
+		if a.tryPlan(goal) {
+			return
+		}
+	}
+	a.cooldown_ms = a.ReplanCooldown_ms
+}
+
+func (a *GOAPAgent) fail() {
+	a.stopAction()
+	a.dropPlan()
+	a.replan()
+}
+
+func (a *GOAPAgent) startAction(action *GOAPAction, node *Entity) {
+	a.ctx = &GOAPActionContext{
+		Agent:  a,
+		Entity: a.e,
+		Node:   node,
+		Action: action,
+		State:  make(map[string]any),
+	}
+	if impl, ok := a.impls[action.Name]; ok && impl.Start != nil {
+		impl.Start(a.ctx)
+	}
+}
+
+// stop the running action, if any
+func (a *GOAPAgent) stopAction() {
+	if a.ctx == nil {
+		return
+	}
+	if impl, ok := a.impls[a.ctx.Action.Name]; ok && impl.Stop != nil {
+		impl.Stop(a.ctx)
+	}
+	a.ctx = nil
+}
+
+// check the action's pres against the believed state, updated with the
+// live state and modal checks of the world as it is
+func (a *GOAPAgent) presFulfilled(action *GOAPAction) bool {
+	ws := a.believed.CopyOf()
+	for k, v := range a.liveState().vals {
+		ws.vals[k] = v
+	}
+	ws.w = a.w
+	ws.modal = make(map[string]any)
+	ws.ModalEntities = a.nodes
+	// as in planning, vars without a value or modal check are taken as 0
+	for _, tg := range action.pres.temporalGoals {
+		for varName := range tg.vars {
+			if _, ok := ws.vals[varName]; !ok {
+				ws.vals[varName] = 0
+			}
+		}
+	}
+	return a.Planner.presFulfilled(action, ws)
+}
+
+// whether the entity is at the node, moving it there if not. Entities
+// without a MOVEMENTTARGET are assumed to be wherever they need to be
+func (a *GOAPAgent) atNode(node *Entity) bool {
+	if node == a.e || !a.w.EntityHasComponent(a.e, MOVEMENTTARGET_) {
+		return true
+	}
+	pos, box := *a.w.GetVec2D(a.e, POSITION_), *a.w.GetVec2D(a.e, BOX_)
+	nodePos := *a.w.GetVec2D(node, POSITION_)
+	var at bool
+	if a.w.EntityHasComponent(node, BOX_) {
+		at = RectIntersectsRect(pos, box, nodePos, *a.w.GetVec2D(node, BOX_))
+	} else {
+		at = nodePos.Sub(pos).Magnitude() <= box.Magnitude()/2
+	}
+	if at {
+		return true
+	}
+	*a.w.GetVec2D(a.e, MOVEMENTTARGET_) = nodePos
+	return false
+}
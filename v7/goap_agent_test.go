@@ -0,0 +1,288 @@
+package sameriver
+
+import (
+	"testing"
+)
+
+// a woodcutter who needs an axe (the "self.hasAxe" STATE val) to chop the
+// tree, with chopped wood counted in the live state
+type testingWoodcutter struct {
+	w        *World
+	e        *Entity
+	tree     *Entity
+	axe      *Entity
+	p        *GOAPPlanner
+	a        *GOAPAgent
+	chopped  int
+	plans    int
+	goalDone int
+}
+
+func testingGOAPAgentWorld() *World {
+	w := testingWorld()
+	w.RegisterComponents([]any{MOVEMENTTARGET_, VEC2D, "MOVEMENTTARGET"})
+	return w
+}
+
+func testingSpawnWoodcutter(w *World, moves bool) *testingWoodcutter {
+	wc := &testingWoodcutter{w: w}
+	components := map[ComponentID]any{
+		POSITION_: Vec2D{0, 0},
+		BOX_:      Vec2D{1, 1},
+		STATE_:    map[string]int{"hasAxe": 0},
+	}
+	if moves {
+		components[MOVEMENTTARGET_] = Vec2D{0, 0}
+	}
+	wc.e = w.Spawn(map[string]any{"components": components})
+	wc.axe = w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{10, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"tags": []string{"axe"},
+	})
+	wc.tree = w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{20, 0},
+			BOX_:      Vec2D{2, 2},
+		},
+		"tags": []string{"tree"},
+	})
+
+	wc.p = NewGOAPPlanner(wc.e, w)
+	wc.p.AddActions(
+		NewGOAPAction(map[string]any{
+			"name": "getAxe",
+			"node": "axe",
+			"cost": 1,
+			"pres": nil,
+			"effs": map[string]int{
+				"self.hasAxe,=": 1,
+			},
+		}),
+		NewGOAPAction(map[string]any{
+			"name": "chopTree",
+			"node": "tree",
+			"cost": 1,
+			"pres": map[string]int{
+				"self.hasAxe,=": 1,
+			},
+			"effs": map[string]int{
+				"woodChopped,+": 1,
+			},
+		}),
+	)
+
+	wc.a = NewGOAPAgent(wc.e, w, wc.p)
+	wc.a.State = func() *GOAPWorldState {
+		return NewGOAPWorldState(map[string]int{"woodChopped": wc.chopped})
+	}
+	wc.a.OnPlan = func(goal *GOAPAgentGoal, path *GOAPPath) { wc.plans++ }
+	wc.a.OnGoalDone = func(goal *GOAPAgentGoal) { wc.goalDone++ }
+	wc.a.AddGoal(&GOAPAgentGoal{
+		Name: "wood",
+		Spec: map[string]int{"woodChopped,>=": 2},
+	})
+	wc.a.BindActions(map[string]*GOAPActionImpl{
+		"getAxe": GOAPTimedAction(100, func(ctx *GOAPActionContext) bool {
+			w.GetIntMap(ctx.Entity, STATE_).Set("hasAxe", 1)
+			return true
+		}),
+		"chopTree": GOAPTimedAction(200, func(ctx *GOAPActionContext) bool {
+			wc.chopped += ctx.Action.Count
+			return true
+		}),
+	})
+	return wc
+}
+
+// run the agent, teleporting the entity to its MOVEMENTTARGET
+func (wc *testingWoodcutter) run(ms float64) {
+	for t := 0.0; t < ms; t += FRAME_MS {
+		wc.a.Update(FRAME_MS)
+		if wc.w.EntityHasComponent(wc.e, MOVEMENTTARGET_) {
+			*wc.w.GetVec2D(wc.e, POSITION_) = *wc.w.GetVec2D(wc.e, MOVEMENTTARGET_)
+		}
+	}
+}
+
+func TestGOAPAgentExecutesPlan(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, true)
+	wc.run(FRAME_MS)
+	if wc.a.Action() == nil || wc.a.Action().Name != "getAxe" {
+		t.Fatalf("should have planned to get the axe first, plan: %v", wc.a.Path())
+	}
+	if *w.GetVec2D(wc.e, MOVEMENTTARGET_) != *w.GetVec2D(wc.axe, POSITION_) {
+		t.Fatal("should move to the axe")
+	}
+	wc.run(1000)
+	if wc.chopped != 2 {
+		t.Fatalf("should have chopped 2 wood, chopped %d", wc.chopped)
+	}
+	if wc.goalDone != 1 || wc.plans != 1 {
+		t.Fatalf("should have carried out one plan, planned %d times, done %d",
+			wc.plans, wc.goalDone)
+	}
+	if wc.a.Goal() != nil {
+		t.Fatal("should be idle once the goal is satisfied")
+	}
+}
+
+func TestGOAPAgentWaitsToReachNode(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, true)
+	for i := 0; i < 20; i++ {
+		// never let it reach the axe
+		wc.a.Update(FRAME_MS)
+	}
+	if w.GetIntMap(wc.e, STATE_).Get("hasAxe") != 0 {
+		t.Fatal("should not get the axe without reaching it")
+	}
+}
+
+func TestGOAPAgentReplansWhenPresFail(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	// lose the axe just after picking it up
+	for w.GetIntMap(wc.e, STATE_).Get("hasAxe") == 0 {
+		wc.run(FRAME_MS)
+	}
+	w.GetIntMap(wc.e, STATE_).Set("hasAxe", 0)
+	wc.run(FRAME_MS)
+	if wc.plans != 2 || wc.a.Action().Name != "getAxe" {
+		t.Fatalf("should have replanned to get the axe again")
+	}
+	wc.run(1000)
+	if wc.chopped != 2 {
+		t.Fatalf("should have chopped 2 wood, chopped %d", wc.chopped)
+	}
+}
+
+func TestGOAPAgentReplansWhenActionFails(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	stopped := 0
+	attempts := 0
+	wc.a.BindAction("getAxe", &GOAPActionImpl{
+		Update: func(ctx *GOAPActionContext, dt_ms float64) GOAPActionStatus {
+			attempts++
+			if attempts == 1 {
+				return GOAP_ACTION_FAILURE
+			}
+			w.GetIntMap(ctx.Entity, STATE_).Set("hasAxe", 1)
+			return GOAP_ACTION_SUCCESS
+		},
+		Stop: func(ctx *GOAPActionContext) { stopped++ },
+	})
+	wc.run(1000)
+	if attempts != 2 || wc.plans != 2 || wc.chopped != 2 {
+		t.Fatalf("should replan after failure: attempts %d, plans %d, chopped %d",
+			attempts, wc.plans, wc.chopped)
+	}
+	if stopped != 0 {
+		t.Fatal("actions that finish (even failing) should not be stopped")
+	}
+}
+
+func TestGOAPAgentReplansWhenNodeDespawns(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, true)
+	other := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{-15, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"tags": []string{"axe"},
+	})
+	// head for the nearer axe, then have it despawn before we get there
+	wc.a.Update(FRAME_MS)
+	w.Despawn(wc.axe)
+	wc.run(FRAME_MS * 2)
+	if wc.plans != 2 || *w.GetVec2D(wc.e, MOVEMENTTARGET_) != *w.GetVec2D(other, POSITION_) {
+		t.Fatal("should replan for the other axe when the first despawns")
+	}
+}
+
+func TestGOAPAgentPreemptsForHigherPriorityGoal(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	wc.a.ReplanCooldown_ms = 0
+	hunger := 0.0
+	ate := false
+	wc.p.AddActions(NewGOAPAction(map[string]any{
+		"name": "eat",
+		"node": "self",
+		"cost": 1,
+		"pres": nil,
+		"effs": map[string]int{
+			"fed,=": 1,
+		},
+	}))
+	wc.a.AddGoal(&GOAPAgentGoal{
+		Name:     "eat",
+		Spec:     map[string]int{"fed,=": 1},
+		Priority: func() float64 { return hunger },
+	})
+	stopped := false
+	wc.a.BindAction("eat", GOAPTimedAction(50, func(ctx *GOAPActionContext) bool {
+		ate = true
+		hunger = 0
+		return true
+	}))
+	wc.a.BindAction("getAxe", &GOAPActionImpl{
+		Update: func(ctx *GOAPActionContext, dt_ms float64) GOAPActionStatus {
+			return GOAP_ACTION_RUNNING
+		},
+		Stop: func(ctx *GOAPActionContext) { stopped = true },
+	})
+	wc.run(FRAME_MS * 3)
+	if wc.a.Goal().Name != "wood" {
+		t.Fatal("should pursue wood while not hungry")
+	}
+	hunger = 1
+	wc.run(FRAME_MS)
+	if wc.a.Goal().Name != "eat" || !stopped {
+		t.Fatal("should interrupt getting the axe to eat")
+	}
+	wc.run(100)
+	if !ate || wc.a.Goal().Name != "wood" {
+		t.Fatal("should return to chopping wood after eating")
+	}
+}
+
+func TestGOAPAgentBTAction(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	btr := NewBTRunner()
+	leaves := make([]string, 0)
+	tree := NewBehaviourTree("getAxe", &BTNode{
+		Name: "Sequence",
+		Selector: func(self *BTNode) int {
+			return self.CompletedChildren
+		},
+		CompletionPredicate: func(self *BTNode) bool {
+			return self.CompletedChildren == len(self.Children)
+		},
+		Children: []*BTNode{
+			{Name: "reach"},
+			{Name: "grab"},
+		},
+	})
+	wc.a.BindAction("getAxe", GOAPBTAction(btr, tree,
+		func(ctx *GOAPActionContext, leaf *BTNode, dt_ms float64) {
+			leaves = append(leaves, leaf.Name)
+			if leaf.Name == "grab" {
+				w.GetIntMap(ctx.Entity, STATE_).Set("hasAxe", 1)
+			}
+			leaf.Done()
+		}))
+	wc.run(1000)
+	if len(leaves) != 2 || leaves[0] != "reach" || leaves[1] != "grab" {
+		t.Fatalf("should have run the tree's leaves in order, ran %v", leaves)
+	}
+	if wc.chopped != 2 {
+		t.Fatalf("should have chopped 2 wood, chopped %d", wc.chopped)
+	}
+}
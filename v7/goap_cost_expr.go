@@ -0,0 +1,200 @@
+package sameriver
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// a parsed GOAP action cost expression, evaluated for the planning entity
+type goapCostExpr func(e *Entity, w *World) float64
+
+// parseGOAPCostExpr parses an arithmetic cost expression such as
+//
+//	"5 + 2 * mind.fatigue"
+//
+// supporting numbers, + - * /, parentheses, and the identifiers
+// mind.<key> (from the entity's mind) and bb.<name>.<key> (from a world
+// blackboard). Identifiers which aren't set, or aren't numbers, are 0
+func parseGOAPCostExpr(src string) (goapCostExpr, error) {
+	p := &goapCostExprParser{src: src}
+	expr, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.src) {
+		return nil, fmt.Errorf("unexpected %q at %d in cost expression %q",
+			p.src[p.pos:], p.pos, src)
+	}
+	return expr, nil
+}
+
+// evaluate the expression as an action cost (rounded, and never negative)
+func (c goapCostExpr) cost(e *Entity, w *World) int {
+	return int(math.Max(0, math.Round(c(e, w))))
+}
+
+type goapCostExprParser struct {
+	src string
+	pos int
+}
+
+func (p *goapCostExprParser) skipSpace() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *goapCostExprParser) peek() byte {
+	p.skipSpace()
+	if p.pos == len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// expr := term (('+'|'-') term)*
+func (p *goapCostExprParser) expr() (goapCostExpr, error) {
+	lhs, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return lhs, nil
+		}
+		p.pos++
+		rhs, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		l := lhs
+		if op == '+' {
+			lhs = func(e *Entity, w *World) float64 { return l(e, w) + rhs(e, w) }
+		} else {
+			lhs = func(e *Entity, w *World) float64 { return l(e, w) - rhs(e, w) }
+		}
+	}
+}
+
+// term := factor (('*'|'/') factor)*
+func (p *goapCostExprParser) term() (goapCostExpr, error) {
+	lhs, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return lhs, nil
+		}
+		p.pos++
+		rhs, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		l := lhs
+		if op == '*' {
+			lhs = func(e *Entity, w *World) float64 { return l(e, w) * rhs(e, w) }
+		} else {
+			lhs = func(e *Entity, w *World) float64 {
+				d := rhs(e, w)
+				if d == 0 {
+					return 0
+				}
+				return l(e, w) / d
+			}
+		}
+	}
+}
+
+// factor := number | identifier | '(' expr ')' | '-' factor
+func (p *goapCostExprParser) factor() (goapCostExpr, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of cost expression %q", p.src)
+	case c == '(':
+		p.pos++
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ) in cost expression %q", p.src)
+		}
+		p.pos++
+		return inner, nil
+	case c == '-':
+		p.pos++
+		inner, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *Entity, w *World) float64 { return -inner(e, w) }, nil
+	case c == '.' || unicode.IsDigit(rune(c)):
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] == '.' || unicode.IsDigit(rune(p.src[p.pos]))) {
+			p.pos++
+		}
+		x, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number in cost expression %q: %s", p.src, err)
+		}
+		return func(e *Entity, w *World) float64 { return x }, nil
+	case c == '_' || unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.src) && goapCostIdentRune(rune(p.src[p.pos])) {
+			p.pos++
+		}
+		return goapCostIdentifier(p.src[start:p.pos])
+	}
+	return nil, fmt.Errorf("unexpected %q at %d in cost expression %q",
+		string(c), p.pos, p.src)
+}
+
+func goapCostIdentRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func goapCostIdentifier(ident string) (goapCostExpr, error) {
+	parts := strings.SplitN(ident, ".", 3)
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("bad identifier %s in cost expression", ident)
+		}
+	}
+	switch {
+	case parts[0] == "mind" && len(parts) >= 2:
+		key := strings.TrimPrefix(ident, "mind.")
+		return func(e *Entity, w *World) float64 {
+			return goapCostNumber(e.Mind.Get(key))
+		}, nil
+	case parts[0] == "bb" && len(parts) == 3:
+		name, key := parts[1], parts[2]
+		return func(e *Entity, w *World) float64 {
+			bb, ok := w.Blackboards[name]
+			if !ok {
+				return 0
+			}
+			return goapCostNumber(bb.Get(key))
+		}, nil
+	}
+	return nil, fmt.Errorf(
+		"unknown identifier %s in cost expression (should be mind.<key> or bb.<name>.<key>)",
+		ident)
+}
+
+func goapCostNumber(x any) float64 {
+	switch v := x.(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
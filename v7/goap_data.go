@@ -0,0 +1,611 @@
+package sameriver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+/*
+GOAPLibrary is a set of GOAP actions, goals, vars and modal vals loaded from
+data, so that NPC behaviour can be authored without Go changes. The JSON
+schema is:
+
+	{
+	  "vars": {
+	    // see GOAPVar; type is int (the default), float or bool, and min,
+	    // max and clamp are optional
+	    "hunger": {"type": "float", "min": 0, "max": 100, "clamp": true},
+	    "coins": {"min": 0}
+	  },
+	  "modalVals": [
+	    // notation strings, as would be generated for effs
+	    "self.inventoryHas(axe)",
+	    "ox.in(field)",
+	    "field.tilled",
+	    // or an INT component of a node
+	    {"name": "booze", "node": "self", "component": "BOOZEAMOUNT"}
+	  ],
+	  "actions": [
+	    {
+	      "name": "chopTree",
+	      "node": "tree",
+	      "otherNodes": ["field"],            // optional
+	      "travelWithNode": false,            // optional
+	      "claims": {"wood": 1},              // optional, see GOAPReservations
+	      "cost": 1,                          // or an expression, see below
+	      "pres": {"self.hasAxe,=": 1},       // optional; an array for temporal pres
+	      "effs": {"woodChopped,+": 1}       // ops are +, -, =, *, min, max
+	    }
+	  ],
+	  "goals": {
+	    "wood": {"woodChopped,>=": 3},        // a goal spec (or array of them)
+	    "eat": {"spec": {"fed,=": 1}, "priority": 2}
+	  }
+	}
+
+Values in pres, effs and goals can be numbers or bools. Costs can be
+arithmetic expressions over the planning entity's mind and the
+world's blackboards, eg. "5 + 2 * mind.fatigue" or "10 * bb.village.danger",
+evaluated each time the cost is needed.
+
+Everything is validated when loaded; all problems found are reported
+together in a *GOAPDataError. Components named in modal vals are checked
+when the library is added to a planner with AddLibrary(), as are * effs on
+modal vals, which being ints can only be multiplied by whole numbers.
+*/
+type GOAPLibrary struct {
+	// action specs as accepted by NewGOAPAction(), except that cost may be
+	// a goapCostExpr to be bound to the planner's entity
+	actions   []map[string]any
+	vars      []GOAPVar
+	modalVals []goapModalValSpec
+	Goals     map[string]*GOAPLibraryGoal
+}
+
+type GOAPLibraryGoal struct {
+	Name string
+	// a goal spec as accepted by GOAPPlanner.Plan()
+	Spec     any
+	Priority float64
+}
+
+// AgentGoal makes a GOAPAgentGoal with the goal's (constant) priority
+func (g *GOAPLibraryGoal) AgentGoal() *GOAPAgentGoal {
+	priority := g.Priority
+	return &GOAPAgentGoal{
+		Name:     g.Name,
+		Spec:     g.Spec,
+		Priority: func() float64 { return priority },
+	}
+}
+
+type goapModalValSpec struct {
+	notation  string
+	name      string
+	node      string
+	component string
+}
+
+// GOAPDataError lists the problems found validating GOAP data
+type GOAPDataError struct {
+	Problems []string
+}
+
+func (err *GOAPDataError) Error() string {
+	return fmt.Sprintf("invalid GOAP data:\n    %s", strings.Join(err.Problems, "\n    "))
+}
+
+func (err *GOAPDataError) add(at string, format string, args ...any) {
+	err.Problems = append(err.Problems, at+": "+fmt.Sprintf(format, args...))
+}
+
+func LoadGOAPLibraryFile(filename string) (*GOAPLibrary, error) {
+	Logger.Printf("Loading GOAP library from %s...", filename)
+	jsonFile, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer jsonFile.Close()
+	contents, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+	return LoadGOAPLibraryJSON(contents)
+}
+
+func LoadGOAPLibraryJSON(jsonStr []byte) (*GOAPLibrary, error) {
+	var data map[string]any
+	if err := json.Unmarshal(jsonStr, &data); err != nil {
+		return nil, err
+	}
+	return LoadGOAPLibrary(data)
+}
+
+// LoadGOAPLibrary validates GOAP data already decoded into generic maps and
+// slices (as by encoding/json, or a YAML decoder), building a library
+func LoadGOAPLibrary(data map[string]any) (*GOAPLibrary, error) {
+	lib := &GOAPLibrary{
+		actions:   make([]map[string]any, 0),
+		vars:      make([]GOAPVar, 0),
+		modalVals: make([]goapModalValSpec, 0),
+		Goals:     make(map[string]*GOAPLibraryGoal),
+	}
+	errs := &GOAPDataError{}
+	for _, k := range goapSortedKeys(data) {
+		if k != "vars" && k != "modalVals" && k != "actions" && k != "goals" {
+			errs.add(k, "unknown field (expected vars, modalVals, actions, goals)")
+		}
+	}
+	if vars, ok := data["vars"]; ok {
+		m, ok := vars.(map[string]any)
+		if !ok {
+			errs.add("vars", "should be an object of vars by name")
+		}
+		for _, name := range goapSortedKeys(m) {
+			if v, ok := goapLoadVar("vars."+name, name, m[name], errs); ok {
+				lib.vars = append(lib.vars, v)
+			}
+		}
+	}
+	if modalVals, ok := data["modalVals"]; ok {
+		list, ok := modalVals.([]any)
+		if !ok {
+			errs.add("modalVals", "should be an array")
+		}
+		for i, v := range list {
+			if spec, ok := goapLoadModalVal(fmt.Sprintf("modalVals[%d]", i), v, errs); ok {
+				lib.modalVals = append(lib.modalVals, spec)
+			}
+		}
+	}
+	if actions, ok := data["actions"]; ok {
+		list, ok := actions.([]any)
+		if !ok {
+			errs.add("actions", "should be an array")
+		}
+		names := make(map[string]bool)
+		boolVars := make(map[string]bool)
+		for _, v := range lib.vars {
+			boolVars[v.Name] = v.Type == GOAP_VAR_BOOL
+		}
+		for i, v := range list {
+			at := fmt.Sprintf("actions[%d]", i)
+			if m, ok := v.(map[string]any); ok {
+				if name, ok := m["name"].(string); ok {
+					if names[name] {
+						errs.add(at, "duplicate action name %s", name)
+						continue
+					}
+					names[name] = true
+				}
+			}
+			if spec, ok := goapLoadAction(at, v, errs); ok {
+				// bool vars can only be set
+				effs := spec["effs"].(map[string]any)
+				for _, k := range goapSortedKeys(effs) {
+					if split := strings.Split(k, ","); boolVars[split[0]] && split[1] != "=" {
+						errs.add(fmt.Sprintf("%s(%s).effs", at, spec["name"]),
+							"%q: bool var %s can only be set with =", k, split[0])
+					}
+				}
+				lib.actions = append(lib.actions, spec)
+			}
+		}
+	}
+	if goals, ok := data["goals"]; ok {
+		m, ok := goals.(map[string]any)
+		if !ok {
+			errs.add("goals", "should be an object of goals by name")
+		}
+		for _, name := range goapSortedKeys(m) {
+			if goal, ok := goapLoadGoal("goals."+name, name, m[name], errs); ok {
+				lib.Goals[name] = goal
+			}
+		}
+	}
+	if len(errs.Problems) > 0 {
+		return nil, errs
+	}
+	return lib, nil
+}
+
+// Goal returns the spec of the named goal, for GOAPPlanner.Plan()
+func (lib *GOAPLibrary) Goal(name string) any {
+	if g, ok := lib.Goals[name]; ok {
+		return g.Spec
+	}
+	return nil
+}
+
+// AddLibrary adds the library's vars, modal vals and actions to the planner
+// (modal vals before actions, so that actions' pres pick up their checks)
+func (p *GOAPPlanner) AddLibrary(lib *GOAPLibrary) error {
+	p.DeclareVars(lib.vars...)
+	errs := &GOAPDataError{}
+	vals := make([]GOAPModalVal, 0, len(lib.modalVals))
+	for i, spec := range lib.modalVals {
+		val, err := p.createModalValFromSpec(spec)
+		if err != nil {
+			errs.add(fmt.Sprintf("modalVals[%d]", i), "%s", err)
+			continue
+		}
+		vals = append(vals, val)
+	}
+	if len(errs.Problems) > 0 {
+		return errs
+	}
+	p.AddModalVals(vals...)
+
+	actions := make([]*GOAPAction, 0, len(lib.actions))
+	for i, spec := range lib.actions {
+		if expr, ok := spec["cost"].(goapCostExpr); ok {
+			bound := make(map[string]any, len(spec))
+			for k, v := range spec {
+				bound[k] = v
+			}
+			bound["cost"] = func() int { return expr.cost(p.e, p.w) }
+			spec = bound
+		}
+		action := NewGOAPAction(spec)
+		for _, effSpec := range goapSortedKeys(spec["effs"].(map[string]any)) {
+			varName := strings.Split(effSpec, ",")[0]
+			if p.isModalVarName(varName) {
+				if err := goapCheckModalEff(action, varName); err != nil {
+					errs.add(fmt.Sprintf("actions[%d](%s).effs", i, action.Name), "%s", err)
+				}
+			}
+		}
+		actions = append(actions, action)
+	}
+	if len(errs.Problems) > 0 {
+		return errs
+	}
+	p.AddActions(actions...)
+	return nil
+}
+
+func (p *GOAPPlanner) createModalValFromSpec(spec goapModalValSpec) (GOAPModalVal, error) {
+	if spec.notation != "" {
+		if METHOD_NOTATION_RE.MatchString(spec.notation) {
+			node, method, param := p.parseParenthesesNotation(spec.notation)
+			if _, ok := p.w.systems["ItemSystem"]; method == "inventoryHas" && !ok {
+				return GOAPModalVal{}, fmt.Errorf("%s needs an ItemSystem", spec.notation)
+			}
+			return p.createModalValMethodNotation(node, method, param), nil
+		}
+		parts := strings.SplitN(spec.notation, ".", 2)
+		return p.createModalValDotNotation(parts[0], parts[1]), nil
+	}
+	id, ok := p.w.Em.ComponentsTable.StringsRev[spec.component]
+	if !ok {
+		return GOAPModalVal{}, fmt.Errorf("unknown component %s", spec.component)
+	}
+	if p.w.Em.ComponentsTable.Kinds[id] != INT {
+		return GOAPModalVal{}, fmt.Errorf("component %s should be INT", spec.component)
+	}
+	node := spec.node
+	return GOAPModalVal{
+		name:  spec.name,
+		nodes: []string{node},
+		check: func(ws *GOAPWorldState) int {
+			return *ws.GetModal(ws.ModalEntities[node], id).(*int)
+		},
+		effModalSet: func(ws *GOAPWorldState, op string, x int) {
+			val := goapModalApply(op, *ws.GetModal(ws.ModalEntities[node], id).(*int), x)
+			ws.SetModal(ws.ModalEntities[node], id, &val)
+		},
+	}, nil
+}
+
+//
+// validation
+//
+
+var goapGoalOps = map[string]bool{"<": true, "<=": true, "=": true, ">=": true, ">": true}
+var goapEffOps = map[string]bool{"+": true, "-": true, "=": true, "*": true, "min": true, "max": true}
+
+func goapSortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// a number as decoded by encoding/json (float64) or a YAML decoder (int for
+// integers)
+func goapNumber(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	}
+	return 0, false
+}
+
+func goapLoadInt(at string, v any, errs *GOAPDataError) (int, bool) {
+	x, ok := goapNumber(v)
+	if !ok || x != math.Trunc(x) {
+		errs.add(at, "should be an integer, got %v", v)
+		return 0, false
+	}
+	return int(x), true
+}
+
+func goapLoadString(at string, v any, errs *GOAPDataError) (string, bool) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		errs.add(at, "should be a non-empty string, got %v", v)
+		return "", false
+	}
+	return s, true
+}
+
+// load a "varName,op": val map, where ops are valid for a goal or eff
+func goapLoadVarMap(
+	at string, v any, ops map[string]bool, allowEach bool,
+	errs *GOAPDataError) (map[string]any, bool) {
+
+	m, ok := v.(map[string]any)
+	if !ok {
+		errs.add(at, "should be an object of \"varName,op\": value")
+		return nil, false
+	}
+	result := make(map[string]any)
+	valid := true
+	for _, k := range goapSortedKeys(m) {
+		spec := k
+		if allowEach {
+			spec = strings.TrimPrefix(spec, "EACH:")
+		}
+		split := strings.Split(spec, ",")
+		if len(split) != 2 || split[0] == "" {
+			errs.add(at, "%q should be \"varName,op\"", k)
+			valid = false
+			continue
+		}
+		if !ops[split[1]] {
+			errs.add(at, "%q has invalid op %q", k, split[1])
+			valid = false
+			continue
+		}
+		if b, ok := m[k].(bool); ok {
+			result[k] = b
+		} else if x, ok := goapNumber(m[k]); ok {
+			result[k] = x
+		} else {
+			errs.add(at+"."+k, "should be a number or bool, got %v", m[k])
+			valid = false
+		}
+	}
+	return result, valid
+}
+
+// load a goal spec: an object, or an array of objects for a temporal goal
+func goapLoadGoalSpec(at string, v any, errs *GOAPDataError) (any, bool) {
+	if list, ok := v.([]any); ok {
+		temporal := make([]any, 0, len(list))
+		valid := true
+		for i, g := range list {
+			m, ok := goapLoadVarMap(fmt.Sprintf("%s[%d]", at, i), g, goapGoalOps, true, errs)
+			valid = valid && ok
+			temporal = append(temporal, m)
+		}
+		return temporal, valid
+	}
+	return goapLoadVarMap(at, v, goapGoalOps, true, errs)
+}
+
+func goapLoadAction(at string, v any, errs *GOAPDataError) (map[string]any, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		errs.add(at, "should be an object")
+		return nil, false
+	}
+	n := len(errs.Problems)
+	spec := make(map[string]any)
+	for _, k := range goapSortedKeys(m) {
+		switch k {
+		case "name", "node", "otherNodes", "travelWithNode", "cost", "pres", "effs", "claims":
+		default:
+			errs.add(at, "unknown field %s", k)
+		}
+	}
+	if name, ok := goapLoadString(at+".name", m["name"], errs); ok {
+		spec["name"] = name
+		at = fmt.Sprintf("%s(%s)", at, name)
+	}
+	if node, ok := goapLoadString(at+".node", m["node"], errs); ok {
+		spec["node"] = node
+	}
+	if others, ok := m["otherNodes"]; ok {
+		list, ok := others.([]any)
+		if !ok {
+			errs.add(at+".otherNodes", "should be an array of node names")
+		}
+		otherNodes := make([]string, 0, len(list))
+		for i, o := range list {
+			if node, ok := goapLoadString(fmt.Sprintf("%s.otherNodes[%d]", at, i), o, errs); ok {
+				otherNodes = append(otherNodes, node)
+			}
+		}
+		spec["otherNodes"] = otherNodes
+	}
+	if claimsV, ok := m["claims"]; ok {
+		claimsM, ok := claimsV.(map[string]any)
+		if !ok {
+			errs.add(at+".claims", "should be an object of resource: quantity")
+		}
+		claims := make(map[string]int)
+		for _, resource := range goapSortedKeys(claimsM) {
+			if n, ok := goapLoadInt(at+".claims."+resource, claimsM[resource], errs); ok {
+				claims[resource] = n
+			}
+		}
+		spec["claims"] = claims
+	}
+	if travel, ok := m["travelWithNode"]; ok {
+		if b, ok := travel.(bool); ok {
+			spec["travelWithNode"] = b
+		} else {
+			errs.add(at+".travelWithNode", "should be a bool")
+		}
+	}
+	switch cost := m["cost"].(type) {
+	case float64, int, int64:
+		if x, ok := goapLoadInt(at+".cost", cost, errs); ok {
+			spec["cost"] = x
+		}
+	case string:
+		expr, err := parseGOAPCostExpr(cost)
+		if err != nil {
+			errs.add(at+".cost", "%s", err)
+		} else {
+			spec["cost"] = expr
+		}
+	default:
+		errs.add(at+".cost", "should be an integer or a cost expression")
+	}
+	if pres, ok := m["pres"]; ok && pres != nil {
+		if spec["pres"], ok = goapLoadGoalSpec(at+".pres", pres, errs); !ok {
+			delete(spec, "pres")
+		}
+	} else {
+		spec["pres"] = nil
+	}
+	if effs, ok := goapLoadVarMap(at+".effs", m["effs"], goapEffOps, false, errs); ok {
+		spec["effs"] = effs
+	}
+	return spec, len(errs.Problems) == n
+}
+
+func goapLoadGoal(at string, name string, v any, errs *GOAPDataError) (*GOAPLibraryGoal, bool) {
+	goal := &GOAPLibraryGoal{Name: name}
+	specV := v
+	if m, ok := v.(map[string]any); ok {
+		if _, ok := m["spec"]; ok {
+			for _, k := range goapSortedKeys(m) {
+				if k != "spec" && k != "priority" {
+					errs.add(at, "unknown field %s", k)
+				}
+			}
+			specV = m["spec"]
+			if priority, ok := m["priority"]; ok {
+				if x, ok := goapNumber(priority); ok {
+					goal.Priority = x
+				} else {
+					errs.add(at+".priority", "should be a number")
+				}
+			}
+			at += ".spec"
+		}
+	}
+	spec, ok := goapLoadGoalSpec(at, specV, errs)
+	goal.Spec = spec
+	return goal, ok
+}
+
+func goapLoadVar(at string, name string, v any, errs *GOAPDataError) (GOAPVar, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		errs.add(at, "should be an object")
+		return GOAPVar{}, false
+	}
+	n := len(errs.Problems)
+	result := GOAPVar{Name: name}
+	for _, k := range goapSortedKeys(m) {
+		switch k {
+		case "type", "min", "max", "clamp":
+		default:
+			errs.add(at, "unknown field %s", k)
+		}
+	}
+	if t, ok := m["type"]; ok {
+		switch t {
+		case "int":
+			result.Type = GOAP_VAR_INT
+		case "float":
+			result.Type = GOAP_VAR_FLOAT
+		case "bool":
+			result.Type = GOAP_VAR_BOOL
+		default:
+			errs.add(at+".type", "should be int, float or bool, got %v", t)
+		}
+	}
+	bound := func(k string, unbounded float64) float64 {
+		x, ok := m[k]
+		if !ok {
+			return unbounded
+		}
+		if f, ok := goapNumber(x); ok {
+			return f
+		}
+		errs.add(at+"."+k, "should be a number, got %v", x)
+		return unbounded
+	}
+	_, hasMin := m["min"]
+	_, hasMax := m["max"]
+	if hasMin || hasMax {
+		result.Domain = &NumericInterval{bound("min", math.Inf(-1)), bound("max", math.Inf(+1))}
+		if result.Domain.A > result.Domain.B {
+			errs.add(at, "min should be <= max")
+		}
+	}
+	if clamp, ok := m["clamp"]; ok {
+		if b, ok := clamp.(bool); ok {
+			result.Clamp = b
+		} else {
+			errs.add(at+".clamp", "should be a bool")
+		}
+	}
+	return result, len(errs.Problems) == n
+}
+
+func goapLoadModalVal(at string, v any, errs *GOAPDataError) (goapModalValSpec, bool) {
+	switch val := v.(type) {
+	case string:
+		if METHOD_NOTATION_RE.MatchString(val) {
+			matches := METHOD_NOTATION_RE.FindStringSubmatch(val)
+			if matches[0] != val {
+				errs.add(at, "%q should be exactly node.method(param)", val)
+				return goapModalValSpec{}, false
+			}
+			if matches[2] != "inventoryHas" && matches[2] != "in" {
+				errs.add(at, "unknown method %s (expected inventoryHas, in)", matches[2])
+				return goapModalValSpec{}, false
+			}
+			return goapModalValSpec{notation: val}, true
+		}
+		parts := strings.SplitN(val, ".", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			errs.add(at, "%q should be node.key or node.method(param)", val)
+			return goapModalValSpec{}, false
+		}
+		return goapModalValSpec{notation: val}, true
+	case map[string]any:
+		n := len(errs.Problems)
+		spec := goapModalValSpec{node: "self"}
+		for _, k := range goapSortedKeys(val) {
+			if k != "name" && k != "node" && k != "component" {
+				errs.add(at, "unknown field %s", k)
+			}
+		}
+		spec.name, _ = goapLoadString(at+".name", val["name"], errs)
+		spec.component, _ = goapLoadString(at+".component", val["component"], errs)
+		if node, ok := val["node"]; ok {
+			spec.node, _ = goapLoadString(at+".node", node, errs)
+		}
+		return spec, len(errs.Problems) == n
+	}
+	errs.add(at, "should be a notation string or an object")
+	return goapModalValSpec{}, false
+}
@@ -0,0 +1,247 @@
+package sameriver
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func testingGOAPLibraryWorld(t *testing.T) (*World, *Entity, *GOAPPlanner) {
+	w := testingWorld()
+	const (
+		COINS = GENERICTAGS_ + 1 + iota
+	)
+	w.RegisterComponents([]any{
+		COINS, INT, "COINS",
+	})
+	e := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{0, 0},
+			BOX_:      Vec2D{1, 1},
+			STATE_:    map[string]int{"hasAxe": 0},
+			COINS:     5,
+		},
+	})
+	w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{30, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"tags": []string{"axe"},
+	})
+	w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{10, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"tags": []string{"tree"},
+	})
+	lib, err := LoadGOAPLibraryFile("test_data/woodcutter_goap.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewGOAPPlanner(e, w)
+	if err := p.AddLibrary(lib); err != nil {
+		t.Fatal(err)
+	}
+	return w, e, p
+}
+
+func TestGOAPLibraryLoad(t *testing.T) {
+	lib, err := LoadGOAPLibraryFile("test_data/woodcutter_goap.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lib.actions) != 3 || len(lib.modalVals) != 2 {
+		t.Fatal("should have loaded the actions and modal vals")
+	}
+	if lib.Goals["rich"].Priority != 0.5 || lib.Goals["wood"].Priority != 0 {
+		t.Fatal("should have loaded goal priorities")
+	}
+	if lib.Goals["rich"].AgentGoal().Priority() != 0.5 {
+		t.Fatal("agent goal should have the goal's priority")
+	}
+	if _, ok := lib.Goal("rich").([]any); !ok {
+		t.Fatal("temporal goal should load as an array")
+	}
+}
+
+func TestGOAPLibraryLoadYAML(t *testing.T) {
+	// JSON is YAML, and a YAML decoder gives ints for its integers
+	contents, err := os.ReadFile("test_data/woodcutter_goap.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data map[string]any
+	if err := yaml.Unmarshal(contents, &data); err != nil {
+		t.Fatal(err)
+	}
+	lib, err := LoadGOAPLibrary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lib.actions) != 3 || lib.Goals["rich"].Priority != 0.5 {
+		t.Fatal("should have loaded the library")
+	}
+	w, e, _ := testingGOAPLibraryWorld(t)
+	p := NewGOAPPlanner(e, w)
+	if err := p.AddLibrary(lib); err != nil {
+		t.Fatal(err)
+	}
+	plan, ok := p.Plan(NewGOAPWorldState(nil), lib.Goal("wood"), 500)
+	if !ok || plan.path[0].Name != "buyAxe" {
+		t.Fatalf("should plan as with the JSON-decoded library, got %v", plan)
+	}
+}
+
+func TestGOAPLibraryPlan(t *testing.T) {
+	_, e, p := testingGOAPLibraryWorld(t)
+	lib, _ := LoadGOAPLibraryFile("test_data/woodcutter_goap.json")
+
+	// buying the axe is cheaper than walking to it...
+	plan, ok := p.Plan(NewGOAPWorldState(nil), lib.Goal("wood"), 500)
+	if !ok {
+		t.Fatal("should have found a plan")
+	}
+	if plan.path[0].Name != "buyAxe" {
+		t.Fatalf("should buy the axe, got %s", plan)
+	}
+	// ...unless the entity is stingy (cost expressions read its mind)
+	e.Mind.Set("stingy", 1)
+	plan, ok = p.Plan(NewGOAPWorldState(nil), lib.Goal("wood"), 500)
+	if !ok {
+		t.Fatal("should have found a plan")
+	}
+	if plan.path[0].Name != "getAxe" {
+		t.Fatalf("stingy entity should fetch the axe, got %s", plan)
+	}
+}
+
+func TestGOAPLibraryModalOps(t *testing.T) {
+	_, _, p := testingGOAPLibraryWorld(t)
+	p.AddActions(
+		NewGOAPAction(map[string]any{
+			"name": "invest",
+			"node": "self",
+			"cost": 1,
+			"effs": map[string]int{"coins,*": 3},
+		}),
+		NewGOAPAction(map[string]any{
+			"name": "gamble",
+			"node": "self",
+			"cost": 1,
+			"effs": map[string]int{"coins,min": 1},
+		}),
+	)
+
+	// the entity has 5 coins
+	plan, ok := p.Plan(NewGOAPWorldState(nil), map[string]int{"coins,>=": 12}, 500)
+	if !ok || len(plan.path) != 1 || plan.path[0].Name != "invest" {
+		t.Fatalf("should have planned to multiply the coins, got %v", plan)
+	}
+	if coins := plan.statesAlong[1].vals["coins"]; coins != 15 {
+		t.Fatalf("coins should be 15 after investing, got %g", coins)
+	}
+	plan, ok = p.Plan(NewGOAPWorldState(nil), map[string]int{"coins,<=": 1}, 500)
+	if !ok || len(plan.path) != 1 || plan.path[0].Name != "gamble" {
+		t.Fatalf("should have planned to bound the coins, got %v", plan)
+	}
+	if coins := plan.statesAlong[1].vals["coins"]; coins != 1 {
+		t.Fatalf("coins should be 1 after gambling, got %g", coins)
+	}
+
+	// modal vals are ints
+	lib, err := LoadGOAPLibraryJSON([]byte(`{
+		"actions": [{"name": "halve", "node": "self", "cost": 1, "effs": {"coins,*": 0.5}}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddLibrary(lib); err == nil || !strings.Contains(err.Error(), "whole number") {
+		t.Fatalf("should reject multiplying a modal val by 0.5, got %v", err)
+	}
+}
+
+func TestGOAPLibraryValidation(t *testing.T) {
+	_, err := LoadGOAPLibraryJSON([]byte(`{
+		"actions": [
+			{"name": "a", "node": "self", "cost": 1.5, "effs": {"x,^": 1}, "claims": {"wood": 0.5}},
+			{"name": "b", "cost": "2 + mind.", "pres": {"y": 1}, "effs": {"x,+": 1}, "colour": "red"},
+			{"name": "a", "node": "self", "cost": 1, "effs": {"x,+": 1}}
+		],
+		"modalVals": ["self.sing(loudly)", {"component": "HUNGER"}],
+		"goals": {"g": {"x,=>": 1}},
+		"extra": 1
+	}`))
+	var dataErr *GOAPDataError
+	if !errors.As(err, &dataErr) {
+		t.Fatalf("should have failed validation, got %v", err)
+	}
+	for _, expected := range []string{
+		"extra: unknown field",
+		"actions[0](a).cost: should be an integer",
+		"actions[0](a).effs: \"x,^\" has invalid op",
+		"actions[0](a).claims.wood: should be an integer",
+		"actions[1](b).node: should be a non-empty string",
+		"actions[1](b).cost: bad identifier mind.",
+		"actions[1](b).pres: \"y\" should be \"varName,op\"",
+		"actions[1]: unknown field colour",
+		"actions[2]: duplicate action name a",
+		"modalVals[0]: unknown method sing",
+		"modalVals[1].name: should be a non-empty string",
+		"goals.g: \"x,=>\" has invalid op",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("errors should include %q:\n%s", expected, err)
+		}
+	}
+
+	// components are checked when added to a planner
+	lib, err := LoadGOAPLibraryJSON([]byte(`{
+		"modalVals": [{"name": "hunger", "component": "HUNGER"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := testingWorld()
+	e := w.Spawn(map[string]any{"components": map[ComponentID]any{POSITION_: Vec2D{0, 0}}})
+	if err := NewGOAPPlanner(e, w).AddLibrary(lib); err == nil ||
+		!strings.Contains(err.Error(), "unknown component HUNGER") {
+		t.Fatalf("should fail for unknown component, got %v", err)
+	}
+}
+
+func TestGOAPCostExpr(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(map[string]any{})
+	e.Mind.Set("fatigue", 2)
+	w.Blackboards["village"] = NewBlackboard("village")
+	w.Blackboards["village"].Set("danger", 0.5)
+	for src, expected := range map[string]int{
+		"3":                           3,
+		"1 + 2 * 3":                   7,
+		"(1 + 2) * 3":                 9,
+		"10 / 4":                      3,
+		"5 - -2":                      7,
+		"1 - 10":                      0,
+		"1 + 2 * mind.fatigue":        5,
+		"10 * bb.village.danger":      5,
+		"mind.unset + bb.nope.danger": 0,
+	} {
+		expr, err := parseGOAPCostExpr(src)
+		if err != nil {
+			t.Fatalf("%s: %s", src, err)
+		}
+		if cost := expr.cost(e, w); cost != expected {
+			t.Fatalf("%s should be %d, got %d", src, expected, cost)
+		}
+	}
+	for _, src := range []string{"", "1 +", "(1", "1 2", "fatigue", "1 $ 2"} {
+		if _, err := parseGOAPCostExpr(src); err == nil {
+			t.Fatalf("%q should fail to parse", src)
+		}
+	}
+}
@@ -6,13 +6,14 @@ import (
 )
 
 type GOAPGoal struct {
-	spec map[string]int
+	spec map[string]float64
 	vars map[string]*NumericInterval
 }
 
-func newGOAPGoal(spec map[string]int) *GOAPGoal {
+// spec is a map of "varName,op" to int, float64 or bool
+func newGOAPGoal(spec any) *GOAPGoal {
 	g := &GOAPGoal{
-		spec: spec,
+		spec: goapSpecVals(spec),
 		vars: make(map[string]*NumericInterval),
 	}
 	return g.Parametrized(1)
@@ -24,12 +25,12 @@ func (g *GOAPGoal) Parametrized(n int) *GOAPGoal {
 		vars: make(map[string]*NumericInterval),
 	}
 	for spec, val := range g.spec {
-		logGOAPDebug("        parametrizing %s:%d by %d", spec, val, n)
+		logGOAPDebug("        parametrizing %s:%g by %d", spec, val, n)
 		var split []string
 		macroSplit := strings.Split(spec, ":")
 		// if there is a macro ("EACH")
 		if macroSplit[0] == "EACH" {
-			val *= n
+			val *= float64(n)
 			split = strings.Split(macroSplit[1], ",")
 		} else {
 			split = strings.Split(spec, ",")
@@ -54,7 +55,7 @@ func (g *GOAPGoal) remaining(ws *GOAPWorldState) (result *GOAPGoalRemaining) {
 	}
 	for varName, interval := range g.vars {
 		if stateVal, ok := ws.vals[varName]; ok {
-			diff := interval.Diff(float64(stateVal))
+			diff := interval.Diff(stateVal)
 			logGOAPDebug("                diff for %s: %.0f", varName, diff)
 			result.diffs[varName] = diff
 			if diff != 0 {
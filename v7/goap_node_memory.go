@@ -0,0 +1,137 @@
+package sameriver
+
+import (
+	"math"
+)
+
+// GOAPNodeMemory remembers node entities an agent failed to interact with,
+// so that node selection avoids them for a while and the agent tries
+// another comparable entity instead of replanning to the same one forever
+// (eg. the closest ox needs leading past bandits, and the plan keeps
+// breaking as the agent flees; a farther ox may be fine).
+//
+// A memory decays over Cooldown_ms. Each further failure of the same entity
+// while it's remembered lengthens its cooldown by another Cooldown_ms, so
+// that nodes which keep failing are avoided for longer. While remembered,
+// an entity is excluded from selection, or if Penalty > 0, just treated as
+// being up to Penalty farther away (scaled by how fresh the memory is).
+//
+// Since we can't know in general when a failed node becomes fine again
+// (maybe the bandits were killed), memories can also be cleared on world
+// events with ClearOn().
+type GOAPNodeMemory struct {
+	Cooldown_ms float64
+	Penalty     float64
+
+	failures map[*Entity]*goapNodeFailure
+	// event subscriptions which clear the memory
+	clearOn []*EventChannel
+}
+
+type goapNodeFailure struct {
+	// the node the entity was bound to when it last failed
+	node         string
+	count        int
+	cooldown_ms  float64
+	remaining_ms float64
+}
+
+func NewGOAPNodeMemory(w *World, cooldown_ms float64) *GOAPNodeMemory {
+	m := &GOAPNodeMemory{
+		Cooldown_ms: cooldown_ms,
+		failures:    make(map[*Entity]*goapNodeFailure),
+		clearOn:     make([]*EventChannel, 0),
+	}
+	// entities are reused after despawn, so forget them
+	w.AddDespawnCallback(m.Forget)
+	return m
+}
+
+// Fail records that interacting with e, bound to node, failed
+func (m *GOAPNodeMemory) Fail(e *Entity, node string) {
+	f, ok := m.failures[e]
+	if !ok {
+		f = &goapNodeFailure{}
+		m.failures[e] = f
+	}
+	f.node = node
+	f.count++
+	f.cooldown_ms = float64(f.count) * m.Cooldown_ms
+	f.remaining_ms = f.cooldown_ms
+	logGOAPDebug("remembering failure #%d of entity %d as node %s for %.0f ms",
+		f.count, e.ID, node, f.cooldown_ms)
+}
+
+// Forget clears the memory of e
+func (m *GOAPNodeMemory) Forget(e *Entity) {
+	delete(m.failures, e)
+}
+
+// Clear forgets all failures
+func (m *GOAPNodeMemory) Clear() {
+	m.failures = make(map[*Entity]*goapNodeFailure)
+}
+
+// ClearOn clears the memory whenever an event matching filter is published
+// (checked on Update())
+func (m *GOAPNodeMemory) ClearOn(w *World, filter *EventFilter) {
+	m.clearOn = append(m.clearOn, w.Events.Subscribe(filter))
+}
+
+// Strength returns how fresh the memory of e failing is, from 1 (just
+// failed) down to 0 (not remembered)
+func (m *GOAPNodeMemory) Strength(e *Entity) float64 {
+	f, ok := m.failures[e]
+	if !ok {
+		return 0
+	}
+	return f.remaining_ms / f.cooldown_ms
+}
+
+// Failures returns how many times e has failed while remembered
+func (m *GOAPNodeMemory) Failures(e *Entity) int {
+	if f, ok := m.failures[e]; ok {
+		return f.count
+	}
+	return 0
+}
+
+// Update decays the memories and checks for events to clear on
+func (m *GOAPNodeMemory) Update(dt_ms float64) {
+	for _, c := range m.clearOn {
+		cleared := false
+		for len(c.C) > 0 {
+			<-c.C
+			cleared = true
+		}
+		if cleared {
+			m.Clear()
+		}
+	}
+	for e, f := range m.failures {
+		f.remaining_ms -= dt_ms
+		if f.remaining_ms <= 0 {
+			delete(m.failures, e)
+		}
+	}
+}
+
+// the entity passing filter closest to pos (plus any penalty), skipping
+// excluded entities; nil if there are none
+func (m *GOAPNodeMemory) closest(w *World, pos, box Vec2D, filter func(*Entity) bool) *Entity {
+	closest := (*Entity)(nil)
+	closestDistance := math.MaxFloat64
+	for _, e := range w.FilterAllEntities(filter) {
+		strength := m.Strength(e)
+		if strength > 0 && m.Penalty <= 0 {
+			continue
+		}
+		distance := RectDistance(pos, box, *w.GetVec2D(e, POSITION_), *w.GetVec2D(e, BOX_))
+		distance += strength * m.Penalty
+		if distance < closestDistance {
+			closestDistance = distance
+			closest = e
+		}
+	}
+	return closest
+}
@@ -0,0 +1,127 @@
+package sameriver
+
+import (
+	"testing"
+)
+
+// a woodcutter with node memory, for whom the nearer axe can't be picked up
+func testingNodeMemoryWoodcutter(w *World) (wc *testingWoodcutter, farAxe *Entity) {
+	wc = testingSpawnWoodcutter(w, false)
+	farAxe = w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{-30, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"tags": []string{"axe"},
+	})
+	wc.a.SetNodeMemory(NewGOAPNodeMemory(w, 1000))
+	wc.a.BindAction("getAxe", GOAPTimedAction(100, func(ctx *GOAPActionContext) bool {
+		if ctx.Node == wc.axe {
+			return false
+		}
+		w.GetIntMap(ctx.Entity, STATE_).Set("hasAxe", 1)
+		return true
+	}))
+	return wc, farAxe
+}
+
+func TestGOAPNodeMemoryAvoidsFailedNode(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc, farAxe := testingNodeMemoryWoodcutter(w)
+	m := wc.a.NodeMemory()
+	wc.run(FRAME_MS)
+	if wc.a.nodes["axe"] != wc.axe {
+		t.Fatal("should go for the nearer axe first")
+	}
+	wc.run(200)
+	if m.Failures(wc.axe) != 1 || m.Strength(wc.axe) <= 0 {
+		t.Fatal("should remember the nearer axe failing")
+	}
+	if wc.plans != 2 || wc.a.nodes["axe"] != farAxe {
+		t.Fatal("should replan for the farther axe")
+	}
+	wc.run(1000)
+	if wc.chopped != 2 {
+		t.Fatalf("should have chopped 2 wood with the farther axe, chopped %d", wc.chopped)
+	}
+	if m.Strength(wc.axe) != 0 {
+		t.Fatal("memory should decay")
+	}
+}
+
+func TestGOAPNodeMemoryCooldownGrows(t *testing.T) {
+	w := testingWorld()
+	e := w.Spawn(map[string]any{})
+	m := NewGOAPNodeMemory(w, 100)
+	m.Fail(e, "ox")
+	m.Update(50)
+	if m.Strength(e) != 0.5 {
+		t.Fatalf("strength should decay linearly, got %f", m.Strength(e))
+	}
+	m.Fail(e, "ox")
+	m.Update(100)
+	if m.Failures(e) != 2 || m.Strength(e) != 0.5 {
+		t.Fatalf("second failure should be remembered twice as long, got %f", m.Strength(e))
+	}
+	m.Update(100)
+	if m.Failures(e) != 0 {
+		t.Fatal("should have forgotten")
+	}
+	m.Fail(e, "ox")
+	w.Despawn(e)
+	w.Update(FRAME_MS / 2)
+	if m.Failures(e) != 0 {
+		t.Fatal("should forget despawned entities")
+	}
+}
+
+func TestGOAPNodeMemoryPenalty(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc, farAxe := testingNodeMemoryWoodcutter(w)
+	m := wc.a.NodeMemory()
+	m.Penalty = 10
+	m.Fail(wc.axe, "axe")
+	wc.run(FRAME_MS)
+	if wc.a.nodes["axe"] != wc.axe {
+		t.Fatal("small penalty should not outweigh the nearer axe")
+	}
+	m.Penalty = 100
+	wc.a.InterruptFailed()
+	wc.run(FRAME_MS)
+	if m.Failures(wc.axe) != 2 || wc.a.nodes["axe"] != farAxe {
+		t.Fatal("large penalty should outweigh the nearer axe")
+	}
+}
+
+func TestGOAPNodeMemoryBoundSelectorFallback(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc, farAxe := testingNodeMemoryWoodcutter(w)
+	wc.p.BindEntitySelectors(map[string]any{
+		"axe": func(e *Entity) bool { return e == wc.axe },
+	})
+	wc.run(FRAME_MS)
+	if wc.a.nodes["axe"] != wc.axe {
+		t.Fatal("should use the bound selector")
+	}
+	wc.run(200)
+	if wc.a.nodes["axe"] != farAxe {
+		t.Fatal("should fall back to the generic selector when the bound entity is excluded")
+	}
+}
+
+func TestGOAPNodeMemoryClearOn(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc, _ := testingNodeMemoryWoodcutter(w)
+	m := wc.a.NodeMemory()
+	m.ClearOn(w, SimpleEventFilter("bandits-defeated"))
+	m.Fail(wc.axe, "axe")
+	m.Update(0)
+	if m.Failures(wc.axe) != 1 {
+		t.Fatal("should remember until the event")
+	}
+	w.Events.Publish("bandits-defeated", nil)
+	m.Update(0)
+	if m.Failures(wc.axe) != 0 {
+		t.Fatal("should clear on the event")
+	}
+}
@@ -0,0 +1,226 @@
+package sameriver
+
+import (
+	"container/heap"
+	"fmt"
+	"time"
+
+	"github.com/TwiN/go-color"
+)
+
+// GOAPPlanJob is a planning search which can be run a slice at a time with
+// Step(), so that many entities can plan without stalling a frame. The
+// best solution found so far is available from Best() at any time.
+//
+// The job keeps its own selector result cache, and the selectors bound on
+// the planner when BeginPlan() was called, so other plans can be made with
+// the same planner while it's in progress.
+type GOAPPlanJob struct {
+	p *GOAPPlanner
+
+	start   *GOAPWorldState
+	goal    *GOAPTemporalGoal
+	maxIter int
+	iter    int
+
+	// used for the search
+	pq *GOAPPriorityQueue
+	// the solutions found, cheapest first
+	resultPq *GOAPPriorityQueue
+	// used to keep track of which paths we've already seen since there's
+	// multiple ways to reach a path in the insertion-based logic we use
+	pathsSeen map[string]bool
+
+	selectorResultCache map[string]*Entity
+	boundSelectors      map[string]func(*Entity) bool
+
+	// nil unless the planner is tracing
+	trace *GOAPTrace
+
+	// total time spent in Step()
+	elapsed_ms float64
+	done       bool
+}
+
+// BeginPlan starts a search for a plan from start to goalSpec, to be run
+// with Step()
+func (p *GOAPPlanner) BeginPlan(
+	start *GOAPWorldState,
+	goalSpec any,
+	maxIter int) *GOAPPlanJob {
+
+	j := &GOAPPlanJob{
+		p:                   p,
+		maxIter:             maxIter,
+		pq:                  &GOAPPriorityQueue{},
+		resultPq:            &GOAPPriorityQueue{},
+		pathsSeen:           make(map[string]bool),
+		selectorResultCache: make(map[string]*Entity),
+		boundSelectors:      p.boundSelectors,
+	}
+	defer j.swapIn()()
+
+	// we may be writing to this with modal vals as we explore and don't want
+	// to pollute the caller's state object
+	start = start.CopyOf()
+	start.w = p.w
+	p.setPositionInStartModalIfNotDefined(start)
+	start.ModalEntities["self"] = p.e
+	j.start = start
+
+	logGOAPDebug("Planning...")
+
+	// convert goal spec into GOAPTemporalGoal
+	j.goal = NewGOAPTemporalGoal(goalSpec)
+
+	// populate start state with any modal vals at start
+	for _, tg := range j.goal.temporalGoals {
+		for varName := range tg.vars {
+			p.setVarInStartIfNotDefined(start, varName)
+		}
+	}
+
+	heap.Init(j.resultPq)
+	heap.Init(j.pq)
+
+	rootPath := NewGOAPPath(nil)
+	p.computeCostAndRemainingsOfPath(rootPath, start, j.goal)
+	rootPath.regionOffsets[0] = make([]int, len(j.goal.temporalGoals))
+	backtrackRoot := &GOAPPQueueItem{
+		path:  rootPath,
+		index: -1, // going to be set by Push()
+	}
+	heap.Push(j.pq, backtrackRoot)
+	if p.tracing {
+		j.trace = newGOAPTrace(p.e, start, j.goal, maxIter)
+		j.trace.add(nil, rootPath, nil, 0, GOAP_TRACE_QUEUED, nil)
+	}
+	return j
+}
+
+// use the job's cache and selectors in the planner until the returned func
+// is called
+func (j *GOAPPlanJob) swapIn() (restore func()) {
+	cache, bound, trace := j.p.selectorResultCache, j.p.boundSelectors, j.p.trace
+	j.p.selectorResultCache, j.p.boundSelectors, j.p.trace = j.selectorResultCache, j.boundSelectors, j.trace
+	return func() {
+		j.p.selectorResultCache, j.p.boundSelectors, j.p.trace = cache, bound, trace
+	}
+}
+
+// Step runs the search for about budget_ms (at least one iteration is run,
+// so it may go over), returning whether the search is done
+func (j *GOAPPlanJob) Step(budget_ms float64) (done bool) {
+	if j.done {
+		return true
+	}
+	defer j.swapIn()()
+
+	p := j.p
+	t0 := time.Now()
+	for first := true; j.iter < j.maxIter && j.pq.Len() > 0; first = false {
+		if !first && float64(time.Since(t0).Nanoseconds())/1.0e6 >= budget_ms {
+			break
+		}
+
+		logGOAPDebug("=== iter ===")
+		here := heap.Pop(j.pq).(*GOAPPQueueItem)
+		j.trace.pop(here.path)
+		if DEBUG_GOAP {
+			logGOAPDebug(color.InRedOverGray("here:"))
+			logGOAPDebug(color.InWhiteOverBlue(color.InBold(GOAPPathToString(here.path))))
+			logGOAPDebug(color.InRedOverGray(fmt.Sprintf("(%d unfulfilled)",
+				here.path.remainings.NUnfulfilled())))
+		}
+
+		if here.path.remainings.NUnfulfilled() == 0 {
+			if err := p.validateForward(here.path, j.start, j.goal); err != nil {
+				logGOAPDebug(">>>>>>> potential solution rejected")
+				j.trace.outcome(here.path, GOAP_TRACE_REJECTED, err)
+				continue
+			}
+			j.trace.outcome(here.path, GOAP_TRACE_SOLUTION, nil)
+
+			if DEBUG_GOAP {
+				logGOAPDebug(color.InGreenOverWhite(color.InBold(fmt.Sprintf("    SOLUTION: %s", GOAPPathToString(here.path)))))
+				logGOAPDebug(color.InGreenOverWhite(color.InBold(fmt.Sprintf("%d solutions found so far", j.resultPq.Len()+1))))
+			}
+			heap.Push(j.resultPq, here)
+		} else {
+			j.trace.outcome(here.path, GOAP_TRACE_EXPANDED, nil)
+			p.traverseFulfillers(j.pq, j.start, here, j.goal, j.pathsSeen)
+			j.iter++
+		}
+	}
+	j.elapsed_ms += float64(time.Since(t0).Nanoseconds()) / 1.0e6
+
+	if j.iter < j.maxIter && j.pq.Len() > 0 {
+		return false
+	}
+	j.done = true
+	if j.trace != nil {
+		best, _ := j.Best()
+		j.trace.finish(j.iter, best)
+		p.lastTrace = j.trace
+	}
+
+	if j.iter >= j.maxIter {
+		logGOAPDebug("Took %f ms to reach max iter (%d)", j.elapsed_ms, j.iter)
+		logGOAPDebug("================================ REACHED MAX ITER")
+	}
+	if j.pq.Len() == 0 && j.resultPq.Len() == 0 {
+		logGOAPDebug("Took %f ms to exhaust pq without solution (%d iterations)", j.elapsed_ms, j.iter)
+		logGOAPDebug("================================ EXHAUSTED PQ WITHOUT SOLUTION")
+	}
+	if j.resultPq.Len() > 0 {
+		logGOAPDebug("Took %f ms to find %d solutions (%d iterations)", j.elapsed_ms, j.resultPq.Len(), j.iter)
+		if j.pq.Len() == 0 {
+			logGOAPDebug("Exhausted pq")
+		}
+	}
+	return true
+}
+
+// Done returns whether the search has finished
+func (j *GOAPPlanJob) Done() bool {
+	return j.done
+}
+
+// Best returns the cheapest solution found so far; once Done(), this is
+// the result of the plan
+func (j *GOAPPlanJob) Best() (solution *GOAPPath, ok bool) {
+	if j.resultPq.Len() == 0 {
+		return nil, false
+	}
+	return (*j.resultPq)[0].path, true
+}
+
+// Trace returns the job's trace so far, or nil if the planner wasn't
+// tracing when it began
+func (j *GOAPPlanJob) Trace() *GOAPTrace {
+	return j.trace
+}
+
+// Iterations returns how many iterations of the search have run
+func (j *GOAPPlanJob) Iterations() int {
+	return j.iter
+}
+
+// AddLogic schedules the job as a world logic which steps it by budget_ms
+// each time it runs, removing itself and calling done with the result
+// when the search finishes
+func (j *GOAPPlanJob) AddLogic(
+	w *World,
+	name string,
+	budget_ms float64,
+	done func(solution *GOAPPath, ok bool)) *LogicUnit {
+
+	return w.AddLogic(name, func(dt_ms float64) {
+		if j.Step(budget_ms) {
+			w.RemoveLogic(name)
+			if done != nil {
+				done(j.Best())
+			}
+		}
+	})
+}
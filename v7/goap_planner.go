@@ -7,7 +7,6 @@ import (
 	"math"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/TwiN/go-color"
 )
@@ -31,15 +30,30 @@ type GOAPPlanner struct {
 	boundSelectorsFlipflop bool
 	// selector result cache
 	selectorResultCache map[string]*Entity
+	// if set, entities that failed as nodes are avoided
+	nodeMemory *GOAPNodeMemory
+	// if set, nodes reserved by other entities are skipped, and plans must
+	// fit in the available resources
+	reservations *GOAPReservations
 
 	//
 	// GOAP tetris pieces to put together :)
 	//
 	modalVals map[string]GOAPModalVal
 	actions   *GOAPActionSet
+	// declared types and domains of vars
+	vars map[string]*GOAPVar
 
 	// map of [varName](map[action]bool), the set of actions for affecting each varName
 	varActions map[string](map[*GOAPAction]bool)
+
+	//
+	// tracing (see GOAPTrace)
+	//
+	tracing   bool
+	lastTrace *GOAPTrace
+	// the trace of the plan job being stepped, if any
+	trace *GOAPTrace
 }
 
 func NewGOAPPlanner(e *Entity, w *World) *GOAPPlanner {
@@ -48,6 +62,7 @@ func NewGOAPPlanner(e *Entity, w *World) *GOAPPlanner {
 		e:                   e,
 		modalVals:           make(map[string]GOAPModalVal),
 		actions:             NewGOAPActionSet(),
+		vars:                make(map[string]*GOAPVar),
 		varActions:          make(map[string](map[*GOAPAction]bool)),
 		selectorResultCache: make(map[string]*Entity),
 	}
@@ -76,6 +91,8 @@ func (p *GOAPPlanner) trySelectNodes(ws *GOAPWorldState, nodes []string) (bindEr
 // a Node is an entity (even objects in the world are entities).
 // the ws arg is READ ONLY for modal pos
 // tries cache, then tries the bound selector, falling back to generic
+// (also when everything the bound selector matches is excluded by the
+// node memory)
 // returns nil if nothing valid
 func (p *GOAPPlanner) selectNode(ws *GOAPWorldState, node string) (ent *Entity) {
 	defer func() {
@@ -83,11 +100,23 @@ func (p *GOAPPlanner) selectNode(ws *GOAPWorldState, node string) (ent *Entity)
 			p.selectorResultCache[node] = ent
 		}
 	}()
+	if node == "self" {
+		return p.e
+	}
 	pos := ws.GetModal(p.e, POSITION_).(*Vec2D)
 	box := p.w.GetVec2D(p.e, BOX_)
 
 	// use a selector to find the node entity (ent)
 	trySelect := func(selector func(*Entity) bool) *Entity {
+		if p.reservations != nil && !p.reservations.SharedNodes[node] {
+			unreserved := selector
+			selector = func(e *Entity) bool {
+				return unreserved(e) && !p.reservations.reservedByOther(e, p.e)
+			}
+		}
+		if p.nodeMemory != nil {
+			return p.nodeMemory.closest(p.w, *pos, *box, selector)
+		}
 		return p.w.ClosestEntityFilter(*pos, *box, selector)
 	}
 	var selector func(*Entity) bool
@@ -100,7 +129,7 @@ func (p *GOAPPlanner) selectNode(ws *GOAPWorldState, node string) (ent *Entity)
 	}
 
 	// fallback to checking if the node is a tag
-	ent = p.w.ClosestEntityFilter(*pos, *box, func(e *Entity) bool {
+	ent = trySelect(func(e *Entity) bool {
 		return p.w.EntityHasTag(e, node)
 	})
 
@@ -160,6 +189,18 @@ func (p *GOAPPlanner) bindEntities(nodes []string, ws *GOAPWorldState, start boo
 	return nil
 }
 
+// SetNodeMemory sets the memory of failed nodes to avoid when selecting
+// (nil to select regardless)
+func (p *GOAPPlanner) SetNodeMemory(m *GOAPNodeMemory) {
+	p.nodeMemory = m
+}
+
+// SetReservations sets the reservations the planner coordinates with other
+// entities' through (nil to plan alone)
+func (p *GOAPPlanner) SetReservations(r *GOAPReservations) {
+	p.reservations = r
+}
+
 func (p *GOAPPlanner) BindEntitySelectors(selectors map[string]any) {
 	p.boundSelectorsFlipflop = true
 	p.boundSelectors = make(map[string]func(*Entity) bool)
@@ -207,6 +248,60 @@ func (p *GOAPPlanner) selectorFromString(s string) func(*Entity) bool {
 // SPECIAL MODAL VAL NOTATIONS
 //
 
+// the int given to a modal val's effModalSet for an eff applied count
+// times: the amount added or subtracted, the factor multiplied by, or the
+// value set, or bounded by for min and max
+func goapModalSetArg(eff *GOAPEff, count int) int {
+	switch eff.op {
+	case "+", "-":
+		return int(math.Round(float64(count) * eff.val))
+	case "*":
+		return int(math.Round(math.Pow(eff.val, float64(count))))
+	default:
+		return int(math.Round(eff.val))
+	}
+}
+
+// apply an op with the arg from goapModalSetArg to a modal val's current
+// value
+func goapModalApply(op string, current, x int) int {
+	switch op {
+	case "+":
+		return current + x
+	case "-":
+		return current - x
+	case "*":
+		return current * x
+	case "min":
+		if x < current {
+			return x
+		}
+		return current
+	case "max":
+		if x > current {
+			return x
+		}
+		return current
+	default:
+		return x
+	}
+}
+
+// modal vals are ints, so a * eff on one needs a whole factor
+func goapCheckModalEff(action *GOAPAction, varName string) error {
+	if eff := action.effs[varName]; eff.op == "*" && (eff.val < 0 || eff.val != math.Trunc(eff.val)) {
+		return fmt.Errorf("action %s has eff %s,* %g but modal vals are ints, so can only be multiplied by a whole number",
+			action.Name, varName, eff.val)
+	}
+	return nil
+}
+
+// whether AddActions() will give an eff on varName a modal setter
+func (p *GOAPPlanner) isModalVarName(varName string) bool {
+	_, ok := p.modalVals[varName]
+	return ok || METHOD_NOTATION_RE.MatchString(varName) || strings.Contains(varName, ".")
+}
+
 func (p *GOAPPlanner) createModalValDotNotation(node, stateKey string) GOAPModalVal {
 	return GOAPModalVal{
 		name:  node + "." + stateKey,
@@ -216,9 +311,7 @@ func (p *GOAPPlanner) createModalValDotNotation(node, stateKey string) GOAPModal
 		},
 		effModalSet: func(ws *GOAPWorldState, op string, x int) {
 			state := ws.GetModal(ws.ModalEntities[node], STATE_).(*IntMap).CopyOf()
-			if op == "=" {
-				state.Set(stateKey, x)
-			}
+			state.Set(stateKey, goapModalApply(op, state.Get(stateKey), x))
 			ws.SetModal(ws.ModalEntities[node], STATE_, &state)
 		},
 	}
@@ -246,22 +339,15 @@ func (p *GOAPPlanner) createModalValInventoryHas(node, archetype string) GOAPMod
 		},
 		effModalSet: func(ws *GOAPWorldState, op string, x int) {
 			inv := ws.GetModal(ws.ModalEntities[node], INVENTORY_).(*Inventory).CopyOf()
-			switch op {
-			case "-":
-				inv.DebitNName(x, archetype)
-			case "=":
-				if x == 0 {
-					inv.DebitAllName(archetype)
-				} else {
-					inv.SetCountName(x, archetype)
-				}
-			case "+":
-				count := inv.CountName(archetype)
-				if count == 0 {
-					inv.Credit(items.CreateStackSimple(x, archetype))
-				} else {
-					inv.SetCountName(count+x, archetype)
-				}
+			count := inv.CountName(archetype)
+			switch newCount := goapModalApply(op, count, x); {
+			case newCount == count:
+			case newCount <= 0:
+				inv.DebitAllName(archetype)
+			case count == 0:
+				inv.Credit(items.CreateStackSimple(newCount, archetype))
+			default:
+				inv.SetCountName(newCount, archetype)
 			}
 			ws.SetModal(ws.ModalEntities[node], INVENTORY_, inv)
 		},
@@ -269,36 +355,35 @@ func (p *GOAPPlanner) createModalValInventoryHas(node, archetype string) GOAPMod
 }
 
 func (p *GOAPPlanner) createModalValIn(node, other string) GOAPModalVal {
+	check := func(ws *GOAPWorldState) int {
+		entity := ws.ModalEntities[node]
+		otherEntity := ws.ModalEntities[other]
+		entityPos := ws.GetModal(entity, POSITION_).(*Vec2D)
+		if RectIntersectsRect(
+			*entityPos, *p.w.GetVec2D(entity, BOX_),
+			*p.w.GetVec2D(otherEntity, POSITION_), *p.w.GetVec2D(otherEntity, BOX_)) {
+			return 1
+		} else {
+			return 0
+		}
+	}
 	return GOAPModalVal{
 		name:  fmt.Sprintf("%s.in(%s)", node, other),
 		nodes: []string{node, other},
-		check: func(ws *GOAPWorldState) int {
-			entity := ws.ModalEntities[node]
-			otherEntity := ws.ModalEntities[other]
-			entityPos := ws.GetModal(entity, POSITION_).(*Vec2D)
-			if RectIntersectsRect(
-				*entityPos, *p.w.GetVec2D(entity, BOX_),
-				*p.w.GetVec2D(otherEntity, POSITION_), *p.w.GetVec2D(otherEntity, BOX_)) {
-				return 1
-			} else {
-				return 0
-			}
-		},
+		check: check,
 		effModalSet: func(ws *GOAPWorldState, op string, x int) {
 			entity := ws.ModalEntities[node]
 			otherEntity := ws.ModalEntities[other]
-			if op == "=" {
-				switch x {
-				case 0:
-					// TODO: this should really be a call to some kind of sophisticated
-					// relocation function that avoids obstacles and makes sure there's a path
-					// to be able to get there via navmesh/grid
-					awayFromOther := p.w.GetVec2D(otherEntity, POSITION_).Add(p.w.GetVec2D(otherEntity, BOX_).Scale(1.1))
-					ws.SetModal(entity, POSITION_, &awayFromOther)
-				case 1:
-					otherCenter := *p.w.GetVec2D(otherEntity, POSITION_)
-					ws.SetModal(entity, POSITION_, &otherCenter)
-				}
+			switch goapModalApply(op, check(ws), x) {
+			case 0:
+				// TODO: this should really be a call to some kind of sophisticated
+				// relocation function that avoids obstacles and makes sure there's a path
+				// to be able to get there via navmesh/grid
+				awayFromOther := p.w.GetVec2D(otherEntity, POSITION_).Add(p.w.GetVec2D(otherEntity, BOX_).Scale(1.1))
+				ws.SetModal(entity, POSITION_, &awayFromOther)
+			case 1:
+				otherCenter := *p.w.GetVec2D(otherEntity, POSITION_)
+				ws.SetModal(entity, POSITION_, &otherCenter)
 			}
 		},
 	}
@@ -326,7 +411,7 @@ func (p *GOAPPlanner) AddModalVals(vals ...GOAPModalVal) {
 
 func (p *GOAPPlanner) checkModalInto(varName string, ws *GOAPWorldState) {
 	if _, ok := p.modalVals[varName]; ok {
-		ws.vals[varName] = p.modalVals[varName].check(ws)
+		ws.vals[varName] = float64(p.modalVals[varName].check(ws))
 	}
 }
 
@@ -344,6 +429,9 @@ func (p *GOAPPlanner) AddActions(actions ...*GOAPAction) {
 		// link up modal setters for effs matching modal varnames
 		for varName := range action.effs {
 			p.actionAffectsVar(action, varName)
+			if v, ok := p.vars[varName]; ok {
+				p.checkEffAgainstVar(action, v)
+			}
 			// basic pre-added modal val (simple string, no dots)
 			if modal, ok := p.modalVals[varName]; ok {
 				logGOAPDebug("[][][]     adding modal setter for %s", varName)
@@ -369,6 +457,11 @@ func (p *GOAPPlanner) AddActions(actions ...*GOAPAction) {
 				}
 			}
 		}
+		for varName := range action.effModalSetters {
+			if err := goapCheckModalEff(action, varName); err != nil {
+				panic(err)
+			}
+		}
 		// link up modal checks for pres matching modal varnames
 		for _, tg := range action.pres.temporalGoals {
 			for varName := range tg.vars {
@@ -397,12 +490,12 @@ func (p *GOAPPlanner) applyActionBasic(
 		op := action.ops[varName]
 		x := ws.vals[varName]
 		if DEBUG_GOAP {
-			logGOAPDebug("     %s       %d x %s%s%d(%d) ; = %d",
+			logGOAPDebug("     %s       %d x %s%s%g(%g) ; = %g",
 				color.InWhiteOverYellow(">>>"),
 				action.Count, varName, op, eff.val, x,
-				eff.f(action.Count, x))
+				p.applyEff(varName, eff, action.Count, x))
 		}
-		ws.vals[varName] = eff.f(action.Count, x)
+		ws.vals[varName] = p.applyEff(varName, eff, action.Count, x)
 	}
 	if DEBUG_GOAP {
 		logGOAPDebug(color.InBlueOverWhite(fmt.Sprintf("            ws after action: %v", ws.vals)))
@@ -439,13 +532,13 @@ func (p *GOAPPlanner) applyActionModal(a *GOAPAction, ws *GOAPWorldState) (newWS
 	for varName, eff := range a.effs {
 		op := a.ops[varName]
 		x := ws.vals[varName]
-		logGOAPDebug("    %s        applying %s::%d x %s%s%d(%d) ; = %d",
+		logGOAPDebug("    %s        applying %s::%d x %s%s%g(%g) ; = %g",
 			color.InPurpleOverWhite(" >>>modal "),
 			a.DisplayName(), a.Count, varName, op, eff.val, x,
-			eff.f(a.Count, x))
-		// do modal set
+			p.applyEff(varName, eff, a.Count, x))
+		// do modal set (modal vals are ints)
 		if setter, ok := a.effModalSetters[varName]; ok {
-			setter(newWS, op, a.Count*eff.val)
+			setter(newWS, op, goapModalSetArg(eff, a.Count))
 		}
 	}
 
@@ -454,7 +547,7 @@ func (p *GOAPPlanner) applyActionModal(a *GOAPAction, ws *GOAPWorldState) (newWS
 		if modalVal, ok := p.modalVals[varName]; ok {
 			logGOAPDebug("              re-checking modal val %s", varName)
 			supposedToBe := newWS.vals[varName]
-			newWS.vals[varName] = modalVal.check(newWS)
+			newWS.vals[varName] = float64(modalVal.check(newWS))
 			if newWS.vals[varName] != supposedToBe {
 				err := fmt.Errorf("%w for %s", ErrGOAPModalVsSymbolicValueConflict, varName)
 				logGOAPDebug(color.InPurpleOverWhite(fmt.Sprintf("%s", err)))
@@ -505,6 +598,13 @@ func (p *GOAPPlanner) computeCostAndRemainingsOfPath(path *GOAPPath, start *GOAP
 		if modalErr != nil {
 			return modalErr
 		}
+		// an action whose pres are fulfilled taking a var out of its domain
+		// can't be fixed by inserting anything before it, so prune
+		if surface.nUnfulfilledAtIx(i) == 0 {
+			if domainErr := p.effsOutOfDomain(a, ws); domainErr != nil {
+				return domainErr
+			}
+		}
 		totalCost += cost
 		path.statesAlong[i+1] = ws
 	}
@@ -526,7 +626,7 @@ func (p *GOAPPlanner) presFulfilled(a *GOAPAction, ws *GOAPWorldState) bool {
 	logGOAPDebug("Checking presFulfilled")
 	modifiedWS := ws.CopyOf()
 	for varName, checkF := range a.preModalChecks {
-		modifiedWS.vals[varName] = checkF(ws)
+		modifiedWS.vals[varName] = float64(checkF(ws))
 	}
 	goalLeftCount := 0
 	for _, tg := range a.pres.temporalGoals {
@@ -535,19 +635,23 @@ func (p *GOAPPlanner) presFulfilled(a *GOAPAction, ws *GOAPWorldState) bool {
 	return goalLeftCount == 0
 }
 
-// validate a completed path forward
-func (p *GOAPPlanner) validateForward(path *GOAPPath, start *GOAPWorldState, main *GOAPTemporalGoal) bool {
+// validate a completed path forward, returning why it's invalid if so
+func (p *GOAPPlanner) validateForward(path *GOAPPath, start *GOAPWorldState, main *GOAPTemporalGoal) error {
 
 	ws := start.CopyOf()
 	for _, a := range path.path {
 		if len(a.pres.temporalGoals) > 0 && !p.presFulfilled(a, ws) {
 			logGOAPDebug(">>>>>>> in validateForward, %s was not fulfilled", a.DisplayName())
-			return false
+			return fmt.Errorf("pres of %s were not fulfilled", a.DisplayName())
 		}
 		// we don't check bindEntities() returned bindErr here cause it will never happen;
 		// we evaluate the full path modally already to compute its remainings and distance cost
 		p.bindEntities(append(a.otherNodes, a.Node), ws, false)
 		ws, _, _ = p.applyActionModal(a, ws)
+		if domainErr := p.effsOutOfDomain(a, ws); domainErr != nil {
+			logGOAPDebug(">>>>>>> in validateForward, %s", domainErr)
+			return domainErr
+		}
 	}
 	endRemainingCount := 0
 	for _, tg := range main.temporalGoals {
@@ -555,9 +659,17 @@ func (p *GOAPPlanner) validateForward(path *GOAPPath, start *GOAPWorldState, mai
 	}
 	if endRemainingCount != 0 {
 		logGOAPDebug(">>>>>>> in validateForward, main goal was not fulfilled at end of path")
-		return false
+		return errors.New("main goal was not fulfilled at end of path")
 	}
-	return true
+	if p.reservations != nil {
+		for resource, n := range p.reservations.pathClaims(path) {
+			if available := p.reservations.Available(resource, p.e); n > available {
+				logGOAPDebug(">>>>>>> in validateForward, path claims %d %s but %d available", n, resource, available)
+				return fmt.Errorf("path claims %d %s but %d available", n, resource, available)
+			}
+		}
+	}
+	return nil
 }
 
 func (p *GOAPPlanner) actionHelpsToInsert(
@@ -582,11 +694,11 @@ func (p *GOAPPlanner) actionHelpsToInsert(
 				logGOAPDebug("      [ ] eff affects var: %s; is it satisfactory/closer?", effVarName)
 				// all other cases
 				stateAtPoint := path.statesAlong[insertionIx].vals[varName]
-				needToBeat := interval.Diff(float64(stateAtPoint))
-				actionDiff := interval.Diff(float64(eff.f(action.Count, stateAtPoint)))
+				needToBeat := interval.Diff(stateAtPoint)
+				actionDiff := interval.Diff(p.applyEff(varName, eff, action.Count, stateAtPoint))
 				if DEBUG_GOAP {
 					logGOAPDebug(path.String())
-					logGOAPDebug("            ws[%s] = %d (before)", varName, stateAtPoint)
+					logGOAPDebug("            ws[%s] = %g (before)", varName, stateAtPoint)
 					logGOAPDebug("              needToBeat diff: %d", int(needToBeat))
 					logGOAPDebug("              actionDiff: %d", int(actionDiff))
 				}
@@ -607,8 +719,10 @@ func (p *GOAPPlanner) actionHelpsToInsert(
 							diffMagnitude = -needToBeat
 						} else if eff.op == "+" {
 							diffMagnitude = needToBeat
+						} else if eff.op == "*" {
+							return goapMultiplyScale(stateAtPoint, stateAtPoint+needToBeat, eff.val)
 						}
-						scale := int(math.Ceil(diffMagnitude / float64(eff.val)))
+						scale := int(math.Ceil(diffMagnitude / eff.val))
 						return scale, true
 					}
 				} else {
@@ -637,6 +751,21 @@ func (p *GOAPPlanner) actionHelpsToInsert(
 	return helpsGoal(goalToHelp.goalLeft)
 }
 
+// goapMultiplyScale gives how many times x needs to be multiplied by val to
+// reach target, or false if it never will: from 0 (the value of an unset
+// var), across 0, or by a val of 1 or <= 0
+func goapMultiplyScale(x float64, target float64, val float64) (scale int, ok bool) {
+	if x <= 0 || target <= 0 || val <= 0 || val == 1 {
+		logGOAPDebug("      [_] can't reach %g from %g by * %g", target, x, val)
+		return -1, false
+	}
+	n := math.Ceil(math.Log(target/x) / math.Log(val))
+	if math.IsInf(n, 0) || math.IsNaN(n) || n < 1 {
+		return -1, false
+	}
+	return int(n), true
+}
+
 func (p *GOAPPlanner) setPositionInStartModalIfNotDefined(start *GOAPWorldState) {
 	start.SetModal(p.e, POSITION_, p.w.GetVec2D(p.e, POSITION_))
 }
@@ -650,7 +779,7 @@ func (p *GOAPPlanner) setVarInStartIfNotDefined(start *GOAPWorldState, varName s
 				return bindErr
 			}
 			p.checkModalInto(varName, start)
-			logGOAPDebug(color.InPurple(fmt.Sprintf("[ ] start.vals[\"%s\"] = %d", varName, start.vals[varName])))
+			logGOAPDebug(color.InPurple(fmt.Sprintf("[ ] start.vals[\"%s\"] = %g", varName, start.vals[varName])))
 		} else {
 			// NOTE: vars that don't have modal check default to 0
 			logGOAPDebug(color.InYellow(fmt.Sprintf("[ ] %s not defined in GOAP start state, and no modal check exists. Defaulting to 0.", varName)))
@@ -801,12 +930,15 @@ func (p *GOAPPlanner) traverseFulfillers(
 						}
 						if bindErrInPres != nil {
 							logGOAPDebug(color.InBold(color.InWhiteOverCyan(fmt.Sprintf("in pre of action %s, modal varName %s's modal resolution encountered bind failure: %s", toInsert.DisplayName(), bindErrVar, bindErrInPres))))
+							p.trace.add(here.path, newPath, toInsert, insertionIx, GOAP_TRACE_PRUNED,
+								fmt.Errorf("binding %s: %w", bindErrVar, bindErrInPres))
 							continue
 						}
 						// compute remainings of path from start to end goal
 						computeErr := p.computeCostAndRemainingsOfPath(newPath, start, goal)
 						if computeErr != nil {
 							logGOAPDebug(color.InBold(color.InWhiteOverCyan(fmt.Sprintf("err for action %s: %s", toInsert.DisplayName(), computeErr))))
+							p.trace.add(here.path, newPath, toInsert, insertionIx, GOAP_TRACE_PRUNED, computeErr)
 							continue
 						}
 
@@ -819,6 +951,7 @@ func (p *GOAPPlanner) traverseFulfillers(
 						}
 						pathsSeen[pathStr] = true
 						heap.Push(pq, &GOAPPQueueItem{path: newPath})
+						p.trace.add(here.path, newPath, toInsert, insertionIx, GOAP_TRACE_QUEUED, nil)
 					} else {
 						logGOAPDebug("[_] %s not helpful", action.DisplayName())
 					}
@@ -829,6 +962,8 @@ func (p *GOAPPlanner) traverseFulfillers(
 	logGOAPDebug("--------------------------/traverse")
 }
 
+// Plan runs the whole search at once; see BeginPlan() to spread it across
+// frames
 func (p *GOAPPlanner) Plan(
 	start *GOAPWorldState,
 	goalSpec any,
@@ -836,113 +971,8 @@ func (p *GOAPPlanner) Plan(
 
 	defer func() {
 		p.boundSelectorsFlipflop = false
-		// we especially must clear this between Plan() calls
-		p.selectorResultCache = make(map[string]*Entity)
 	}()
-
-	// we may be writing to this with modal vals as we explore and don't want
-	// to pollute the caller's state object
-	start = start.CopyOf()
-	start.w = p.w
-	p.setPositionInStartModalIfNotDefined(start)
-	start.ModalEntities["self"] = p.e
-
-	logGOAPDebug("Planning...")
-
-	// convert goal spec into GOAPTemporalGoal
-	goal := NewGOAPTemporalGoal(goalSpec)
-
-	// populate start state with any modal vals at start
-	for _, tg := range goal.temporalGoals {
-		for varName := range tg.vars {
-			p.setVarInStartIfNotDefined(start, varName)
-		}
-	}
-
-	// used to return the solution with lowest cost among solutions found
-	resultPq := &GOAPPriorityQueue{}
-	heap.Init(resultPq)
-
-	// used to keep track of which paths we've already seen since there's multiple ways to
-	// reach a path in the insertion-based logic we use
-	pathsSeen := make(map[string]bool)
-
-	// used for the search
-	pq := &GOAPPriorityQueue{}
-	heap.Init(pq)
-
-	rootPath := NewGOAPPath(nil)
-	p.computeCostAndRemainingsOfPath(rootPath, start, goal)
-	rootPath.regionOffsets[0] = make([]int, len(goal.temporalGoals))
-	backtrackRoot := &GOAPPQueueItem{
-		path:  rootPath,
-		index: -1, // going to be set by Push()
-	}
-	heap.Push(pq, backtrackRoot)
-
-	iter := 0
-	// TODO: should we just pop out the *very first result*?
-	// why wait for 2 or exhausting the pq?
-	t0 := time.Now()
-	// remove resultPq.Len() < 2 to exhaust space/max iter
-	for iter < maxIter && pq.Len() > 0 {
-
-		logGOAPDebug("=== iter ===")
-		here := heap.Pop(pq).(*GOAPPQueueItem)
-		if DEBUG_GOAP {
-			logGOAPDebug(color.InRedOverGray("here:"))
-			logGOAPDebug(color.InWhiteOverBlue(color.InBold(GOAPPathToString(here.path))))
-			logGOAPDebug(color.InRedOverGray(fmt.Sprintf("(%d unfulfilled)",
-				here.path.remainings.NUnfulfilled())))
-		}
-
-		if here.path.remainings.NUnfulfilled() == 0 {
-			ok := p.validateForward(here.path, start, goal)
-			if !ok {
-				logGOAPDebug(">>>>>>> potential solution rejected")
-				continue
-			}
-
-			if DEBUG_GOAP {
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(">>>>>>>>>>>>>>>>>>>>>>")))
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(">>>>>>>>>>>>>>>>>>>>>>")))
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(">>>>>>>>>>>>>>>>>>>>>>")))
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(fmt.Sprintf("    SOLUTION: %s", GOAPPathToString(here.path)))))
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(">>>>>>>>>>>>>>>>>>>>>>")))
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(">>>>>>>>>>>>>>>>>>>>>>")))
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(">>>>>>>>>>>>>>>>>>>>>>")))
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(GOAPPathToString(here.path))))
-				logGOAPDebug(color.InGreenOverWhite(color.InBold(fmt.Sprintf("%d solutions found so far", resultPq.Len()+1))))
-			}
-			heap.Push(resultPq, here)
-		} else {
-			p.traverseFulfillers(pq, start, here, goal, pathsSeen)
-			iter++
-		}
-	}
-
-	dt := float64(time.Since(t0).Nanoseconds()) / 1.0e6
-	if iter >= maxIter {
-		logGOAPDebug("Took %f ms to reach max iter (%d)", dt, iter)
-		logGOAPDebug("================================ REACHED MAX ITER")
-	}
-	if pq.Len() == 0 && resultPq.Len() == 0 {
-		logGOAPDebug("Took %f ms to exhaust pq without solution (%d iterations)", dt, iter)
-		logGOAPDebug("================================ EXHAUSTED PQ WITHOUT SOLUTION")
-	}
-	if resultPq.Len() > 0 {
-		logGOAPDebug("Took %f ms to find %d solutions (%d iterations)", dt, resultPq.Len(), iter)
-		if pq.Len() == 0 {
-			logGOAPDebug("Exhausted pq")
-		}
-		results := make([]*GOAPPath, 0)
-		for resultPq.Len() > 0 {
-			pop := heap.Pop(resultPq).(*GOAPPQueueItem).path
-			results = append(results, pop)
-			logGOAPDebug("solution (cost %.3f): %s", pop.cost, color.InWhiteOverBlue(GOAPPathToString(pop)))
-		}
-		return results[0], true
-	} else {
-		return nil, false
-	}
+	job := p.BeginPlan(start, goalSpec, maxIter)
+	job.Step(math.Inf(+1))
+	return job.Best()
 }
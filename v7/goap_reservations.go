@@ -0,0 +1,319 @@
+package sameriver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+GOAPReservations coordinates the GOAP agents of a world, so that they don't
+all plan to chop the same tree. It's kept in a world blackboard (so other
+code can see who has claimed what, and it's saved with the world), with keys:
+
+	node.<entity ID>             ID of the entity the node is reserved by
+	stock.<resource>             quantity of the resource there is
+	claim.<resource>.<owner ID>  quantity of the resource claimed by owner
+	goal.<name>                  IDs of the entities sharing the goal
+
+Planners using the reservations (see GOAPPlanner.SetReservations()) don't
+select nodes reserved by other entities, and only find plans whose actions'
+"claims" (eg. "claims": map[string]int{"wood": 3}, per count of the action)
+are available. Agents reserve the nodes bound for their plans (except those
+named in SharedNodes) and claim the plan's resources when they adopt it,
+releasing them when the plan completes, fails or is interrupted, and when
+the agent despawns.
+*/
+type GOAPReservations struct {
+	w  *World
+	bb Blackboard
+	// node names which are never reserved (eg. "well", which any number of
+	// agents can use at once)
+	SharedNodes map[string]bool
+}
+
+func NewGOAPReservations(w *World, name string) *GOAPReservations {
+	r := &GOAPReservations{
+		w:           w,
+		bb:          w.CreateBlackboard(name),
+		SharedNodes: make(map[string]bool),
+	}
+	w.AddDespawnCallback(func(e *Entity) {
+		r.ReleaseAll(e)
+		r.bb.Remove(r.nodeKey(e))
+		for _, name := range r.sharedGoalNames() {
+			r.LeaveGoal(name, e)
+		}
+	})
+	return r
+}
+
+func (r *GOAPReservations) nodeKey(node *Entity) string {
+	return fmt.Sprintf("node.%d", node.ID)
+}
+
+func (r *GOAPReservations) claimKey(resource string, owner *Entity) string {
+	return fmt.Sprintf("claim.%s.%d", resource, owner.ID)
+}
+
+//
+// nodes
+//
+
+// ReserveNode reserves node for owner, returning false if it's reserved by
+// another entity
+func (r *GOAPReservations) ReserveNode(owner, node *Entity) bool {
+	if by := r.NodeReservedBy(node); by != nil && by != owner {
+		return false
+	}
+	r.bb.Set(r.nodeKey(node), owner.ID)
+	return true
+}
+
+// NodeReservedBy returns the entity node is reserved by, or nil
+func (r *GOAPReservations) NodeReservedBy(node *Entity) *Entity {
+	if id, ok := r.bb.Get(r.nodeKey(node)).(int); ok {
+		return r.w.GetEntity(id)
+	}
+	return nil
+}
+
+// ReleaseNode releases node if it's reserved by owner
+func (r *GOAPReservations) ReleaseNode(owner, node *Entity) {
+	if r.NodeReservedBy(node) == owner {
+		r.bb.Remove(r.nodeKey(node))
+	}
+}
+
+func (r *GOAPReservations) reservedByOther(node, e *Entity) bool {
+	by := r.NodeReservedBy(node)
+	return by != nil && by != e
+}
+
+//
+// resources
+//
+
+// SetStock sets the quantity of resource there is to claim
+func (r *GOAPReservations) SetStock(resource string, n int) {
+	r.bb.Set("stock."+resource, n)
+}
+
+func (r *GOAPReservations) Stock(resource string) int {
+	n, _ := r.bb.Get("stock." + resource).(int)
+	return n
+}
+
+// Claimed returns the quantity of resource claimed by owner
+func (r *GOAPReservations) Claimed(resource string, owner *Entity) int {
+	n, _ := r.bb.Get(r.claimKey(resource, owner)).(int)
+	return n
+}
+
+// Available returns the quantity of resource not claimed by entities other
+// than e
+func (r *GOAPReservations) Available(resource string, e *Entity) int {
+	available := r.Stock(resource)
+	prefix := "claim." + resource + "."
+	for k, v := range r.bb.State {
+		if strings.HasPrefix(k, prefix) && k != r.claimKey(resource, e) {
+			available -= v.(int)
+		}
+	}
+	return available
+}
+
+// Claim sets owner's claim on resource to n, returning false (and leaving
+// the claim as it was) if that much isn't available
+func (r *GOAPReservations) Claim(owner *Entity, resource string, n int) bool {
+	if n > r.Available(resource, owner) {
+		return false
+	}
+	if n == 0 {
+		r.bb.Remove(r.claimKey(resource, owner))
+	} else {
+		r.bb.Set(r.claimKey(resource, owner), n)
+	}
+	return true
+}
+
+// Consume takes n of owner's claim on resource out of the stock (as when
+// the resource is actually taken)
+func (r *GOAPReservations) Consume(owner *Entity, resource string, n int) {
+	claimed := r.Claimed(resource, owner)
+	n = int(math.Min(float64(n), float64(claimed)))
+	r.SetStock(resource, r.Stock(resource)-n)
+	r.Claim(owner, resource, claimed-n)
+}
+
+// ReleaseAll releases every node reservation and resource claim of owner
+func (r *GOAPReservations) ReleaseAll(owner *Entity) {
+	owned := strconv.Itoa(owner.ID)
+	for k, v := range r.bb.State {
+		if strings.HasPrefix(k, "node.") && v == owner.ID {
+			r.bb.Remove(k)
+		}
+		if strings.HasPrefix(k, "claim.") && strings.HasSuffix(k, "."+owned) {
+			r.bb.Remove(k)
+		}
+	}
+}
+
+// the total claims of the actions in path
+func (r *GOAPReservations) pathClaims(path *GOAPPath) map[string]int {
+	claims := make(map[string]int)
+	for _, a := range path.path {
+		for resource, n := range a.claims {
+			claims[resource] += a.Count * n
+		}
+	}
+	return claims
+}
+
+// reserve the plan's nodes and claim its resources for owner (releasing
+// whatever it had before), returning false and reserving nothing if that
+// isn't possible
+func (r *GOAPReservations) reservePlan(owner *Entity, path *GOAPPath, nodes map[string]*Entity) bool {
+	claims := r.pathClaims(path)
+	for node, e := range nodes {
+		if e != owner && !r.SharedNodes[node] && r.reservedByOther(e, owner) {
+			return false
+		}
+	}
+	for resource, n := range claims {
+		if n > r.Available(resource, owner) {
+			return false
+		}
+	}
+	r.ReleaseAll(owner)
+	for node, e := range nodes {
+		if e != owner && !r.SharedNodes[node] {
+			r.ReserveNode(owner, e)
+		}
+	}
+	for resource, n := range claims {
+		r.Claim(owner, resource, n)
+	}
+	return true
+}
+
+//
+// shared goals
+//
+
+/*
+ShareGoal creates a goal that agents joining it cooperate on, by splitting
+its additive parts: for each var with a >= op, the participants' goals
+are shares of the value (which add up to it, earlier joiners taking any
+remainder), while other ops are the same for all. So for
+
+	r.ShareGoal("firewood", map[string]int{"woodChopped,>=": 10})
+
+with three agents joined, their goals are woodChopped >= 4, 3 and 3. The
+vars are each agent's own (eg. the wood it has chopped); the goal
+doesn't sum them.
+*/
+func (r *GOAPReservations) ShareGoal(name string, spec map[string]int) *GOAPSharedGoal {
+	if !r.bb.Has("goal." + name) {
+		r.bb.Set("goal."+name, []int{})
+	}
+	return &GOAPSharedGoal{r: r, Name: name, spec: spec}
+}
+
+// GOAPSharedGoal is a goal split between the agents which join it; see
+// GOAPReservations.ShareGoal()
+type GOAPSharedGoal struct {
+	r    *GOAPReservations
+	Name string
+	spec map[string]int
+}
+
+func (r *GOAPReservations) participants(name string) []int {
+	ids, _ := r.bb.Get("goal." + name).([]int)
+	return ids
+}
+
+func (r *GOAPReservations) sharedGoalNames() []string {
+	names := make([]string, 0)
+	for k := range r.bb.State {
+		if strings.HasPrefix(k, "goal.") {
+			names = append(names, strings.TrimPrefix(k, "goal."))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Join adds the agent to the goal's participants, adding the goal to the
+// agent with the given priority (nil for constant 0)
+func (g *GOAPSharedGoal) Join(a *GOAPAgent, priority func() float64) *GOAPAgentGoal {
+	if g.participantIx(a.e) == -1 {
+		ids := g.r.participants(g.Name)
+		g.r.bb.Set("goal."+g.Name, append(append([]int{}, ids...), a.e.ID))
+	}
+	goal := &GOAPAgentGoal{
+		Name:     g.Name,
+		Spec:     func() any { return g.Part(a.e) },
+		Priority: priority,
+	}
+	a.AddGoal(goal)
+	return goal
+}
+
+// LeaveGoal removes e from the participants of the named goal, so the
+// others' shares grow
+func (r *GOAPReservations) LeaveGoal(name string, e *Entity) {
+	ids := r.participants(name)
+	remaining := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id != e.ID {
+			remaining = append(remaining, id)
+		}
+	}
+	if len(remaining) != len(ids) {
+		r.bb.Set("goal."+name, remaining)
+	}
+}
+
+// Participants returns the entities sharing the goal, in the order they
+// joined
+func (g *GOAPSharedGoal) Participants() []*Entity {
+	ids := g.r.participants(g.Name)
+	result := make([]*Entity, len(ids))
+	for i, id := range ids {
+		result[i] = g.r.w.GetEntity(id)
+	}
+	return result
+}
+
+func (g *GOAPSharedGoal) participantIx(e *Entity) int {
+	for i, id := range g.r.participants(g.Name) {
+		if id == e.ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Part returns e's part of the goal as a goal spec (the whole goal if e
+// isn't participating)
+func (g *GOAPSharedGoal) Part(e *Entity) map[string]int {
+	ids := g.r.participants(g.Name)
+	ix := g.participantIx(e)
+	part := make(map[string]int, len(g.spec))
+	for spec, val := range g.spec {
+		if ix == -1 || !strings.HasSuffix(spec, ",>=") {
+			part[spec] = val
+			continue
+		}
+		n := len(ids)
+		share := val / n
+		if ix < val%n {
+			share++
+		}
+		part[spec] = share
+	}
+	return part
+}
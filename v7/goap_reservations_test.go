@@ -0,0 +1,150 @@
+package sameriver
+
+import (
+	"testing"
+)
+
+func TestGOAPReservationsNodes(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	r := NewGOAPReservations(w, "village")
+	// two woodcutters with identical axes and trees at the same places
+	wcs := []*testingWoodcutter{
+		testingSpawnWoodcutter(w, false),
+		testingSpawnWoodcutter(w, false),
+	}
+	for _, wc := range wcs {
+		wc.p.SetReservations(r)
+	}
+	wcs[0].run(FRAME_MS)
+	wcs[1].run(FRAME_MS)
+	for _, node := range []string{"axe", "tree"} {
+		e0, e1 := wcs[0].a.nodes[node], wcs[1].a.nodes[node]
+		if e0 == e1 {
+			t.Fatalf("woodcutters should not plan to use the same %s", node)
+		}
+		if r.NodeReservedBy(e0) != wcs[0].e || r.NodeReservedBy(e1) != wcs[1].e {
+			t.Fatalf("%s should be reserved by the woodcutter using it", node)
+		}
+	}
+	if r.NodeReservedBy(wcs[0].e) != nil {
+		t.Fatal("self should not be reserved")
+	}
+	for i := 0; i < 100; i++ {
+		wcs[0].run(FRAME_MS)
+		wcs[1].run(FRAME_MS)
+	}
+	if wcs[0].chopped != 2 || wcs[1].chopped != 2 {
+		t.Fatal("both should have chopped their wood")
+	}
+	for k := range w.Blackboards["village"].State {
+		t.Fatalf("reservations should be released when plans complete, found %s", k)
+	}
+}
+
+func TestGOAPReservationsReleaseOnFailureAndDespawn(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	r := NewGOAPReservations(w, "village")
+	r.SharedNodes["tree"] = true
+	wc := testingSpawnWoodcutter(w, false)
+	wc.p.SetReservations(r)
+	other := testingSpawnWoodcutter(w, false)
+	other.p.SetReservations(r)
+	wc.a.BindAction("getAxe", &GOAPActionImpl{
+		Update: func(ctx *GOAPActionContext, dt_ms float64) GOAPActionStatus {
+			return GOAP_ACTION_RUNNING
+		},
+	})
+	wc.run(FRAME_MS)
+	axe := wc.a.nodes["axe"]
+	if r.NodeReservedBy(axe) != wc.e || r.NodeReservedBy(wc.a.nodes["tree"]) != nil {
+		t.Fatal("should reserve the axe but not the shared tree")
+	}
+	wc.a.Interrupt()
+	if r.NodeReservedBy(axe) != nil {
+		t.Fatal("should release reservations on interrupt")
+	}
+	wc.run(FRAME_MS)
+	axe = wc.a.nodes["axe"]
+	other.run(FRAME_MS)
+	if other.a.nodes["axe"] == axe {
+		t.Fatal("other should get the axe not reserved")
+	}
+	w.Despawn(wc.e)
+	w.Update(FRAME_MS / 2)
+	if r.NodeReservedBy(axe) != nil {
+		t.Fatal("should release reservations on despawn")
+	}
+}
+
+func TestGOAPReservationsClaims(t *testing.T) {
+	w := testingWorld()
+	r := NewGOAPReservations(w, "village")
+	r.SetStock("wood", 3)
+	spawn := func() (*Entity, *GOAPPlanner) {
+		e := w.Spawn(map[string]any{
+			"components": map[ComponentID]any{
+				POSITION_: Vec2D{0, 0},
+				BOX_:      Vec2D{1, 1},
+			},
+		})
+		p := NewGOAPPlanner(e, w)
+		p.SetReservations(r)
+		p.AddActions(NewGOAPAction(map[string]any{
+			"name":   "withdrawWood",
+			"node":   "self",
+			"cost":   1,
+			"claims": map[string]int{"wood": 1},
+			"effs":   map[string]int{"wood,+": 1},
+		}))
+		return e, p
+	}
+	goal := map[string]int{"wood,>=": 2}
+	e1, p1 := spawn()
+	e2, p2 := spawn()
+	path, ok := p1.Plan(NewGOAPWorldState(nil), goal, 50)
+	if !ok || !r.reservePlan(e1, path, nil) || r.Claimed("wood", e1) != 2 {
+		t.Fatal("first planner should claim 2 wood")
+	}
+	if _, ok := p2.Plan(NewGOAPWorldState(nil), goal, 50); ok {
+		t.Fatal("second planner should not find a plan with only 1 wood available")
+	}
+	if r.Available("wood", e2) != 1 || r.Available("wood", e1) != 3 {
+		t.Fatal("available should exclude others' claims")
+	}
+	r.Consume(e1, "wood", 1)
+	if r.Stock("wood") != 2 || r.Claimed("wood", e1) != 1 {
+		t.Fatal("consuming should take from the stock and the claim")
+	}
+	r.ReleaseAll(e1)
+	if _, ok := p2.Plan(NewGOAPWorldState(nil), goal, 50); !ok {
+		t.Fatal("second planner should find a plan once the claim is released")
+	}
+}
+
+func TestGOAPSharedGoal(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	r := NewGOAPReservations(w, "village")
+	firewood := r.ShareGoal("firewood", map[string]int{"woodChopped,>=": 10, "self.hasAxe,=": 1})
+	wcs := make([]*testingWoodcutter, 3)
+	for i := range wcs {
+		wcs[i] = testingSpawnWoodcutter(w, false)
+		wcs[i].a.goals = nil
+		wcs[i].p.SetReservations(r)
+		firewood.Join(wcs[i].a, nil)
+	}
+	for i, share := range []int{4, 3, 3} {
+		part := firewood.Part(wcs[i].e)
+		if part["woodChopped,>="] != share || part["self.hasAxe,="] != 1 {
+			t.Fatalf("participant %d should have share %d, got %v", i, share, part)
+		}
+	}
+	w.Despawn(wcs[2].e)
+	w.Update(FRAME_MS / 2)
+	if len(firewood.Participants()) != 2 || firewood.Part(wcs[0].e)["woodChopped,>="] != 5 {
+		t.Fatal("despawned participant should leave the goal, growing the others' shares")
+	}
+	wcs[0].run(2000)
+	if wcs[0].chopped != 5 {
+		t.Fatalf("agent should chop its share of 5, chopped %d", wcs[0].chopped)
+	}
+}
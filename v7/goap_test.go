@@ -16,7 +16,7 @@ func printWorldState(ws *GOAPWorldState) {
 		return
 	}
 	for name, val := range ws.vals {
-		Logger.Printf("    %s: %d", name, val)
+		Logger.Printf("    %s: %g", name, val)
 	}
 }
 
@@ -975,3 +975,107 @@ func TestGOAPPlanFarmer2000(t *testing.T) {
 
 	}
 }
+
+func TestGOAPPlanJobStepped(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	// costlier alternatives keep the search going after the first solution
+	wc.p.AddActions(
+		NewGOAPAction(map[string]any{
+			"name": "buyAxe",
+			"node": "self",
+			"cost": 50,
+			"pres": nil,
+			"effs": map[string]int{"self.hasAxe,=": 1},
+		}),
+		NewGOAPAction(map[string]any{
+			"name": "buyWood",
+			"node": "self",
+			"cost": 100,
+			"pres": nil,
+			"effs": map[string]int{"woodChopped,+": 1},
+		}),
+	)
+	goal := map[string]int{"woodChopped,>=": 2}
+
+	expected, ok := wc.p.Plan(NewGOAPWorldState(nil), goal, 500)
+	if !ok {
+		t.Fatal("should have found a plan")
+	}
+
+	job := wc.p.BeginPlan(NewGOAPWorldState(nil), goal, 500)
+	steps := 0
+	foundEarly := false
+	// a zero budget runs one iteration per step
+	for !job.Step(0) {
+		steps++
+		if _, ok := job.Best(); ok {
+			foundEarly = true
+		}
+	}
+	if steps == 0 {
+		t.Fatal("should have taken more than one step with no budget")
+	}
+	if !foundEarly {
+		t.Fatal("should have had a best-so-far solution before finishing")
+	}
+	plan, ok := job.Best()
+	if !ok || plan.String() != expected.String() {
+		t.Fatalf("stepped plan %s should match Plan() %s", plan, expected)
+	}
+	// stepping a done job is a no-op
+	if !job.Step(10) || job.Iterations() > 500 {
+		t.Fatal("job should stay done")
+	}
+}
+
+func TestGOAPPlanJobInterleaved(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	// bind "tree" to a different tree for the first job only
+	other := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{-50, 0},
+			BOX_:      Vec2D{2, 2},
+		},
+	})
+	wc.p.BindEntitySelectors(map[string]any{
+		"tree": func(e *Entity) bool { return e == other },
+	})
+	j1 := wc.p.BeginPlan(NewGOAPWorldState(nil), map[string]int{"woodChopped,>=": 1}, 500)
+	wc.p.BindEntitySelectors(map[string]any{})
+	j2 := wc.p.BeginPlan(NewGOAPWorldState(nil), map[string]int{"woodChopped,>=": 1}, 500)
+	for !(j1.Done() && j2.Done()) {
+		j1.Step(0)
+		j2.Step(0)
+	}
+	p1, ok1 := j1.Best()
+	p2, ok2 := j2.Best()
+	if !ok1 || !ok2 {
+		t.Fatal("both jobs should find plans")
+	}
+	tree1 := p1.statesAlong[len(p1.path)].ModalEntities["tree"]
+	tree2 := p2.statesAlong[len(p2.path)].ModalEntities["tree"]
+	if tree1 != other || tree2 != wc.tree {
+		t.Fatal("each job should keep the selectors bound when it began")
+	}
+}
+
+func TestGOAPPlanJobAsLogic(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	job := wc.p.BeginPlan(NewGOAPWorldState(nil), map[string]int{"woodChopped,>=": 2}, 500)
+	var result *GOAPPath
+	job.AddLogic(w, "plan-wood", 0, func(solution *GOAPPath, ok bool) {
+		result = solution
+	})
+	for i := 0; i < 100 && result == nil; i++ {
+		w.Update(FRAME_MS / 2)
+	}
+	if result == nil || len(result.path) != 2 {
+		t.Fatalf("logic should have finished the plan, got %v", result)
+	}
+	if _, ok := w.worldLogics["plan-wood"]; ok {
+		t.Fatal("logic should remove itself when the plan is done")
+	}
+}
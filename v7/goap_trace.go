@@ -0,0 +1,273 @@
+package sameriver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// outcomes of a node in a GOAPTrace
+const (
+	// pushed to the queue but never popped
+	GOAP_TRACE_QUEUED = "queued"
+	// popped, and actions inserted to fulfill its remainings
+	GOAP_TRACE_EXPANDED = "expanded"
+	// popped with nothing remaining, and valid
+	GOAP_TRACE_SOLUTION = "solution"
+	// popped with nothing remaining, but failed validateForward()
+	GOAP_TRACE_REJECTED = "rejected"
+	// never pushed to the queue (binding failed, or the path was invalid)
+	GOAP_TRACE_PRUNED = "pruned"
+)
+
+// GOAPTrace is a structured record of a planning search, made when tracing
+// is enabled with GOAPPlanner.SetTracing(), for inspecting why a plan was
+// (or wasn't) found. Each path the search considers is a node, whose parent
+// is the path it was made from by inserting an action. Export it with JSON()
+// or DOT(), or render it with cmd/sameriver-goap-trace.
+//
+// Intervals (in Goal and Remaining) are strings like "[2, +Inf]", since JSON
+// has no infinity
+type GOAPTrace struct {
+	Entity     int                 `json:"entity"`
+	Start      map[string]float64  `json:"start"`
+	Goal       []map[string]string `json:"goal"`
+	MaxIter    int                 `json:"maxIter"`
+	Iterations int                 `json:"iterations"`
+	// the ID of the cheapest solution, or -1
+	Best  int              `json:"best"`
+	Nodes []*GOAPTraceNode `json:"nodes"`
+
+	// node ID of each path
+	ids    map[*GOAPPath]int
+	popped int
+}
+
+type GOAPTraceNode struct {
+	ID int `json:"id"`
+	// -1 for the root (the empty path)
+	Parent int      `json:"parent"`
+	Path   []string `json:"path"`
+	// the action inserted into the parent's path to make this one
+	Inserted    string  `json:"inserted,omitempty"`
+	InsertionIx int     `json:"insertionIx"`
+	Cost        float64 `json:"cost"`
+	// as GOAPPath.regionOffsets
+	RegionOffsets [][]int `json:"regionOffsets"`
+	// for the pres of each action in the path then the main goal, the
+	// unfulfilled vars of each temporal region
+	Remaining [][]map[string]string `json:"remaining"`
+	// the order in which it was popped from the queue (-1 if never)
+	Popped  int    `json:"popped"`
+	Outcome string `json:"outcome"`
+	// why the node was pruned or rejected
+	Reason string `json:"reason,omitempty"`
+}
+
+// SetTracing sets whether plans record a GOAPTrace (see LastTrace() and
+// GOAPPlanJob.Trace())
+func (p *GOAPPlanner) SetTracing(tracing bool) {
+	p.tracing = tracing
+}
+
+// LastTrace returns the trace of the last plan to finish while tracing
+func (p *GOAPPlanner) LastTrace() *GOAPTrace {
+	return p.lastTrace
+}
+
+func newGOAPTrace(e *Entity, start *GOAPWorldState, goal *GOAPTemporalGoal, maxIter int) *GOAPTrace {
+	t := &GOAPTrace{
+		Entity:  e.ID,
+		Start:   make(map[string]float64),
+		Goal:    make([]map[string]string, 0, len(goal.temporalGoals)),
+		MaxIter: maxIter,
+		Best:    -1,
+		Nodes:   make([]*GOAPTraceNode, 0),
+		ids:     make(map[*GOAPPath]int),
+	}
+	for k, v := range start.vals {
+		t.Start[k] = v
+	}
+	for _, g := range goal.temporalGoals {
+		t.Goal = append(t.Goal, goapTraceIntervals(g.vars))
+	}
+	return t
+}
+
+func goapTraceIntervals(vars map[string]*NumericInterval) map[string]string {
+	result := make(map[string]string)
+	for varName, interval := range vars {
+		result[varName] = fmt.Sprintf("[%g, %g]", interval.A, interval.B)
+	}
+	return result
+}
+
+// record path as a node made from parent (nil for the root)
+func (t *GOAPTrace) add(
+	parent *GOAPPath, path *GOAPPath, inserted *GOAPAction, insertionIx int,
+	outcome string, reason error) {
+
+	if t == nil {
+		return
+	}
+	n := &GOAPTraceNode{
+		ID:          len(t.Nodes),
+		Parent:      -1,
+		Path:        make([]string, len(path.path)),
+		InsertionIx: insertionIx,
+		Cost:        path.cost,
+		Popped:      -1,
+		Outcome:     outcome,
+	}
+	if parent != nil {
+		n.Parent = t.ids[parent]
+	}
+	if inserted != nil {
+		n.Inserted = inserted.DisplayName()
+	}
+	for i, a := range path.path {
+		n.Path[i] = a.DisplayName()
+	}
+	n.RegionOffsets = make([][]int, len(path.regionOffsets))
+	for i, offsets := range path.regionOffsets {
+		n.RegionOffsets[i] = append([]int{}, offsets...)
+	}
+	if path.remainings != nil {
+		n.Remaining = make([][]map[string]string, len(path.remainings.surface))
+		for i, tgs := range path.remainings.surface {
+			n.Remaining[i] = make([]map[string]string, len(tgs))
+			for j, tg := range tgs {
+				n.Remaining[i][j] = goapTraceIntervals(tg.goalLeft)
+			}
+		}
+	}
+	if reason != nil {
+		n.Reason = reason.Error()
+	}
+	t.ids[path] = n.ID
+	t.Nodes = append(t.Nodes, n)
+}
+
+func (t *GOAPTrace) pop(path *GOAPPath) {
+	if t == nil {
+		return
+	}
+	t.Nodes[t.ids[path]].Popped = t.popped
+	t.popped++
+}
+
+func (t *GOAPTrace) outcome(path *GOAPPath, outcome string, reason error) {
+	if t == nil {
+		return
+	}
+	n := t.Nodes[t.ids[path]]
+	n.Outcome = outcome
+	if reason != nil {
+		n.Reason = reason.Error()
+	}
+}
+
+func (t *GOAPTrace) finish(iterations int, best *GOAPPath) {
+	if t == nil {
+		return
+	}
+	t.Iterations = iterations
+	if best != nil {
+		t.Best = t.ids[best]
+	}
+}
+
+// JSON encodes the trace
+func (t *GOAPTrace) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+// LoadGOAPTraceJSON decodes a trace encoded by GOAPTrace.JSON()
+func LoadGOAPTraceJSON(jsonStr []byte) (*GOAPTrace, error) {
+	t := &GOAPTrace{}
+	if err := json.Unmarshal(jsonStr, t); err != nil {
+		return nil, err
+	}
+	for i, n := range t.Nodes {
+		if n.ID != i || n.Parent >= i {
+			return nil, fmt.Errorf("GOAP trace node %d is out of order", i)
+		}
+	}
+	return t, nil
+}
+
+var goapTraceDOTColors = map[string]string{
+	GOAP_TRACE_QUEUED:   "lightgrey",
+	GOAP_TRACE_EXPANDED: "lightblue",
+	GOAP_TRACE_SOLUTION: "palegreen",
+	GOAP_TRACE_REJECTED: "salmon",
+	GOAP_TRACE_PRUNED:   "white",
+}
+
+// DOT renders the trace's search tree as a Graphviz digraph
+func (t *GOAPTrace) DOT() string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph goap {\n")
+	buf.WriteString("  node [shape=box, style=filled, fontname=monospace];\n")
+	for _, n := range t.Nodes {
+		label := fmt.Sprintf("#%d [%s]\\ncost %.2f", n.ID, strings.Join(n.Path, ","), n.Cost)
+		if n.Popped >= 0 {
+			label += fmt.Sprintf("\\npopped %d", n.Popped)
+		}
+		if left := n.RemainingString(); left != "" {
+			label += "\\n" + left
+		}
+		if n.Reason != "" {
+			label += "\\n" + n.Reason
+		}
+		attrs := fmt.Sprintf("label=%s, fillcolor=%s", goapDOTQuote(label), goapTraceDOTColors[n.Outcome])
+		if n.ID == t.Best {
+			attrs += ", penwidth=3"
+		}
+		if n.Outcome == GOAP_TRACE_PRUNED {
+			attrs += ", style=\"filled,dashed\""
+		}
+		fmt.Fprintf(&buf, "  n%d [%s];\n", n.ID, attrs)
+		if n.Parent >= 0 {
+			fmt.Fprintf(&buf, "  n%d -> n%d [label=%s];\n", n.Parent, n.ID,
+				goapDOTQuote(fmt.Sprintf("%s@%d", n.Inserted, n.InsertionIx)))
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// RemainingString summarises the unfulfilled vars of the node, eg.
+// "chopTree: {hasAxe [1, 1]} | main: {wood [2, +Inf]}"
+func (n *GOAPTraceNode) RemainingString() string {
+	parts := make([]string, 0)
+	for i, regions := range n.Remaining {
+		var at string
+		if i == len(n.Remaining)-1 {
+			at = "main"
+		} else if i < len(n.Path) {
+			at = n.Path[i]
+		}
+		regionStrs := make([]string, 0, len(regions))
+		empty := true
+		for _, region := range regions {
+			vars := make([]string, 0, len(region))
+			for varName, interval := range region {
+				vars = append(vars, varName+" "+interval)
+				empty = false
+			}
+			sort.Strings(vars)
+			regionStrs = append(regionStrs, "{"+strings.Join(vars, ", ")+"}")
+		}
+		if !empty {
+			parts = append(parts, at+": "+strings.Join(regionStrs, " "))
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+func goapDOTQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
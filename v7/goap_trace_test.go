@@ -0,0 +1,136 @@
+package sameriver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGOAPTrace(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	p := wc.p
+	// an action which would take coins out of domain, to be pruned
+	p.AddActions(NewGOAPAction(map[string]any{
+		"name": "stealWood",
+		"node": "self",
+		"cost": 1,
+		"effs": map[string]int{"woodChopped,+": 1, "coins,-": 1},
+	}))
+	p.DeclareVars(GOAPVar{Name: "coins", Domain: MakeNumericInterval(">=", 0)})
+	start := NewGOAPWorldState(nil)
+	goal := map[string]int{"woodChopped,>=": 2}
+
+	if _, ok := p.Plan(start, goal, 50); !ok || p.LastTrace() != nil {
+		t.Fatal("should not trace unless enabled")
+	}
+	p.SetTracing(true)
+	plan, ok := p.Plan(start, goal, 50)
+	if !ok {
+		t.Fatal("should have found a plan")
+	}
+	trace := p.LastTrace()
+	if trace == nil || trace.Entity != wc.e.ID || trace.Goal[0]["woodChopped"] != "[2, +Inf]" {
+		t.Fatalf("should have recorded the plan's entity and goal, got %+v", trace)
+	}
+	root := trace.Nodes[0]
+	if root.Parent != -1 || len(root.Path) != 0 || root.Outcome != GOAP_TRACE_EXPANDED || root.Popped != 0 {
+		t.Fatalf("root should be the expanded empty path, got %+v", root)
+	}
+	if root.RemainingString() != "main: {woodChopped [2, +Inf]}" {
+		t.Fatalf("root should have the main goal remaining, got %s", root.RemainingString())
+	}
+	best := trace.Nodes[trace.Best]
+	if best.Outcome != GOAP_TRACE_SOLUTION || strings.Join(best.Path, ",") != "getAxe,chopTree(2)" ||
+		best.Cost != plan.cost {
+		t.Fatalf("best should be the solution, got %+v", best)
+	}
+	// follow the solution back to the root
+	inserted := make([]string, 0)
+	for n := best; n.Parent != -1; n = trace.Nodes[n.Parent] {
+		if n.Popped < 0 || trace.Nodes[n.Parent].Popped >= n.Popped {
+			t.Fatal("nodes should be popped after their parents")
+		}
+		inserted = append(inserted, n.Inserted)
+	}
+	if strings.Join(inserted, ",") != "getAxe,chopTree(2)" {
+		t.Fatalf("solution should have been built by inserting chopTree then getAxe, got %v", inserted)
+	}
+	pruned := 0
+	for _, n := range trace.Nodes {
+		if n.Outcome == GOAP_TRACE_PRUNED {
+			pruned++
+			if n.Inserted != "stealWood(2)" || !strings.Contains(n.Reason, "out of its declared domain") {
+				t.Fatalf("stealWood should be pruned for taking coins out of domain, got %+v", n)
+			}
+		}
+	}
+	if pruned == 0 {
+		t.Fatal("should have recorded pruned nodes")
+	}
+
+	// export
+	jsonBytes, err := trace.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGOAPTraceJSON(jsonBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Nodes) != len(trace.Nodes) || loaded.Best != trace.Best ||
+		loaded.Nodes[trace.Best].RemainingString() != best.RemainingString() {
+		t.Fatal("trace should round-trip through JSON")
+	}
+	dot := trace.DOT()
+	if !strings.HasPrefix(dot, "digraph goap {") ||
+		!strings.Contains(dot, `n0 -> n1 [label="`) ||
+		!strings.Contains(dot, "penwidth=3") {
+		t.Fatalf("should render DOT, got\n%s", dot)
+	}
+}
+
+func TestGOAPTraceRejected(t *testing.T) {
+	w := testingGOAPAgentWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	p := wc.p
+	// the only way to sell wood claims a market stall, and there are none
+	r := NewGOAPReservations(w, "village")
+	r.SetStock("stall", 0)
+	p.SetReservations(r)
+	p.AddActions(NewGOAPAction(map[string]any{
+		"name":   "sellWood",
+		"node":   "self",
+		"cost":   1,
+		"claims": map[string]int{"stall": 1},
+		"effs":   map[string]int{"woodSold,+": 1},
+	}))
+	p.SetTracing(true)
+	job := p.BeginPlan(NewGOAPWorldState(nil), map[string]int{"woodSold,>=": 1}, 50)
+	if job.Trace() == nil || len(job.Trace().Nodes) != 1 {
+		t.Fatal("job should have a trace with the root from the start")
+	}
+	job.Step(0)
+	if job.Trace().Nodes[0].Outcome != GOAP_TRACE_EXPANDED {
+		t.Fatal("trace should update as the job steps")
+	}
+	for !job.Step(0) {
+	}
+	if _, ok := job.Best(); ok {
+		t.Fatal("should not have found a plan")
+	}
+	rejected := 0
+	for _, n := range job.Trace().Nodes {
+		if n.Outcome == GOAP_TRACE_REJECTED {
+			rejected++
+			if strings.Join(n.Path, ",") != "sellWood" || n.Reason != "path claims 1 stall but 0 available" {
+				t.Fatalf("sellWood should be rejected for claiming a stall, got %+v", n)
+			}
+		}
+	}
+	if rejected == 0 {
+		t.Fatal("should have recorded the rejected node")
+	}
+	if p.LastTrace() != job.Trace() {
+		t.Fatal("finished job's trace should be the last trace")
+	}
+}
@@ -0,0 +1,157 @@
+package sameriver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrGOAPVarOutOfDomain = errors.New("var went out of its declared domain")
+
+type GOAPVarType int
+
+const (
+	GOAP_VAR_INT GOAPVarType = iota
+	GOAP_VAR_FLOAT
+	GOAP_VAR_BOOL
+)
+
+func (t GOAPVarType) String() string {
+	switch t {
+	case GOAP_VAR_INT:
+		return "int"
+	case GOAP_VAR_FLOAT:
+		return "float"
+	case GOAP_VAR_BOOL:
+		return "bool"
+	}
+	return fmt.Sprintf("GOAPVarType(%d)", int(t))
+}
+
+// GOAPVar declares the type and domain of a world state var, given to
+// GOAPPlanner.DeclareVars(). Undeclared vars behave as unbounded ints.
+//
+// Int vars are rounded after each eff, and bool vars are 0 or 1 (any
+// nonzero value is 1, and only "=" effs can change them). If an eff takes
+// a var outside its Domain, either it's clamped back in (if Clamp), or the
+// plan is invalid: the planner prunes a path as soon as an action whose
+// pres are fulfilled would leave the domain, so to have the planner seek
+// a way to stay inside it (eg. earn coins before spending them), give the
+// action a pre (eg. "coins,>=": 3)
+type GOAPVar struct {
+	Name string
+	Type GOAPVarType
+	// nil for unbounded
+	Domain *NumericInterval
+	Clamp  bool
+}
+
+// the value x would take when stored in the var
+func (v *GOAPVar) normalize(x float64) float64 {
+	switch v.Type {
+	case GOAP_VAR_INT:
+		x = math.Round(x)
+	case GOAP_VAR_BOOL:
+		if x != 0 {
+			x = 1
+		}
+	}
+	if v.Clamp && v.Domain != nil {
+		x = math.Max(v.Domain.A, math.Min(v.Domain.B, x))
+	}
+	return x
+}
+
+func (v *GOAPVar) inDomain(x float64) bool {
+	return v.Domain == nil || v.Domain.Diff(x) == 0
+}
+
+// DeclareVars sets the types and domains of vars. Bool vars have the
+// domain [0, 1] if none is given
+func (p *GOAPPlanner) DeclareVars(vars ...GOAPVar) {
+	for _, v := range vars {
+		v := v
+		if v.Type == GOAP_VAR_BOOL && v.Domain == nil {
+			v.Domain = &NumericInterval{0, 1}
+		}
+		p.vars[v.Name] = &v
+		for action := range p.varActions[v.Name] {
+			p.checkEffAgainstVar(action, &v)
+		}
+	}
+}
+
+// panic if the action has an eff which can't apply to the var's type
+func (p *GOAPPlanner) checkEffAgainstVar(action *GOAPAction, v *GOAPVar) {
+	if eff, ok := action.effs[v.Name]; ok && v.Type == GOAP_VAR_BOOL && eff.op != "=" {
+		panic(fmt.Sprintf("action %s has eff %s,%s but bool vars can only be set with =",
+			action.Name, v.Name, eff.op))
+	}
+}
+
+// apply the eff to x as the var (if declared) would store it
+func (p *GOAPPlanner) applyEff(varName string, eff *GOAPEff, count int, x float64) float64 {
+	result := eff.f(count, x)
+	if v, ok := p.vars[varName]; ok {
+		result = v.normalize(result)
+	}
+	return result
+}
+
+// the first var the action affects which is outside its domain in ws
+func (p *GOAPPlanner) effsOutOfDomain(a *GOAPAction, ws *GOAPWorldState) error {
+	for varName := range a.effs {
+		if v, ok := p.vars[varName]; ok && !v.inDomain(ws.vals[varName]) {
+			return fmt.Errorf("%w: %s after %s was %g, domain [%g, %g]",
+				ErrGOAPVarOutOfDomain, varName, a.DisplayName(), ws.vals[varName],
+				v.Domain.A, v.Domain.B)
+		}
+	}
+	return nil
+}
+
+// convert a spec's values (int, float64 or bool) to float64
+func goapSpecVals(spec any) map[string]float64 {
+	result := make(map[string]float64)
+	switch m := spec.(type) {
+	case map[string]int:
+		for k, x := range m {
+			result[k] = float64(x)
+		}
+	case map[string]float64:
+		for k, x := range m {
+			result[k] = x
+		}
+	case map[string]bool:
+		for k, b := range m {
+			result[k] = goapBoolVal(b)
+		}
+	case map[string]any:
+		for k, x := range m {
+			result[k] = goapVal(k, x)
+		}
+	case nil:
+	default:
+		panic(fmt.Sprintf("GOAP spec should be a map of string to int, float64 or bool, got %T", spec))
+	}
+	return result
+}
+
+func goapVal(k string, x any) float64 {
+	switch v := x.(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	case bool:
+		return goapBoolVal(v)
+	}
+	panic(fmt.Sprintf("GOAP spec value for %s should be int, float64 or bool, got %T", k, x))
+}
+
+func goapBoolVal(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,276 @@
+package sameriver
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func testingGOAPVarPlanner() *GOAPPlanner {
+	w := testingWorld()
+	e := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{0, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+	})
+	return NewGOAPPlanner(e, w)
+}
+
+func TestGOAPVarFloatMultiply(t *testing.T) {
+	p := testingGOAPVarPlanner()
+	p.DeclareVars(GOAPVar{Name: "hunger", Type: GOAP_VAR_FLOAT})
+	p.AddActions(NewGOAPAction(map[string]any{
+		"name": "eat",
+		"node": "self",
+		"cost": 1,
+		"effs": map[string]float64{"hunger,*": 0.5},
+	}))
+	start := NewGOAPWorldState(map[string]float64{"hunger": 50})
+	plan, ok := p.Plan(start, map[string]float64{"hunger,<=": 10}, 50)
+	if !ok {
+		t.Fatal("should have found a plan")
+	}
+	// 50 -> 25 -> 12.5 -> 6.25
+	if len(plan.path) != 1 || plan.path[0].Count != 3 {
+		t.Fatalf("should eat 3 times, got %s", plan)
+	}
+	if end := plan.statesAlong[len(plan.path)].vals["hunger"]; end != 6.25 {
+		t.Fatalf("hunger should be 6.25, got %g", end)
+	}
+}
+
+func TestGOAPVarMultiplyUnset(t *testing.T) {
+	p := testingGOAPVarPlanner()
+	p.AddActions(NewGOAPAction(map[string]any{
+		"name": "invest",
+		"node": "self",
+		"cost": 1,
+		"effs": map[string]int{"gold,*": 2},
+	}))
+	// gold is unset, so 0, which no amount of doubling helps
+	if plan, ok := p.Plan(NewGOAPWorldState(nil), map[string]int{"gold,>=": 10}, 50); ok {
+		t.Fatalf("shouldn't find a plan multiplying an unset var, got %s", plan)
+	}
+	p.AddActions(NewGOAPAction(map[string]any{
+		"name": "work",
+		"node": "self",
+		"cost": 1,
+		"effs": map[string]int{"gold,+": 3},
+	}))
+	plan, ok := p.Plan(NewGOAPWorldState(nil), map[string]int{"gold,>=": 10}, 50)
+	if !ok {
+		t.Fatal("should have found a plan")
+	}
+	if end := plan.statesAlong[len(plan.path)].vals["gold"]; end < 10 {
+		t.Fatalf("gold should reach 10, got %g in %s", end, plan)
+	}
+
+	for _, c := range []struct {
+		x, target, val float64
+		scale          int
+		ok             bool
+	}{
+		{50, 10, 0.5, 3, true},
+		{3, 10, 2, 2, true},
+		{0, 10, 2, -1, false},
+		{-4, 10, 2, -1, false},
+		{3, -1, 2, -1, false},
+		{3, 10, 1, -1, false},
+		{3, 10, 0, -1, false},
+		{3, 10, -2, -1, false},
+	} {
+		scale, ok := goapMultiplyScale(c.x, c.target, c.val)
+		if scale != c.scale || ok != c.ok {
+			t.Errorf("%g * %g^n >= %g: expected %d, %t, got %d, %t", c.x, c.val, c.target, c.scale, c.ok, scale, ok)
+		}
+	}
+}
+
+func TestGOAPVarMinMax(t *testing.T) {
+	p := testingGOAPVarPlanner()
+	p.AddActions(
+		NewGOAPAction(map[string]any{
+			"name": "sitByFire",
+			"node": "self",
+			"cost": 1,
+			"effs": map[string]int{"warmth,max": 20},
+		}),
+		NewGOAPAction(map[string]any{
+			"name": "swim",
+			"node": "self",
+			"cost": 1,
+			"effs": map[string]int{"warmth,min": 0},
+		}),
+	)
+	plan, ok := p.Plan(NewGOAPWorldState(map[string]int{"warmth": 5}),
+		map[string]int{"warmth,>": 15}, 50)
+	if !ok || len(plan.path) != 1 || plan.path[0].Name != "sitByFire" {
+		t.Fatalf("should sit by the fire, got %v", plan)
+	}
+	plan, ok = p.Plan(NewGOAPWorldState(map[string]int{"warmth": 5}),
+		map[string]int{"warmth,<": 1}, 50)
+	if !ok || len(plan.path) != 1 || plan.path[0].Name != "swim" {
+		t.Fatalf("should swim, got %v", plan)
+	}
+}
+
+func TestGOAPVarDomainPrunes(t *testing.T) {
+	p := testingGOAPVarPlanner()
+	p.AddActions(
+		NewGOAPAction(map[string]any{
+			"name": "buyAxe",
+			"node": "self",
+			"cost": 1,
+			"effs": map[string]int{"hasAxe,=": 1, "coins,-": 3},
+		}),
+		NewGOAPAction(map[string]any{
+			"name": "forgeAxe",
+			"node": "self",
+			"cost": 20,
+			"effs": map[string]int{"hasAxe,=": 1},
+		}),
+	)
+	start := NewGOAPWorldState(map[string]int{"coins": 1})
+	goal := map[string]int{"hasAxe,=": 1}
+
+	plan, ok := p.Plan(start, goal, 50)
+	if !ok || plan.path[0].Name != "buyAxe" {
+		t.Fatalf("undeclared coins should be allowed to go negative, got %v", plan)
+	}
+	p.DeclareVars(GOAPVar{Name: "coins", Domain: MakeNumericInterval(">=", 0)})
+	plan, ok = p.Plan(start, goal, 50)
+	if !ok || plan.path[0].Name != "forgeAxe" {
+		t.Fatalf("should not buy an axe it can't afford, got %v", plan)
+	}
+	plan, ok = p.Plan(NewGOAPWorldState(map[string]int{"coins": 3}), goal, 50)
+	if !ok || plan.path[0].Name != "buyAxe" {
+		t.Fatalf("should buy an axe it can afford, got %v", plan)
+	}
+}
+
+func TestGOAPVarClampAndRound(t *testing.T) {
+	p := testingGOAPVarPlanner()
+	p.DeclareVars(
+		GOAPVar{Name: "hunger", Type: GOAP_VAR_FLOAT, Domain: &NumericInterval{0, 100}, Clamp: true},
+		GOAPVar{Name: "bread", Type: GOAP_VAR_INT},
+	)
+	feast := NewGOAPAction(map[string]any{
+		"name": "feast",
+		"node": "self",
+		"cost": 1,
+		"effs": map[string]float64{"hunger,-": 30, "bread,*": 0.5},
+	})
+	p.AddActions(feast)
+	ws := p.applyActionBasic(feast, NewGOAPWorldState(map[string]int{"hunger": 20, "bread": 5}), true)
+	if ws.vals["hunger"] != 0 {
+		t.Fatalf("hunger should clamp to 0, got %g", ws.vals["hunger"])
+	}
+	if ws.vals["bread"] != 3 {
+		t.Fatalf("bread should round to 3, got %g", ws.vals["bread"])
+	}
+	plan, ok := p.Plan(NewGOAPWorldState(map[string]int{"hunger": 50}),
+		map[string]int{"hunger,<=": 0}, 50)
+	if !ok || plan.path[0].Count != 2 {
+		t.Fatalf("should feast twice to reach 0, got %v", plan)
+	}
+}
+
+func TestGOAPVarBool(t *testing.T) {
+	p := testingGOAPVarPlanner()
+	p.DeclareVars(GOAPVar{Name: "hasAxe", Type: GOAP_VAR_BOOL})
+	if p.vars["hasAxe"].Domain.A != 0 || p.vars["hasAxe"].Domain.B != 1 {
+		t.Fatal("bool vars should have domain [0, 1]")
+	}
+	p.AddActions(NewGOAPAction(map[string]any{
+		"name": "getAxe",
+		"node": "self",
+		"cost": 1,
+		"effs": map[string]bool{"hasAxe,=": true},
+	}))
+	plan, ok := p.Plan(NewGOAPWorldState(map[string]bool{"hasAxe": false}),
+		map[string]bool{"hasAxe,=": true}, 50)
+	if !ok || plan.path[0].Name != "getAxe" {
+		t.Fatalf("should get the axe, got %v", plan)
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "bool") {
+			t.Fatalf("should panic adding + eff on bool var, got %v", r)
+		}
+	}()
+	p.AddActions(NewGOAPAction(map[string]any{
+		"name": "getAnotherAxe",
+		"node": "self",
+		"cost": 1,
+		"effs": map[string]int{"hasAxe,+": 1},
+	}))
+}
+
+func TestGOAPVarStrictIntervals(t *testing.T) {
+	gt := MakeNumericInterval(">", 2.5)
+	if gt.Diff(2.5) <= 0 || gt.Diff(2.6) != 0 || gt.Diff(3) != 0 {
+		t.Fatal("> should exclude only the value itself")
+	}
+	lt := MakeNumericInterval("<", 3)
+	if lt.Diff(3) >= 0 || lt.Diff(2) != 0 || lt.Diff(math.Nextafter(3, 0)) != 0 {
+		t.Fatal("< should exclude only the value itself")
+	}
+}
+
+func TestGOAPLibraryVars(t *testing.T) {
+	lib, err := LoadGOAPLibraryJSON([]byte(`{
+		"vars": {
+			"hunger": {"type": "float", "min": 0, "max": 100, "clamp": true},
+			"coins": {"min": 0},
+			"hasAxe": {"type": "bool"}
+		},
+		"actions": [
+			{"name": "eat", "node": "self", "cost": 1, "effs": {"hunger,*": 0.5}},
+			{"name": "getAxe", "node": "self", "cost": 1, "effs": {"hasAxe,=": true}}
+		],
+		"goals": {"fed": {"hunger,<=": 12.5}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := testingGOAPVarPlanner()
+	if err := p.AddLibrary(lib); err != nil {
+		t.Fatal(err)
+	}
+	hunger := p.vars["hunger"]
+	if hunger.Type != GOAP_VAR_FLOAT || !hunger.Clamp || hunger.Domain.B != 100 {
+		t.Fatal("should have declared hunger")
+	}
+	if coins := p.vars["coins"]; coins.Type != GOAP_VAR_INT || !math.IsInf(coins.Domain.B, +1) {
+		t.Fatal("coins should be an int with no max")
+	}
+	plan, ok := p.Plan(NewGOAPWorldState(map[string]int{"hunger": 50}), lib.Goal("fed"), 50)
+	if !ok || plan.path[0].Count != 2 {
+		t.Fatalf("should eat twice, got %v", plan)
+	}
+
+	_, err = LoadGOAPLibraryJSON([]byte(`{
+		"vars": {
+			"a": {"type": "string"},
+			"b": {"min": 5, "max": 1, "clamp": "yes"},
+			"c": {"type": "bool", "colour": "red"},
+			"d": {"type": "bool"}
+		},
+		"actions": [
+			{"name": "x", "node": "self", "cost": 1, "effs": {"a,=": "one"}},
+			{"name": "y", "node": "self", "cost": 1, "effs": {"d,+": 1}}
+		]
+	}`))
+	for _, expected := range []string{
+		"vars.a.type: should be int, float or bool, got string",
+		"vars.b: min should be <= max",
+		"vars.b.clamp: should be a bool",
+		"vars.c: unknown field colour",
+		"actions[0](x).effs.a,=: should be a number or bool, got one",
+		"actions[1](y).effs: \"d,+\": bool var d can only be set with =",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("errors should include %q:\n%v", expected, err)
+		}
+	}
+}
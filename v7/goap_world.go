@@ -8,14 +8,14 @@ import (
 type GOAPWorldState struct {
 	w *World
 	// TODO: export vals
-	vals map[string]int
+	vals map[string]float64
 	// TODO: change this to a map[int](map[string]any) [ID][component]
 	modal         map[string]any
 	ModalEntities map[string]*Entity
 }
 
 func (ws *GOAPWorldState) CopyOf() *GOAPWorldState {
-	copyvals := make(map[string]int)
+	copyvals := make(map[string]float64)
 	for k, v := range ws.vals {
 		copyvals[k] = v
 	}
@@ -36,15 +36,13 @@ func (ws *GOAPWorldState) CopyOf() *GOAPWorldState {
 	return copyWS
 }
 
-func NewGOAPWorldState(vals map[string]int) *GOAPWorldState {
-	ws := &GOAPWorldState{
-		vals:  vals,
+// NewGOAPWorldState makes a world state from a map of var values (int,
+// float64 or bool), or nil
+func NewGOAPWorldState(vals any) *GOAPWorldState {
+	return &GOAPWorldState{
+		vals:  goapSpecVals(vals),
 		modal: make(map[string]any),
 	}
-	if vals == nil {
-		ws.vals = make(map[string]int)
-	}
-	return ws
 }
 
 func (ws *GOAPWorldState) ecKey(e *Entity, name ComponentID) string {
@@ -63,21 +63,6 @@ func indexOfEntityInSlice(slice *[]*Entity, x *Entity) int {
 	return -1
 }
 
-func removeEventChannelFromSlice(slice []*EventChannel, x *EventChannel) []*EventChannel {
-	last_ix := len(slice) - 1
-	for i, v := range slice {
-		if v.C == x.C {
-			slice[i] = slice[last_ix]
-			// set the last element to the zero value (for same reasons
-			// as above)
-			slice[last_ix] = &EventChannel{}
-			slice = slice[:last_ix]
-			break
-		}
-	}
-	return slice
-}
-
 func removeUpdatedEntityListFromSlice(
 	slice *[]*UpdatedEntityList, x *UpdatedEntityList) {
 	last_ix := len(*slice) - 1
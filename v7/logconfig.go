@@ -86,6 +86,11 @@ var logGOAPDebug = SubLogFunction(
 	"GOAP", DEBUG_GOAP,
 	func(s string) string { return s })
 
+var DEBUG_UTILITY = os.Getenv("DEBUG_UTILITY") == "true"
+var logUtilityDebug = SubLogFunction(
+	"Utility", DEBUG_UTILITY,
+	func(s string) string { return s })
+
 var DEBUG_RUNTIME_LIMITER = os.Getenv("DEBUG_RUNTIME_LIMITER") == "true"
 var logRuntimeLimiter = SubLogFunction(
 	"RuntimeLimiter", DEBUG_RUNTIME_LIMITER,
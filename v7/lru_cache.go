@@ -0,0 +1,82 @@
+package sameriver
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruCache is a map holding at most capacity keys, evicting the least
+// recently used, safe for concurrent use
+type lruCache[K comparable, V any] struct {
+	mutex    sync.Mutex
+	capacity int
+	// most recently used at the front
+	order *list.List
+	items map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRUCache[K comparable, V any](capacity int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+func (c *lruCache[K, V]) Get(key K) (value V, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry[K, V]).value, true
+}
+
+func (c *lruCache[K, V]) Put(key K, value V) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key, value})
+	c.evict()
+}
+
+func (c *lruCache[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// Clear removes all keys
+func (c *lruCache[K, V]) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.order.Init()
+	c.items = make(map[K]*list.Element)
+}
+
+// Resize sets the capacity, evicting keys if there are now too many
+func (c *lruCache[K, V]) Resize(capacity int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.capacity = capacity
+	c.evict()
+}
+
+func (c *lruCache[K, V]) evict() {
+	for c.order.Len() > c.capacity {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.items, el.Value.(*lruEntry[K, V]).key)
+	}
+}
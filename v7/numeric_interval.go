@@ -23,18 +23,20 @@ func (i *NumericInterval) Diff(x float64) float64 {
 	}
 }
 
-func MakeNumericInterval(op string, val int) *NumericInterval {
+// the strict ops (<, >) exclude val by the smallest step, so they work for
+// float vars as well as ints
+func MakeNumericInterval(op string, val float64) *NumericInterval {
 	switch op {
 	case "<":
-		return &NumericInterval{math.Inf(-1), float64(val - 1)}
+		return &NumericInterval{math.Inf(-1), math.Nextafter(val, math.Inf(-1))}
 	case "<=":
-		return &NumericInterval{math.Inf(-1), float64(val)}
+		return &NumericInterval{math.Inf(-1), val}
 	case "=":
-		return &NumericInterval{float64(val), float64(val)}
+		return &NumericInterval{val, val}
 	case ">=":
-		return &NumericInterval{float64(val), math.Inf(+1)}
+		return &NumericInterval{val, math.Inf(+1)}
 	case ">":
-		return &NumericInterval{float64(val + 1), math.Inf(+1)}
+		return &NumericInterval{math.Nextafter(val, math.Inf(+1)), math.Inf(+1)}
 		/*
 			case ">;<":
 				// TODO
@@ -0,0 +1,233 @@
+package sameriver
+
+import (
+	"math"
+)
+
+/*
+ORCA (optimal reciprocal collision avoidance, van den Berg et al.) finds,
+for each agent, the velocity closest to the one it would like which is
+guaranteed not to collide with any neighbour within a time horizon, assuming
+each neighbour takes half the responsibility for avoiding the collision.
+
+Each neighbour contributes a half-plane of permitted velocities (an
+orcaLine: the permitted side is to the left of direction), and the new
+velocity is found by a small incremental linear program over those
+half-planes, constrained to a circle of radius maxSpeed. This is a port of
+the linear programs in the RVO2 library.
+*/
+
+const ORCA_EPSILON = 1e-5
+
+type orcaLine struct {
+	point     Vec2D
+	direction Vec2D
+}
+
+// orcaAgent is what the half-plane computation needs to know about an agent
+type orcaAgent struct {
+	pos    Vec2D
+	vel    Vec2D
+	radius float64
+}
+
+// the half-plane of velocities for a which avoid b for timeHorizon,
+// given a takes responsibility (0.5 for reciprocal, 1 if b won't avoid a)
+// of the avoidance. timeStep is the time until the next velocity update,
+// used to resolve agents which are already overlapping
+func orcaLineFor(a, b orcaAgent, timeHorizon, timeStep, responsibility float64) orcaLine {
+	relPos := b.pos.Sub(a.pos)
+	relVel := a.vel.Sub(b.vel)
+	distSq := relPos.Dot(relPos)
+	combinedRadius := a.radius + b.radius
+	combinedRadiusSq := combinedRadius * combinedRadius
+
+	var line orcaLine
+	var u Vec2D
+	if distSq > combinedRadiusSq {
+		// no collision yet
+		invTimeHorizon := 1 / timeHorizon
+		// vector from the cutoff circle's centre to the relative velocity
+		w := relVel.Sub(relPos.Scale(invTimeHorizon))
+		wLengthSq := w.Dot(w)
+		dotProduct1 := w.Dot(relPos)
+		if dotProduct1 < 0 && dotProduct1*dotProduct1 > combinedRadiusSq*wLengthSq {
+			// project on the cutoff circle
+			wLength := math.Sqrt(wLengthSq)
+			unitW := w.Scale(1 / wLength)
+			line.direction = Vec2D{unitW.Y, -unitW.X}
+			u = unitW.Scale(combinedRadius*invTimeHorizon - wLength)
+		} else {
+			// project on the legs of the velocity obstacle cone
+			leg := math.Sqrt(distSq - combinedRadiusSq)
+			if relPos.ScalarCross(w) > 0 {
+				// left leg
+				line.direction = Vec2D{
+					relPos.X*leg - relPos.Y*combinedRadius,
+					relPos.X*combinedRadius + relPos.Y*leg,
+				}.Scale(1 / distSq)
+			} else {
+				// right leg
+				line.direction = Vec2D{
+					relPos.X*leg + relPos.Y*combinedRadius,
+					-relPos.X*combinedRadius + relPos.Y*leg,
+				}.Scale(-1 / distSq)
+			}
+			dotProduct2 := relVel.Dot(line.direction)
+			u = line.direction.Scale(dotProduct2).Sub(relVel)
+		}
+	} else {
+		// already colliding; project on the cutoff circle for this time step
+		invTimeStep := 1 / timeStep
+		w := relVel.Sub(relPos.Scale(invTimeStep))
+		wLength := w.Magnitude()
+		if wLength == 0 {
+			// exactly coincident and at rest relative to each other; pick
+			// any direction to separate in
+			w = Vec2D{1, 0}
+			wLength = 1
+		}
+		unitW := w.Scale(1 / wLength)
+		line.direction = Vec2D{unitW.Y, -unitW.X}
+		u = unitW.Scale(combinedRadius*invTimeStep - wLength)
+	}
+	line.point = a.vel.Add(u.Scale(responsibility))
+	return line
+}
+
+// solve for the point on line lineNo nearest optVelocity (or furthest in
+// direction optVelocity if directionOpt) satisfying the earlier lines
+// and within radius
+func orcaLinearProgram1(lines []orcaLine, lineNo int, radius float64,
+	optVelocity Vec2D, directionOpt bool, result *Vec2D) bool {
+
+	line := lines[lineNo]
+	dotProduct := line.point.Dot(line.direction)
+	discriminant := dotProduct*dotProduct + radius*radius - line.point.Dot(line.point)
+	if discriminant < 0 {
+		// the max speed circle fully invalidates this line
+		return false
+	}
+	sqrtDiscriminant := math.Sqrt(discriminant)
+	tLeft := -dotProduct - sqrtDiscriminant
+	tRight := -dotProduct + sqrtDiscriminant
+
+	for i := 0; i < lineNo; i++ {
+		denominator := line.direction.ScalarCross(lines[i].direction)
+		numerator := lines[i].direction.ScalarCross(line.point.Sub(lines[i].point))
+		if math.Abs(denominator) <= ORCA_EPSILON {
+			// lines are (almost) parallel
+			if numerator < 0 {
+				return false
+			}
+			continue
+		}
+		t := numerator / denominator
+		if denominator >= 0 {
+			// line i bounds line lineNo on the right
+			tRight = math.Min(tRight, t)
+		} else {
+			// line i bounds line lineNo on the left
+			tLeft = math.Max(tLeft, t)
+		}
+		if tLeft > tRight {
+			return false
+		}
+	}
+
+	if directionOpt {
+		if optVelocity.Dot(line.direction) > 0 {
+			*result = line.point.Add(line.direction.Scale(tRight))
+		} else {
+			*result = line.point.Add(line.direction.Scale(tLeft))
+		}
+	} else {
+		t := line.direction.Dot(optVelocity.Sub(line.point))
+		if t < tLeft {
+			*result = line.point.Add(line.direction.Scale(tLeft))
+		} else if t > tRight {
+			*result = line.point.Add(line.direction.Scale(tRight))
+		} else {
+			*result = line.point.Add(line.direction.Scale(t))
+		}
+	}
+	return true
+}
+
+// solve for the velocity nearest optVelocity satisfying all lines within
+// radius, returning len(lines) on success, or else the index of the line
+// at which it failed (result then holds the best velocity found so far)
+func orcaLinearProgram2(lines []orcaLine, radius float64,
+	optVelocity Vec2D, directionOpt bool, result *Vec2D) int {
+
+	if directionOpt {
+		// optVelocity is a unit direction
+		*result = optVelocity.Scale(radius)
+	} else if optVelocity.Dot(optVelocity) > radius*radius {
+		*result = optVelocity.Unit().Scale(radius)
+	} else {
+		*result = optVelocity
+	}
+	for i := range lines {
+		if lines[i].direction.ScalarCross(lines[i].point.Sub(*result)) > 0 {
+			// result doesn't satisfy line i; find the best point on it
+			tempResult := *result
+			if !orcaLinearProgram1(lines, i, radius, optVelocity, directionOpt, result) {
+				*result = tempResult
+				return i
+			}
+		}
+	}
+	return len(lines)
+}
+
+// when the lines are infeasible together (the agents are too crowded), find
+// the velocity which minimises the maximum violation of any line, starting
+// from beginLine where orcaLinearProgram2 failed
+func orcaLinearProgram3(lines []orcaLine, beginLine int, radius float64, result *Vec2D) {
+	distance := 0.0
+	for i := beginLine; i < len(lines); i++ {
+		if lines[i].direction.ScalarCross(lines[i].point.Sub(*result)) <= distance {
+			// result already satisfies this line within distance
+			continue
+		}
+		projLines := make([]orcaLine, 0, i)
+		for j := 0; j < i; j++ {
+			var line orcaLine
+			determinant := lines[i].direction.ScalarCross(lines[j].direction)
+			if math.Abs(determinant) <= ORCA_EPSILON {
+				if lines[i].direction.Dot(lines[j].direction) > 0 {
+					// same direction
+					continue
+				}
+				// opposite direction
+				line.point = lines[i].point.Add(lines[j].point).Scale(0.5)
+			} else {
+				t := lines[j].direction.ScalarCross(lines[i].point.Sub(lines[j].point)) / determinant
+				line.point = lines[i].point.Add(lines[i].direction.Scale(t))
+			}
+			line.direction = lines[j].direction.Sub(lines[i].direction).Unit()
+			projLines = append(projLines, line)
+		}
+		tempResult := *result
+		optDirection := Vec2D{-lines[i].direction.Y, lines[i].direction.X}
+		if orcaLinearProgram2(projLines, radius, optDirection, true, result) < len(projLines) {
+			// should in principle not happen; the result is by definition
+			// already in the feasible region, so keep it
+			*result = tempResult
+		}
+		distance = lines[i].direction.ScalarCross(lines[i].point.Sub(*result))
+	}
+}
+
+// orcaVelocity finds the velocity nearest prefVelocity, no faster than
+// maxSpeed, satisfying the given half-planes (or violating them as little
+// as possible, if they can't all be satisfied)
+func orcaVelocity(lines []orcaLine, prefVelocity Vec2D, maxSpeed float64) Vec2D {
+	var result Vec2D
+	lineFail := orcaLinearProgram2(lines, maxSpeed, prefVelocity, false, &result)
+	if lineFail < len(lines) {
+		orcaLinearProgram3(lines, lineFail, maxSpeed, &result)
+	}
+	return result
+}
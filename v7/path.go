@@ -0,0 +1,68 @@
+package sameriver
+
+// Path is the value of a PATH component: a list of waypoints which the
+// PathfindingSystem feeds one at a time into the entity's MOVEMENTTARGET
+type Path struct {
+	Waypoints []Vec2D
+	// index into Waypoints of the waypoint we're currently moving toward
+	Ix int
+	// how close we need to get to a waypoint before moving on to the next
+	ArriveRadius float64
+	// true while a path request for this entity is queued or being searched
+	Pending bool
+	// true if the last path request for this entity found no path
+	Failed bool
+}
+
+func NewPath(waypoints []Vec2D, arriveRadius float64) Path {
+	return Path{
+		Waypoints:    waypoints,
+		ArriveRadius: arriveRadius,
+	}
+}
+
+func (p *Path) CopyOf() Path {
+	waypoints := make([]Vec2D, len(p.Waypoints))
+	copy(waypoints, p.Waypoints)
+	result := *p
+	result.Waypoints = waypoints
+	return result
+}
+
+// Set replaces the waypoints and starts following them from the first
+func (p *Path) Set(waypoints []Vec2D) {
+	p.Waypoints = waypoints
+	p.Ix = 0
+	p.Pending = false
+	p.Failed = false
+}
+
+func (p *Path) Clear() {
+	p.Waypoints = nil
+	p.Ix = 0
+}
+
+// Current returns the waypoint currently being moved toward
+func (p *Path) Current() (waypoint Vec2D, ok bool) {
+	if p.Done() {
+		return Vec2D{}, false
+	}
+	return p.Waypoints[p.Ix], true
+}
+
+func (p *Path) Advance() {
+	if !p.Done() {
+		p.Ix++
+	}
+}
+
+func (p *Path) Done() bool {
+	return p.Ix >= len(p.Waypoints)
+}
+
+func (p *Path) Remaining() []Vec2D {
+	if p.Done() {
+		return []Vec2D{}
+	}
+	return p.Waypoints[p.Ix:]
+}
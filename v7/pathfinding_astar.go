@@ -0,0 +1,141 @@
+package sameriver
+
+import (
+	"container/heap"
+)
+
+// a resumable A* search over integer node IDs, shared by the grid (A*, JPS)
+// and navmesh pathfinders. step() expands at most n nodes so a long search
+// can be spread over several frames by the PathfindingSystem
+type astarSearch struct {
+	start int
+	goal  int
+	// call yield for each successor of n; parent is -1 for the start node
+	neighbours func(n int, parent int, yield func(m int, cost float64))
+	heuristic  func(n int) float64
+
+	open   astarOpenSet
+	g      map[int]float64
+	parent map[int]int
+	closed map[int]bool
+
+	done  bool
+	found bool
+	// total nodes expanded so far
+	expansions int
+}
+
+func newAStarSearch(
+	start, goal int,
+	neighbours func(n int, parent int, yield func(m int, cost float64)),
+	heuristic func(n int) float64) *astarSearch {
+
+	s := &astarSearch{
+		start:      start,
+		goal:       goal,
+		neighbours: neighbours,
+		heuristic:  heuristic,
+		open:       make(astarOpenSet, 0),
+		g:          map[int]float64{start: 0},
+		parent:     map[int]int{start: -1},
+		closed:     make(map[int]bool),
+	}
+	heap.Push(&s.open, &astarOpenItem{node: start, f: heuristic(start)})
+	return s
+}
+
+// expand up to n nodes, returning true once the search has finished (check
+// s.found to see if it succeeded)
+func (s *astarSearch) step(n int) (done bool) {
+	for i := 0; i < n && !s.done; i++ {
+		if s.open.Len() == 0 {
+			s.done = true
+			break
+		}
+		here := heap.Pop(&s.open).(*astarOpenItem)
+		// we don't decrease-key; stale duplicates of closed nodes are skipped
+		if s.closed[here.node] {
+			continue
+		}
+		if here.node == s.goal {
+			s.done = true
+			s.found = true
+			break
+		}
+		s.closed[here.node] = true
+		s.expansions++
+		gHere := s.g[here.node]
+		s.neighbours(here.node, s.parent[here.node], func(m int, cost float64) {
+			if s.closed[m] {
+				return
+			}
+			gThere := gHere + cost
+			if g, seen := s.g[m]; seen && g <= gThere {
+				return
+			}
+			s.g[m] = gThere
+			s.parent[m] = here.node
+			heap.Push(&s.open, &astarOpenItem{node: m, f: gThere + s.heuristic(m)})
+		})
+	}
+	return s.done
+}
+
+// run the search to completion
+func (s *astarSearch) run() (found bool) {
+	for !s.step(1024) {
+	}
+	return s.found
+}
+
+// the node IDs from start to goal (nil if no path was found)
+func (s *astarSearch) nodePath() []int {
+	if !s.found {
+		return nil
+	}
+	reversed := make([]int, 0)
+	for n := s.goal; n != -1; n = s.parent[n] {
+		reversed = append(reversed, n)
+	}
+	path := make([]int, len(reversed))
+	for i, n := range reversed {
+		path[len(reversed)-1-i] = n
+	}
+	return path
+}
+
+type astarOpenItem struct {
+	node  int
+	f     float64
+	index int
+}
+
+type astarOpenSet []*astarOpenItem
+
+func (pq astarOpenSet) Len() int { return len(pq) }
+
+func (pq astarOpenSet) Less(i, j int) bool {
+	return pq[i].f < pq[j].f
+}
+
+func (pq astarOpenSet) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *astarOpenSet) Push(x any) {
+	item := x.(*astarOpenItem)
+	item.index = len(*pq)
+	*pq = append(*pq, item)
+}
+
+func (pq *astarOpenSet) Pop() any {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*pq = old[0 : n-1]
+	return item
+}
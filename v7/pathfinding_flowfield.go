@@ -0,0 +1,255 @@
+package sameriver
+
+import (
+	"container/heap"
+	"math"
+	"runtime"
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+/*
+A FlowField gives, for every cell of a PathGrid, the direction to move in
+to follow the cheapest path to a single target. It's built in two passes:
+
+  - the integration field: the cost of the cheapest path from each cell to
+    the target (Dijkstra outward from the target)
+  - the direction field: for each cell, the unit vector toward the
+    neighbour which is cheapest to go through
+
+Once built, any number of entities heading to the same target can look up
+their direction in O(1), which is far cheaper than an A* search per entity
+when hundreds of units share a goal.
+*/
+type FlowField struct {
+	Grid *PathGrid
+	// the exact target position, and its cell
+	Target  Vec2D
+	targetX int
+	targetY int
+	// cost of the cheapest path from each cell to the target, indexed
+	// [y*Width+x] (PATH_COST_IMPASSABLE if the target can't be reached)
+	integration []float64
+	// the unit direction to move in from each cell, indexed [y*Width+x]
+	// (zero at the target cell and at cells which can't reach it)
+	directions []Vec2D
+}
+
+func NewFlowField(grid *PathGrid, target Vec2D) *FlowField {
+	tx, ty := grid.CellOf(target)
+	f := &FlowField{
+		Grid:        grid,
+		Target:      target,
+		targetX:     tx,
+		targetY:     ty,
+		integration: make([]float64, grid.Width*grid.Height),
+		directions:  make([]Vec2D, grid.Width*grid.Height),
+	}
+	for i := range f.integration {
+		f.integration[i] = PATH_COST_IMPASSABLE
+	}
+	if grid.Walkable(tx, ty) {
+		f.integrate()
+		f.direct()
+	}
+	return f
+}
+
+func (f *FlowField) integrate() {
+	g := f.Grid
+	open := make(astarOpenSet, 0)
+	targetID := g.cellID(f.targetX, f.targetY)
+	f.integration[targetID] = 0
+	heap.Push(&open, &astarOpenItem{node: targetID, f: 0})
+	for open.Len() > 0 {
+		here := heap.Pop(&open).(*astarOpenItem)
+		// stale duplicate (we don't decrease-key)
+		if here.f > f.integration[here.node] {
+			continue
+		}
+		x, y := g.cellXY(here.node)
+		// we're searching outward from the target, so the cost of the edge
+		// from neighbour n into here is the cost to enter here
+		for _, d := range pathGridDirections {
+			nx, ny := x+d[0], y+d[1]
+			if !g.InBounds(nx, ny) || !g.canStep(nx, ny, -d[0], -d[1]) {
+				continue
+			}
+			stepLength := 1.0
+			if d[0] != 0 && d[1] != 0 {
+				stepLength = math.Sqrt2
+			}
+			cost := here.f + stepLength*g.Cost(x, y)
+			n := g.cellID(nx, ny)
+			if cost < f.integration[n] {
+				f.integration[n] = cost
+				heap.Push(&open, &astarOpenItem{node: n, f: cost})
+			}
+		}
+	}
+}
+
+func (f *FlowField) direct() {
+	g := f.Grid
+	for i := range f.directions {
+		x, y := g.cellXY(i)
+		if math.IsInf(f.integration[i], +1) ||
+			(x == f.targetX && y == f.targetY) {
+			continue
+		}
+		best := f.integration[i]
+		for _, d := range pathGridDirections {
+			if !g.canStep(x, y, d[0], d[1]) {
+				continue
+			}
+			n := f.integration[g.cellID(x+d[0], y+d[1])]
+			if n < best {
+				best = n
+				f.directions[i] = Vec2D{float64(d[0]), float64(d[1])}.Unit()
+			}
+		}
+	}
+}
+
+// Integration returns the cost of the cheapest path from cell (x, y) to the
+// target
+func (f *FlowField) Integration(x, y int) float64 {
+	if !f.Grid.InBounds(x, y) {
+		return PATH_COST_IMPASSABLE
+	}
+	return f.integration[f.Grid.cellID(x, y)]
+}
+
+// Direction returns the unit vector to move in from pos to follow the field.
+// Within the target cell, this points straight at the target. ok is false
+// if the target can't be reached from pos (or pos is exactly the target)
+func (f *FlowField) Direction(pos Vec2D) (dir Vec2D, ok bool) {
+	return f.direction(pos, f.Target)
+}
+
+// as Direction(), but heading for target within the target cell (fields
+// are cached per cell, so entities sharing a field may have different
+// targets within it)
+func (f *FlowField) direction(pos, target Vec2D) (dir Vec2D, ok bool) {
+	x, y := f.Grid.CellOf(pos)
+	if !f.Grid.InBounds(x, y) {
+		return Vec2D{}, false
+	}
+	if x == f.targetX && y == f.targetY {
+		toTarget := target.Sub(pos)
+		if toTarget.Magnitude() == 0 {
+			return Vec2D{}, false
+		}
+		return toTarget.Unit(), true
+	}
+	dir = f.directions[f.Grid.cellID(x, y)]
+	return dir, dir != Vec2D{}
+}
+
+// FlowFieldCache holds FlowFields by target cell, building them on demand
+// and keeping the FLOWFIELD_CACHE_SIZE most recently used. Fields are
+// dropped whenever the grid's costs change (it's watched with OnChange()),
+// since a single changed cell can reroute paths anywhere on the map
+type FlowFieldCache struct {
+	Grid   *PathGrid
+	fields *lruCache[int, *FlowField]
+	// incremented by Invalidate(), so that fields built from the costs
+	// before it aren't cached after it
+	generation atomic.Uint64
+}
+
+func NewFlowFieldCache(grid *PathGrid) *FlowFieldCache {
+	c := &FlowFieldCache{
+		Grid:   grid,
+		fields: newLRUCache[int, *FlowField](FLOWFIELD_CACHE_SIZE),
+	}
+	grid.OnChange(c.Invalidate)
+	return c
+}
+
+func (c *FlowFieldCache) key(target Vec2D) int {
+	x, y := c.Grid.CellOf(target)
+	if !c.Grid.InBounds(x, y) {
+		return -1
+	}
+	return c.Grid.cellID(x, y)
+}
+
+// Cached returns the field for target's cell if it's already been built.
+// Note that the field's Target is the position it was first built for,
+// which may differ from target within the same cell
+func (c *FlowFieldCache) Cached(target Vec2D) (f *FlowField, ok bool) {
+	return c.fields.Get(c.key(target))
+}
+
+// Get returns the field for target's cell, building it if needed
+func (c *FlowFieldCache) Get(target Vec2D) *FlowField {
+	if f, ok := c.Cached(target); ok {
+		return f
+	}
+	generation := c.generation.Load()
+	f := NewFlowField(c.Grid, target)
+	if c.generation.Load() == generation {
+		c.fields.Put(c.key(target), f)
+	}
+	return f
+}
+
+// Build builds the fields for all the given targets not already cached,
+// on a worker per CPU core
+func (c *FlowFieldCache) Build(targets []Vec2D) {
+	toBuild := make([]Vec2D, 0, len(targets))
+	building := make(map[int]bool)
+	for _, target := range targets {
+		k := c.key(target)
+		if _, ok := c.Cached(target); ok || building[k] {
+			continue
+		}
+		building[k] = true
+		toBuild = append(toBuild, target)
+	}
+	numWorkers := runtime.NumCPU()
+	if numWorkers > len(toBuild) {
+		numWorkers = len(toBuild)
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func(i int) {
+			for j := i; j < len(toBuild); j += numWorkers {
+				c.Get(toBuild[j])
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (c *FlowFieldCache) Size() int {
+	return c.fields.Len()
+}
+
+// SetSize sets how many fields are kept
+func (c *FlowFieldCache) SetSize(size int) {
+	c.fields.Resize(size)
+}
+
+// Invalidate drops all cached fields
+func (c *FlowFieldCache) Invalidate() {
+	c.generation.Inc()
+	c.fields.Clear()
+}
+
+// WatchTileMap keeps the grid's costs in sync with tmap (looking up costs by
+// tile kind in tileCosts, as in PathGridFromTileMap), invalidating the cache
+// whenever a tile changes
+func (c *FlowFieldCache) WatchTileMap(tmap *TileMap, tileCosts map[string]float64) {
+	tmap.OnSetTile(func(x, y int32, kind string) {
+		if !c.Grid.InBounds(int(x), int(y)) {
+			return
+		}
+		// which invalidates the cache if the cost changed
+		c.Grid.SetCost(int(x), int(y), TileCost(tileCosts, kind))
+	})
+}
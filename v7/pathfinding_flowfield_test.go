@@ -0,0 +1,135 @@
+package sameriver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFlowFieldAroundWall(t *testing.T) {
+	g := testingWalledPathGrid()
+	target := Vec2D{9.5, 0.5}
+	f := NewFlowField(g, target)
+	// follow the field cell by cell from the far side of the wall
+	pos := Vec2D{0.5, 0.5}
+	for i := 0; i < 100; i++ {
+		dir, ok := f.Direction(pos)
+		if !ok {
+			break
+		}
+		x, y := g.CellOf(pos)
+		if tx, ty := g.CellOf(target); x == tx && y == ty {
+			break
+		}
+		pos = g.CellCenter(x+int(math.Round(dir.X)), y+int(math.Round(dir.Y)))
+		if !g.Walkable(g.CellOf(pos)) {
+			t.Fatalf("field led into a wall at %v", pos)
+		}
+	}
+	if x, y := g.CellOf(pos); x != 9 || y != 0 {
+		t.Fatalf("following the field should reach the target cell, reached %v", pos)
+	}
+	if f.Integration(9, 0) != 0 {
+		t.Fatal("target cell should have integration 0")
+	}
+	// an entity overlapping a wall can still find its way out
+	if _, ok := f.Direction(g.CellCenter(5, 0)); !ok {
+		t.Fatal("wall cell should have a direction out")
+	}
+}
+
+func TestFlowFieldUnreachable(t *testing.T) {
+	g := testingWalledPathGrid()
+	g.SetCost(5, 9, PATH_COST_IMPASSABLE)
+	f := NewFlowField(g, Vec2D{9.5, 0.5})
+	if _, ok := f.Direction(Vec2D{0.5, 0.5}); ok {
+		t.Fatal("should have no direction where the target can't be reached")
+	}
+	if _, ok := f.Direction(Vec2D{7.5, 0.5}); !ok {
+		t.Fatal("should have a direction on the target's side of the wall")
+	}
+}
+
+func TestFlowFieldCacheInvalidateOnSetTile(t *testing.T) {
+	tmap := NewTileMap(&TileManager{Dimension: 1}, 10, 10)
+	costs := map[string]float64{"wall": PATH_COST_IMPASSABLE}
+	c := NewFlowFieldCache(PathGridFromTileMap(tmap, costs))
+	c.WatchTileMap(tmap, costs)
+	target := Vec2D{9.5, 0.5}
+	f := c.Get(target)
+	if c.Get(Vec2D{9.1, 0.9}) != f {
+		t.Fatal("targets in the same cell should share a field")
+	}
+	c.Build([]Vec2D{{0.5, 0.5}, {0.5, 0.5}, {3.5, 3.5}})
+	if c.Size() != 3 {
+		t.Fatalf("cache should hold 3 fields, held %d", c.Size())
+	}
+	// setting a tile to what it already is doesn't invalidate
+	tmap.SetTile(2, 2, "")
+	if c.Size() != 3 {
+		t.Fatal("no-op tile change should not invalidate")
+	}
+	tmap.SetTile(8, 0, "wall")
+	if c.Size() != 0 {
+		t.Fatal("tile change should invalidate the cache")
+	}
+	if c.Grid.Walkable(8, 0) {
+		t.Fatal("grid should have been updated from the tile change")
+	}
+	if c.Get(target) == f {
+		t.Fatal("field should have been rebuilt")
+	}
+}
+
+func TestFlowFieldCacheBounded(t *testing.T) {
+	c := NewFlowFieldCache(NewPathGrid(10, 10, 1))
+	c.SetSize(4)
+	targets := make([]Vec2D, 0)
+	for x := 0; x < 10; x++ {
+		targets = append(targets, Vec2D{float64(x) + 0.5, 0.5})
+	}
+	c.Build(targets)
+	if c.Size() != 4 {
+		t.Fatalf("cache should hold at most 4 fields, held %d", c.Size())
+	}
+	// changing a cost without a tile map invalidates too
+	c.Grid.SetCost(5, 5, PATH_COST_IMPASSABLE)
+	if c.Size() != 0 {
+		t.Fatal("SetCost should invalidate the cache")
+	}
+}
+
+func testingSpawnFlowFieldFollower(w *World, pos, target Vec2D) *Entity {
+	return w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_:    pos,
+			VELOCITY_:    Vec2D{0, 0},
+			MAXVELOCITY_: 3.0,
+			STEER_:       Vec2D{0, 0},
+			MASS_:        3.0,
+			FLOWFIELD_:   target,
+		}})
+}
+
+func TestFlowFieldSystem(t *testing.T) {
+	w := testingWorld()
+	ss := NewSteeringSystem()
+	fs := NewFlowFieldSystem(NewPathGrid(64, 64, 16))
+	w.RegisterSystems(ss, fs)
+	target := Vec2D{1000, 500}
+	e := testingSpawnFlowFieldFollower(w, Vec2D{10, 500}, target)
+	w.Update(FRAME_MS / 2)
+	if fs.Fields.Size() != 1 {
+		t.Fatal("should have built the follower's field")
+	}
+	v := *w.GetVec2D(e, VELOCITY_)
+	if v.X <= 0 {
+		t.Fatalf("follower should be steered toward the target, velocity %v", v)
+	}
+	// same result single-threaded
+	e2 := testingSpawnFlowFieldFollower(w, Vec2D{10, 500}, target)
+	w.Update(FRAME_MS / 2)
+	fs.SingleThreadUpdate()
+	if w.GetVec2D(e2, VELOCITY_).X <= 0 {
+		t.Fatal("single-threaded update should steer too")
+	}
+}
@@ -0,0 +1,498 @@
+package sameriver
+
+import (
+	"math"
+)
+
+// the cost of a cell which can't be entered at all
+var PATH_COST_IMPASSABLE = math.Inf(+1)
+
+// PathGrid is a grid of per-cell movement costs which the grid pathfinders
+// (A* and jump-point search) search over. Cells are CellSize world units
+// square, with cell (0, 0) at the world origin
+type PathGrid struct {
+	Width    int
+	Height   int
+	CellSize float64
+	// the cost to enter each cell, indexed [y*Width+x]
+	costs []float64
+	// the cheapest walkable cell cost, used to keep the A* heuristic
+	// admissible on weighted grids (recomputed lazily after SetCost)
+	minCost      float64
+	minCostDirty bool
+	// called when a cell's cost changes (see OnChange())
+	onChange []func()
+}
+
+func NewPathGrid(width, height int, cellSize float64) *PathGrid {
+	g := &PathGrid{
+		Width:        width,
+		Height:       height,
+		CellSize:     cellSize,
+		costs:        make([]float64, width*height),
+		minCostDirty: true,
+	}
+	for i := range g.costs {
+		g.costs[i] = 1
+	}
+	return g
+}
+
+// PathGridFromTileMap builds a grid with one cell per tile, where the cost
+// of each cell is looked up by tile kind in tileCosts. Kinds not present in
+// tileCosts (including the empty kind "" for unset tiles) cost 1; use
+// PATH_COST_IMPASSABLE for walls, water, etc.
+func PathGridFromTileMap(tmap *TileMap, tileCosts map[string]float64) *PathGrid {
+	cellSize := 1.0
+	if tmap.tm != nil && tmap.tm.Dimension != 0 {
+		cellSize = float64(tmap.tm.Dimension)
+	}
+	g := NewPathGrid(int(tmap.Width), int(tmap.Height), cellSize)
+	g.UpdateFromTileMap(tmap, tileCosts)
+	return g
+}
+
+// UpdateFromTileMap recomputes every cell's cost from the tile kinds in tmap
+func (g *PathGrid) UpdateFromTileMap(tmap *TileMap, tileCosts map[string]float64) {
+	for y := 0; y < g.Height && y < len(tmap.Tiles); y++ {
+		for x := 0; x < g.Width && x < len(tmap.Tiles[y]); x++ {
+			g.SetCost(x, y, TileCost(tileCosts, tmap.Tiles[y][x]))
+		}
+	}
+}
+
+// TileCost looks up the cost of a tile kind, defaulting to 1
+func TileCost(tileCosts map[string]float64, kind string) float64 {
+	if cost, ok := tileCosts[kind]; ok {
+		return cost
+	}
+	return 1
+}
+
+func (g *PathGrid) InBounds(x, y int) bool {
+	return x >= 0 && x < g.Width && y >= 0 && y < g.Height
+}
+
+func (g *PathGrid) SetCost(x, y int, cost float64) {
+	if g.costs[y*g.Width+x] == cost {
+		return
+	}
+	g.costs[y*g.Width+x] = cost
+	g.minCostDirty = true
+	for _, f := range g.onChange {
+		f()
+	}
+}
+
+// OnChange registers f to be called whenever a cell's cost changes (as
+// FlowFieldCaches do, to invalidate their fields)
+func (g *PathGrid) OnChange(f func()) {
+	g.onChange = append(g.onChange, f)
+}
+
+// Cost returns the cost to enter the cell (impassable if out of bounds)
+func (g *PathGrid) Cost(x, y int) float64 {
+	if !g.InBounds(x, y) {
+		return PATH_COST_IMPASSABLE
+	}
+	return g.costs[y*g.Width+x]
+}
+
+func (g *PathGrid) Walkable(x, y int) bool {
+	cost := g.Cost(x, y)
+	return cost >= 0 && !math.IsInf(cost, +1)
+}
+
+func (g *PathGrid) CellOf(pos Vec2D) (x, y int) {
+	return int(math.Floor(pos.X / g.CellSize)), int(math.Floor(pos.Y / g.CellSize))
+}
+
+func (g *PathGrid) CellCenter(x, y int) Vec2D {
+	return Vec2D{
+		(float64(x) + 0.5) * g.CellSize,
+		(float64(y) + 0.5) * g.CellSize,
+	}
+}
+
+func (g *PathGrid) cellID(x, y int) int {
+	return y*g.Width + x
+}
+
+func (g *PathGrid) cellXY(id int) (x, y int) {
+	return id % g.Width, id / g.Width
+}
+
+func (g *PathGrid) cheapestCost() float64 {
+	if g.minCostDirty {
+		g.minCost = math.MaxFloat64
+		for i, cost := range g.costs {
+			if g.Walkable(g.cellXY(i)) && cost < g.minCost {
+				g.minCost = cost
+			}
+		}
+		if g.minCost == math.MaxFloat64 {
+			g.minCost = 1
+		}
+		g.minCostDirty = false
+	}
+	return g.minCost
+}
+
+// octile distance in cells between two cells (the exact cost of moving
+// between them with 8-directional movement on an empty uniform grid)
+func octileDistance(x0, y0, x1, y1 int) float64 {
+	dx := math.Abs(float64(x1 - x0))
+	dy := math.Abs(float64(y1 - y0))
+	return dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)
+}
+
+// diagonal moves are only allowed when both adjacent orthogonal cells are
+// walkable, so paths never clip the corner of a wall
+func (g *PathGrid) canStep(x, y, dx, dy int) bool {
+	if !g.Walkable(x+dx, y+dy) {
+		return false
+	}
+	if dx != 0 && dy != 0 {
+		return g.Walkable(x+dx, y) && g.Walkable(x, y+dy)
+	}
+	return true
+}
+
+var pathGridDirections = [8][2]int{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// check that start and goal can be searched between, returning their cell IDs
+func (g *PathGrid) endpoints(start, goal Vec2D) (startID, goalID int, ok bool) {
+	sx, sy := g.CellOf(start)
+	gx, gy := g.CellOf(goal)
+	// we allow the start cell to be unwalkable (an entity might be
+	// overlapping a wall) but the goal must be reachable
+	if !g.InBounds(sx, sy) || !g.Walkable(gx, gy) {
+		return -1, -1, false
+	}
+	return g.cellID(sx, sy), g.cellID(gx, gy), true
+}
+
+func (g *PathGrid) newAStarSearch(start, goal Vec2D) *astarSearch {
+	startID, goalID, ok := g.endpoints(start, goal)
+	if !ok {
+		return nil
+	}
+	gx, gy := g.cellXY(goalID)
+	minCost := g.cheapestCost()
+	return newAStarSearch(startID, goalID,
+		func(n int, parent int, yield func(m int, cost float64)) {
+			x, y := g.cellXY(n)
+			for _, d := range pathGridDirections {
+				if !g.canStep(x, y, d[0], d[1]) {
+					continue
+				}
+				stepLength := 1.0
+				if d[0] != 0 && d[1] != 0 {
+					stepLength = math.Sqrt2
+				}
+				yield(g.cellID(x+d[0], y+d[1]), stepLength*g.Cost(x+d[0], y+d[1]))
+			}
+		},
+		func(n int) float64 {
+			x, y := g.cellXY(n)
+			return octileDistance(x, y, gx, gy) * minCost
+		})
+}
+
+/*
+Jump point search (Harabor & Grastien) prunes the symmetric paths of a
+uniform-cost grid by "jumping" along straight and diagonal lines, only
+adding to the open set those cells where the optimal path might turn (where
+a neighbour is forced by an adjacent wall). It treats every walkable cell as
+cost 1, so use A* on grids with weighted terrain.
+
+This is the variant which disallows cutting corners, matching canStep().
+*/
+func (g *PathGrid) newJPSSearch(start, goal Vec2D) *astarSearch {
+	startID, goalID, ok := g.endpoints(start, goal)
+	if !ok {
+		return nil
+	}
+	gx, gy := g.cellXY(goalID)
+	return newAStarSearch(startID, goalID,
+		func(n int, parent int, yield func(m int, cost float64)) {
+			x, y := g.cellXY(n)
+			for _, d := range g.jpsPrunedDirections(n, parent) {
+				jx, jy, ok := g.jump(x+d[0], y+d[1], x, y, gx, gy)
+				if ok {
+					yield(g.cellID(jx, jy), octileDistance(x, y, jx, jy))
+				}
+			}
+		},
+		func(n int) float64 {
+			x, y := g.cellXY(n)
+			return octileDistance(x, y, gx, gy)
+		})
+}
+
+// the directions in which to look for successors of n, given we arrived
+// from parent
+func (g *PathGrid) jpsPrunedDirections(n int, parent int) [][2]int {
+	x, y := g.cellXY(n)
+	dirs := make([][2]int, 0, 8)
+	if parent == -1 {
+		for _, d := range pathGridDirections {
+			if g.canStep(x, y, d[0], d[1]) {
+				dirs = append(dirs, d)
+			}
+		}
+		return dirs
+	}
+	px, py := g.cellXY(parent)
+	dx, dy := signInt(x-px), signInt(y-py)
+	switch {
+	case dx != 0 && dy != 0:
+		if g.Walkable(x, y+dy) {
+			dirs = append(dirs, [2]int{0, dy})
+		}
+		if g.Walkable(x+dx, y) {
+			dirs = append(dirs, [2]int{dx, 0})
+		}
+		if g.canStep(x, y, dx, dy) {
+			dirs = append(dirs, [2]int{dx, dy})
+		}
+	case dx != 0:
+		next := g.Walkable(x+dx, y)
+		up := g.Walkable(x, y+1)
+		down := g.Walkable(x, y-1)
+		if next {
+			dirs = append(dirs, [2]int{dx, 0})
+			if up {
+				dirs = append(dirs, [2]int{dx, 1})
+			}
+			if down {
+				dirs = append(dirs, [2]int{dx, -1})
+			}
+		}
+		if up {
+			dirs = append(dirs, [2]int{0, 1})
+		}
+		if down {
+			dirs = append(dirs, [2]int{0, -1})
+		}
+	case dy != 0:
+		next := g.Walkable(x, y+dy)
+		right := g.Walkable(x+1, y)
+		left := g.Walkable(x-1, y)
+		if next {
+			dirs = append(dirs, [2]int{0, dy})
+			if right {
+				dirs = append(dirs, [2]int{1, dy})
+			}
+			if left {
+				dirs = append(dirs, [2]int{-1, dy})
+			}
+		}
+		if right {
+			dirs = append(dirs, [2]int{1, 0})
+		}
+		if left {
+			dirs = append(dirs, [2]int{-1, 0})
+		}
+	}
+	return dirs
+}
+
+// jump from (px, py) through (x, y) in the direction between them until we
+// hit a wall (ok = false) or find a jump point
+func (g *PathGrid) jump(x, y, px, py, gx, gy int) (jx, jy int, ok bool) {
+	dx, dy := x-px, y-py
+	for {
+		if !g.Walkable(x, y) {
+			return 0, 0, false
+		}
+		if x == gx && y == gy {
+			return x, y, true
+		}
+		switch {
+		case dx != 0 && dy != 0:
+			// moving diagonally, we have to look for jump points along the
+			// horizontal and vertical
+			if _, _, ok := g.jump(x+dx, y, x, y, gx, gy); ok {
+				return x, y, true
+			}
+			if _, _, ok := g.jump(x, y+dy, x, y, gx, gy); ok {
+				return x, y, true
+			}
+		case dx != 0:
+			if (g.Walkable(x, y-1) && !g.Walkable(x-dx, y-1)) ||
+				(g.Walkable(x, y+1) && !g.Walkable(x-dx, y+1)) {
+				return x, y, true
+			}
+		case dy != 0:
+			if (g.Walkable(x-1, y) && !g.Walkable(x-1, y-dy)) ||
+				(g.Walkable(x+1, y) && !g.Walkable(x+1, y-dy)) {
+				return x, y, true
+			}
+		}
+		if !(g.Walkable(x+dx, y) && g.Walkable(x, y+dy)) {
+			return 0, 0, false
+		}
+		x, y = x+dx, y+dy
+	}
+}
+
+func signInt(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// turn the cell IDs of a finished search into world-space waypoints; the
+// start cell is dropped (we're already in it) and the final waypoint is the
+// exact goal rather than the center of its cell
+func (g *PathGrid) waypoints(s *astarSearch, goal Vec2D) []Vec2D {
+	cells := s.nodePath()
+	waypoints := make([]Vec2D, 0, len(cells))
+	for i := 1; i < len(cells)-1; i++ {
+		waypoints = append(waypoints, g.CellCenter(g.cellXY(cells[i])))
+	}
+	return append(waypoints, goal)
+}
+
+// AStar finds the cheapest 8-directional path from start to goal, weighing
+// each step by the cost of the cell entered
+func (g *PathGrid) AStar(start, goal Vec2D) (path []Vec2D, ok bool) {
+	s := g.newAStarSearch(start, goal)
+	if s == nil || !s.run() {
+		return nil, false
+	}
+	return g.waypoints(s, goal), true
+}
+
+// JPS finds the shortest path from start to goal, treating all walkable
+// cells as equal cost; much faster than AStar on large open grids
+func (g *PathGrid) JPS(start, goal Vec2D) (path []Vec2D, ok bool) {
+	s := g.newJPSSearch(start, goal)
+	if s == nil || !s.run() {
+		return nil, false
+	}
+	return g.waypoints(s, goal), true
+}
+
+// visit each cell the segment from a to b passes through, in order (when
+// the segment passes exactly through a cell corner, both cells beside the
+// corner are visited). Stops early and returns false if visit does
+func (g *PathGrid) cellsAlong(a, b Vec2D, visit func(x, y int) bool) bool {
+	ax, ay := a.X/g.CellSize, a.Y/g.CellSize
+	bx, by := b.X/g.CellSize, b.Y/g.CellSize
+	x, y := int(math.Floor(ax)), int(math.Floor(ay))
+	endX, endY := int(math.Floor(bx)), int(math.Floor(by))
+	dx, dy := bx-ax, by-ay
+	stepX, stepY := 0, 0
+	tMaxX, tMaxY := math.Inf(+1), math.Inf(+1)
+	tDeltaX, tDeltaY := math.Inf(+1), math.Inf(+1)
+	if dx > 0 {
+		stepX = 1
+		tDeltaX = 1 / dx
+		tMaxX = (math.Floor(ax) + 1 - ax) / dx
+	} else if dx < 0 {
+		stepX = -1
+		tDeltaX = -1 / dx
+		tMaxX = (ax - math.Floor(ax)) / -dx
+	}
+	if dy > 0 {
+		stepY = 1
+		tDeltaY = 1 / dy
+		tMaxY = (math.Floor(ay) + 1 - ay) / dy
+	} else if dy < 0 {
+		stepY = -1
+		tDeltaY = -1 / dy
+		tMaxY = (ay - math.Floor(ay)) / -dy
+	}
+	// guard against floating point error walking us past the end cell
+	maxSteps := absInt(endX-x) + absInt(endY-y) + 1
+	for i := 0; i <= maxSteps; i++ {
+		if !visit(x, y) {
+			return false
+		}
+		if x == endX && y == endY {
+			return true
+		}
+		switch {
+		case tMaxX < tMaxY:
+			tMaxX += tDeltaX
+			x += stepX
+		case tMaxY < tMaxX:
+			tMaxY += tDeltaY
+			y += stepY
+		default:
+			if !visit(x+stepX, y) || !visit(x, y+stepY) {
+				return false
+			}
+			tMaxX += tDeltaX
+			tMaxY += tDeltaY
+			x += stepX
+			y += stepY
+		}
+	}
+	return true
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// LineOfSight determines whether the segment from a to b crosses only
+// walkable cells costing no more than maxCost
+func (g *PathGrid) LineOfSight(a, b Vec2D, maxCost float64) bool {
+	return g.cellsAlong(a, b, func(x, y int) bool {
+		return g.Walkable(x, y) && g.Cost(x, y) <= maxCost
+	})
+}
+
+// SmoothPath removes waypoints which can be skipped by walking in a straight
+// line ("string pulling"). A shortcut is only taken if it doesn't cross any
+// cell more costly than those the original path crossed between the same
+// two waypoints, so smoothing won't send us wading through a swamp we
+// routed around. from is the position the path is being walked from.
+func (g *PathGrid) SmoothPath(from Vec2D, path []Vec2D) []Vec2D {
+	if len(path) < 2 {
+		return path
+	}
+	points := append([]Vec2D{from}, path...)
+	// the most costly cell crossed by the path on the way to each point
+	segmentCost := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		segmentCost[i] = 0
+		g.cellsAlong(points[i-1], points[i], func(x, y int) bool {
+			if g.Walkable(x, y) {
+				segmentCost[i] = math.Max(segmentCost[i], g.Cost(x, y))
+			}
+			return true
+		})
+	}
+	smoothed := make([]Vec2D, 0, len(path))
+	anchor := 0
+	for anchor < len(points)-1 {
+		// find the furthest point visible from the anchor
+		next := anchor + 1
+		maxCost := segmentCost[next]
+		for j := anchor + 2; j < len(points); j++ {
+			maxCost = math.Max(maxCost, segmentCost[j])
+			if !g.LineOfSight(points[anchor], points[j], maxCost) {
+				break
+			}
+			next = j
+		}
+		smoothed = append(smoothed, points[next])
+		anchor = next
+	}
+	return smoothed
+}
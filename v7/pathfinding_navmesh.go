@@ -0,0 +1,217 @@
+package sameriver
+
+import (
+	"math"
+)
+
+// how close two vertices must be to be considered the same when finding the
+// edges shared between navmesh polygons
+const NAVMESH_VERTEX_EPSILON = 1e-6
+
+// NavMesh is a set of convex polygonal regions which an entity can walk
+// freely within. Polygons which share an edge are connected through that
+// edge (a "portal"), and paths are found by A* over the polygons followed
+// by the funnel algorithm to pull the path taut through the portals
+type NavMesh struct {
+	// the convex regions, with vertices normalised to counter-clockwise order
+	Polygons []Polygon
+	// the centroid of each polygon, used as its position in the A* graph
+	centroids []Vec2D
+	// links[i] are the polygons reachable from polygon i
+	links [][]navMeshLink
+}
+
+type navMeshLink struct {
+	to int
+	// the shared edge, with left and right as seen when crossing from this
+	// polygon into the other
+	left  Vec2D
+	right Vec2D
+}
+
+// NewNavMesh builds a navmesh from convex polygons, connecting those which
+// share an edge exactly (same two vertices). Concave regions should be split
+// into convex pieces before being given here
+func NewNavMesh(polygons []Polygon) *NavMesh {
+	nm := &NavMesh{
+		Polygons:  make([]Polygon, len(polygons)),
+		centroids: make([]Vec2D, len(polygons)),
+		links:     make([][]navMeshLink, len(polygons)),
+	}
+	for i, poly := range polygons {
+		vertices := make([]Vec2D, len(poly.Vertices))
+		copy(vertices, poly.Vertices)
+		normalised := Polygon{Vertices: vertices}
+		if normalised.SignedArea() < 0 {
+			for a, b := 0, len(vertices)-1; a < b; a, b = a+1, b-1 {
+				vertices[a], vertices[b] = vertices[b], vertices[a]
+			}
+		}
+		nm.Polygons[i] = normalised
+		nm.centroids[i] = normalised.Centroid()
+		nm.links[i] = make([]navMeshLink, 0)
+	}
+	for i := range nm.Polygons {
+		for j := i + 1; j < len(nm.Polygons); j++ {
+			nm.linkIfAdjacent(i, j)
+		}
+	}
+	return nm
+}
+
+func navMeshVerticesEqual(a, b Vec2D) bool {
+	return math.Abs(a.X-b.X) < NAVMESH_VERTEX_EPSILON &&
+		math.Abs(a.Y-b.Y) < NAVMESH_VERTEX_EPSILON
+}
+
+func (nm *NavMesh) linkIfAdjacent(i, j int) {
+	pi := nm.Polygons[i].Vertices
+	pj := nm.Polygons[j].Vertices
+	for a := range pi {
+		a0, a1 := pi[a], pi[(a+1)%len(pi)]
+		for b := range pj {
+			b0, b1 := pj[b], pj[(b+1)%len(pj)]
+			// both polygons are counter-clockwise, so a shared edge runs in
+			// opposite directions around each
+			if navMeshVerticesEqual(a0, b1) && navMeshVerticesEqual(a1, b0) {
+				// crossing out of a counter-clockwise polygon, the edge's
+				// end vertex is on our left
+				nm.links[i] = append(nm.links[i], navMeshLink{to: j, left: a1, right: a0})
+				nm.links[j] = append(nm.links[j], navMeshLink{to: i, left: b1, right: b0})
+				return
+			}
+		}
+	}
+}
+
+// PolygonAt returns the index of the polygon containing pos, or -1
+func (nm *NavMesh) PolygonAt(pos Vec2D) int {
+	for i := range nm.Polygons {
+		if nm.Polygons[i].Contains(pos) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Neighbours returns the indexes of the polygons sharing an edge with polygon i
+func (nm *NavMesh) Neighbours(i int) []int {
+	result := make([]int, len(nm.links[i]))
+	for k, link := range nm.links[i] {
+		result[k] = link.to
+	}
+	return result
+}
+
+func (nm *NavMesh) linkBetween(i, j int) (navMeshLink, bool) {
+	for _, link := range nm.links[i] {
+		if link.to == j {
+			return link, true
+		}
+	}
+	return navMeshLink{}, false
+}
+
+func (nm *NavMesh) newSearch(start, goal Vec2D) *astarSearch {
+	startPoly := nm.PolygonAt(start)
+	goalPoly := nm.PolygonAt(goal)
+	if startPoly == -1 || goalPoly == -1 {
+		return nil
+	}
+	return newAStarSearch(startPoly, goalPoly,
+		func(n int, parent int, yield func(m int, cost float64)) {
+			for _, link := range nm.links[n] {
+				_, _, d := nm.centroids[n].Distance(nm.centroids[link.to])
+				yield(link.to, d)
+			}
+		},
+		func(n int) float64 {
+			_, _, d := nm.centroids[n].Distance(goal)
+			return d
+		})
+}
+
+// the waypoints through the corridor of polygons found by a finished search
+func (nm *NavMesh) waypoints(s *astarSearch, start, goal Vec2D) []Vec2D {
+	return nm.StringPull(start, goal, s.nodePath())
+}
+
+// FindPath finds a path from start to goal across the navmesh. Both points
+// must lie inside some polygon of the mesh
+func (nm *NavMesh) FindPath(start, goal Vec2D) (path []Vec2D, ok bool) {
+	s := nm.newSearch(start, goal)
+	if s == nil || !s.run() {
+		return nil, false
+	}
+	return nm.waypoints(s, start, goal), true
+}
+
+// StringPull runs the "simple stupid funnel algorithm" (Mononen) over the
+// portals between the given corridor of polygons, producing the shortest
+// path from start to goal through them. The returned waypoints exclude start.
+func (nm *NavMesh) StringPull(start, goal Vec2D, corridor []int) []Vec2D {
+	// the portals to pass through; the first and last are degenerate
+	// portals at the start and goal points
+	lefts := []Vec2D{start}
+	rights := []Vec2D{start}
+	for i := 0; i < len(corridor)-1; i++ {
+		link, ok := nm.linkBetween(corridor[i], corridor[i+1])
+		if !ok {
+			continue
+		}
+		lefts = append(lefts, link.left)
+		rights = append(rights, link.right)
+	}
+	lefts = append(lefts, goal)
+	rights = append(rights, goal)
+
+	// positive if c is to the left of the ray a->b
+	cross := func(a, b, c Vec2D) float64 {
+		return b.Sub(a).ScalarCross(c.Sub(a))
+	}
+
+	path := make([]Vec2D, 0)
+	apex, portalLeft, portalRight := start, lefts[0], rights[0]
+	apexIx, leftIx, rightIx := 0, 0, 0
+	for i := 1; i < len(lefts); i++ {
+		left, right := lefts[i], rights[i]
+
+		// try to narrow the funnel from the right
+		if cross(apex, portalRight, right) >= 0 {
+			if navMeshVerticesEqual(apex, portalRight) || cross(apex, portalLeft, right) < 0 {
+				portalRight = right
+				rightIx = i
+			} else {
+				// right crossed over left; left becomes a corner of the path
+				path = append(path, portalLeft)
+				apex = portalLeft
+				apexIx = leftIx
+				portalLeft, portalRight = apex, apex
+				leftIx, rightIx = apexIx, apexIx
+				i = apexIx
+				continue
+			}
+		}
+
+		// try to narrow the funnel from the left
+		if cross(apex, portalLeft, left) <= 0 {
+			if navMeshVerticesEqual(apex, portalLeft) || cross(apex, portalRight, left) > 0 {
+				portalLeft = left
+				leftIx = i
+			} else {
+				// left crossed over right; right becomes a corner of the path
+				path = append(path, portalRight)
+				apex = portalRight
+				apexIx = rightIx
+				portalLeft, portalRight = apex, apex
+				leftIx, rightIx = apexIx, apexIx
+				i = apexIx
+				continue
+			}
+		}
+	}
+	if len(path) == 0 || !navMeshVerticesEqual(path[len(path)-1], goal) {
+		path = append(path, goal)
+	}
+	return path
+}
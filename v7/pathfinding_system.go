@@ -0,0 +1,260 @@
+package sameriver
+
+import (
+	"time"
+)
+
+type PathfindingMode int
+
+const (
+	// A* over the PathGrid, respecting cell costs
+	PathfindAStar PathfindingMode = iota
+	// jump point search over the PathGrid (uniform cost, faster)
+	PathfindJPS
+	// A* + funnel over the NavMesh
+	PathfindNavMesh
+)
+
+// how many nodes a search expands between checks of the time budget
+const PATHFINDING_EXPANSIONS_PER_CHECK = 64
+
+// PathRequest is a queued path search. The search runs over one or more
+// PathfindingSystem.Update() calls, and Callback is invoked when it finishes
+type PathRequest struct {
+	Start Vec2D
+	Goal  Vec2D
+	Mode  PathfindingMode
+	// whether to remove unnecessary waypoints once the path is found
+	Smooth bool
+	// called once the search finishes (path is nil if ok is false)
+	Callback func(path []Vec2D, ok bool)
+
+	// the entity this path is for, if any (used to cancel on despawn)
+	e *Entity
+
+	search    *astarSearch
+	started   bool
+	done      bool
+	cancelled bool
+	path      []Vec2D
+	ok        bool
+}
+
+func (r *PathRequest) Cancel() {
+	r.cancelled = true
+}
+
+func (r *PathRequest) Done() bool {
+	return r.done
+}
+
+func (r *PathRequest) Result() (path []Vec2D, ok bool) {
+	return r.path, r.ok
+}
+
+// PathfindingSystem runs queued path searches over the grid or navmesh
+// within a time budget each Update(), and moves entities with a PATH
+// component along their path by setting MOVEMENTTARGET to each waypoint in
+// turn (the SteeringSystem does the actual moving)
+type PathfindingSystem struct {
+	w *World
+
+	Grid    *PathGrid
+	NavMesh *NavMesh
+
+	// how many ms of searching we allow per Update()
+	Budget_ms float64
+	// default ArriveRadius given to PATH components which don't set one
+	ArriveRadius float64
+
+	requests  []*PathRequest
+	followers *UpdatedEntityList
+}
+
+func NewPathfindingSystem(grid *PathGrid, navMesh *NavMesh) *PathfindingSystem {
+	return &PathfindingSystem{
+		Grid:         grid,
+		NavMesh:      navMesh,
+		Budget_ms:    2,
+		ArriveRadius: 2,
+		requests:     make([]*PathRequest, 0),
+	}
+}
+
+func (s *PathfindingSystem) GetComponentDeps() []any {
+	return []any{
+		POSITION_, VEC2D, "POSITION",
+		MOVEMENTTARGET_, VEC2D, "MOVEMENTTARGET",
+		PATH_, PATH, "PATH",
+	}
+}
+
+func (s *PathfindingSystem) LinkWorld(w *World) {
+	s.w = w
+	s.followers = w.GetUpdatedEntityList(
+		w.EntityFilterFromComponentBitArray(
+			"pathfollowing",
+			w.Em.ComponentsTable.BitArrayFromIDs(
+				[]ComponentID{POSITION_, MOVEMENTTARGET_, PATH_})))
+	w.AddDespawnCallback(func(e *Entity) {
+		for _, r := range s.requests {
+			if r.e == e {
+				r.Cancel()
+			}
+		}
+	})
+}
+
+func (s *PathfindingSystem) Update(dt_ms float64) {
+	s.processRequests()
+	s.followPaths()
+}
+
+// Request queues a path search, returning the request so the caller can
+// poll or cancel it
+func (s *PathfindingSystem) Request(r *PathRequest) *PathRequest {
+	s.requests = append(s.requests, r)
+	return r
+}
+
+func (s *PathfindingSystem) RequestPath(
+	start, goal Vec2D,
+	mode PathfindingMode,
+	callback func(path []Vec2D, ok bool)) *PathRequest {
+
+	return s.Request(&PathRequest{
+		Start:    start,
+		Goal:     goal,
+		Mode:     mode,
+		Smooth:   true,
+		Callback: callback,
+	})
+}
+
+// RequestEntityPath queues a search from the entity's position to goal,
+// and once found, sets the waypoints into its PATH component
+func (s *PathfindingSystem) RequestEntityPath(
+	e *Entity, goal Vec2D, mode PathfindingMode) *PathRequest {
+
+	// a new request for the entity replaces any in flight
+	for _, r := range s.requests {
+		if r.e == e {
+			r.Cancel()
+		}
+	}
+	path := s.w.GetPath(e, PATH_)
+	path.Pending = true
+	return s.Request(&PathRequest{
+		Start:  *s.w.GetVec2D(e, POSITION_),
+		Goal:   goal,
+		Mode:   mode,
+		Smooth: true,
+		e:      e,
+		Callback: func(waypoints []Vec2D, ok bool) {
+			path := s.w.GetPath(e, PATH_)
+			if ok {
+				path.Set(waypoints)
+			} else {
+				path.Clear()
+				path.Pending = false
+				path.Failed = true
+			}
+		},
+	})
+}
+
+func (s *PathfindingSystem) startSearch(r *PathRequest) {
+	r.started = true
+	switch r.Mode {
+	case PathfindAStar, PathfindJPS:
+		if s.Grid == nil {
+			logWarning("grid path requested but PathfindingSystem has no PathGrid")
+			return
+		}
+		if r.Mode == PathfindAStar {
+			r.search = s.Grid.newAStarSearch(r.Start, r.Goal)
+		} else {
+			r.search = s.Grid.newJPSSearch(r.Start, r.Goal)
+		}
+	case PathfindNavMesh:
+		if s.NavMesh == nil {
+			logWarning("navmesh path requested but PathfindingSystem has no NavMesh")
+			return
+		}
+		r.search = s.NavMesh.newSearch(r.Start, r.Goal)
+	}
+}
+
+func (s *PathfindingSystem) finish(r *PathRequest) {
+	r.done = true
+	if r.search != nil && r.search.found {
+		switch r.Mode {
+		case PathfindAStar, PathfindJPS:
+			r.path = s.Grid.waypoints(r.search, r.Goal)
+			if r.Smooth {
+				r.path = s.Grid.SmoothPath(r.Start, r.path)
+			}
+		case PathfindNavMesh:
+			// the funnel algorithm already gives the shortest path
+			r.path = s.NavMesh.waypoints(r.search, r.Start, r.Goal)
+		}
+		r.ok = true
+	}
+	// release the search's memory
+	r.search = nil
+	if r.Callback != nil {
+		r.Callback(r.path, r.ok)
+	}
+}
+
+// advance the queued searches in order until we run out of budget
+func (s *PathfindingSystem) processRequests() {
+	t0 := time.Now()
+	elapsed_ms := func() float64 {
+		return float64(time.Since(t0).Nanoseconds()) / 1e6
+	}
+	for len(s.requests) > 0 && elapsed_ms() < s.Budget_ms {
+		r := s.requests[0]
+		if r.cancelled {
+			s.requests = s.requests[1:]
+			continue
+		}
+		if !r.started {
+			s.startSearch(r)
+		}
+		if r.search == nil || r.search.step(PATHFINDING_EXPANSIONS_PER_CHECK) {
+			s.finish(r)
+			s.requests = s.requests[1:]
+		}
+	}
+}
+
+// point each following entity at its current waypoint
+func (s *PathfindingSystem) followPaths() {
+	for _, e := range s.followers.entities {
+		path := s.w.GetPath(e, PATH_)
+		waypoint, ok := path.Current()
+		if !ok {
+			continue
+		}
+		pos := s.w.GetVec2D(e, POSITION_)
+		radius := path.ArriveRadius
+		if radius == 0 {
+			radius = s.ArriveRadius
+		}
+		// skip past any waypoints we've reached (the last one we hold
+		// onto, so the entity arrives and stays at the goal)
+		for waypoint.Sub(*pos).Magnitude() <= radius && path.Ix < len(path.Waypoints)-1 {
+			path.Advance()
+			waypoint, _ = path.Current()
+		}
+		if waypoint.Sub(*pos).Magnitude() <= radius && path.Ix == len(path.Waypoints)-1 {
+			path.Advance()
+		}
+		*s.w.GetVec2D(e, MOVEMENTTARGET_) = waypoint
+	}
+}
+
+func (s *PathfindingSystem) Expand(n int) {
+	// nil?
+}
@@ -0,0 +1,306 @@
+package sameriver
+
+import (
+	"math"
+	"testing"
+)
+
+// a 10x10 grid with a wall down x=5 leaving a gap only at the top (y=9)
+func testingWalledPathGrid() *PathGrid {
+	g := NewPathGrid(10, 10, 1)
+	for y := 0; y < 9; y++ {
+		g.SetCost(5, y, PATH_COST_IMPASSABLE)
+	}
+	return g
+}
+
+func testingCheckGridPath(t *testing.T, g *PathGrid, start, goal Vec2D, path []Vec2D) {
+	if len(path) == 0 {
+		t.Fatal("path was empty")
+	}
+	if path[len(path)-1] != goal {
+		t.Fatalf("path should end at goal %v, ended at %v", goal, path[len(path)-1])
+	}
+	prev := start
+	for _, p := range path {
+		if !g.LineOfSight(prev, p, math.MaxFloat64) {
+			t.Fatalf("path segment %v -> %v crosses a wall: %v", prev, p, path)
+		}
+		prev = p
+	}
+}
+
+func TestPathGridAStarAroundWall(t *testing.T) {
+	g := testingWalledPathGrid()
+	start, goal := Vec2D{0.5, 0.5}, Vec2D{9.5, 0.5}
+	path, ok := g.AStar(start, goal)
+	if !ok {
+		t.Fatal("should have found a path")
+	}
+	// the path has to go up through the gap in the wall
+	wentThroughGap := false
+	for _, p := range path {
+		x, y := g.CellOf(p)
+		if x == 5 && y == 9 {
+			wentThroughGap = true
+		}
+	}
+	if !wentThroughGap {
+		t.Fatalf("path should pass through the gap at (5, 9): %v", path)
+	}
+	testingCheckGridPath(t, g, start, goal, path)
+}
+
+func TestPathGridJPSAroundWall(t *testing.T) {
+	g := testingWalledPathGrid()
+	start, goal := Vec2D{0.5, 0.5}, Vec2D{9.5, 0.5}
+	astarPath, _ := g.AStar(start, goal)
+	jpsPath, ok := g.JPS(start, goal)
+	if !ok {
+		t.Fatal("should have found a path")
+	}
+	testingCheckGridPath(t, g, start, goal, jpsPath)
+	length := func(path []Vec2D) float64 {
+		total := 0.0
+		prev := start
+		for _, p := range path {
+			total += p.Sub(prev).Magnitude()
+			prev = p
+		}
+		return total
+	}
+	if math.Abs(length(astarPath)-length(jpsPath)) > 1e-6 {
+		t.Fatalf("JPS path length %f should equal A* path length %f",
+			length(jpsPath), length(astarPath))
+	}
+}
+
+func TestPathGridNoPath(t *testing.T) {
+	g := testingWalledPathGrid()
+	g.SetCost(5, 9, PATH_COST_IMPASSABLE)
+	if _, ok := g.AStar(Vec2D{0.5, 0.5}, Vec2D{9.5, 0.5}); ok {
+		t.Fatal("A* should not find a path through a solid wall")
+	}
+	if _, ok := g.JPS(Vec2D{0.5, 0.5}, Vec2D{9.5, 0.5}); ok {
+		t.Fatal("JPS should not find a path through a solid wall")
+	}
+	// goal inside the wall
+	if _, ok := g.AStar(Vec2D{0.5, 0.5}, Vec2D{5.5, 0.5}); ok {
+		t.Fatal("should not find a path to an impassable cell")
+	}
+}
+
+func TestPathGridAvoidsCostlyCells(t *testing.T) {
+	g := NewPathGrid(10, 3, 1)
+	// a swamp along the middle row, except at the ends
+	for x := 1; x < 9; x++ {
+		g.SetCost(x, 1, 20)
+	}
+	path, ok := g.AStar(Vec2D{0.5, 1.5}, Vec2D{9.5, 1.5})
+	if !ok {
+		t.Fatal("should have found a path")
+	}
+	for _, p := range path {
+		x, y := g.CellOf(p)
+		if g.Cost(x, y) > 1 {
+			t.Fatalf("path should go around the swamp: %v", path)
+		}
+	}
+}
+
+func TestPathGridSmoothPath(t *testing.T) {
+	g := NewPathGrid(10, 10, 1)
+	start, goal := Vec2D{0.5, 0.5}, Vec2D{9.5, 4.5}
+	path, ok := g.AStar(start, goal)
+	if !ok {
+		t.Fatal("should have found a path")
+	}
+	smoothed := g.SmoothPath(start, path)
+	if len(smoothed) != 1 || smoothed[0] != goal {
+		t.Fatalf("path over an open grid should smooth to just the goal, got %v", smoothed)
+	}
+}
+
+func TestPathGridFromTileMap(t *testing.T) {
+	tm := NewTileMap(&TileManager{Dimension: 8}, 4, 4)
+	tm.Tiles[2][1] = "water"
+	g := PathGridFromTileMap(tm, map[string]float64{"water": PATH_COST_IMPASSABLE})
+	if g.CellSize != 8 {
+		t.Fatalf("cell size should come from tile dimension, got %f", g.CellSize)
+	}
+	if g.Walkable(1, 2) {
+		t.Fatal("water tile should be impassable")
+	}
+	if !g.Walkable(0, 0) {
+		t.Fatal("unset tile should be walkable")
+	}
+}
+
+// an L-shaped corridor: A is bottom-left, B bottom-right, C top-right
+func testingLNavMesh() *NavMesh {
+	return NewNavMesh([]Polygon{
+		{Vertices: []Vec2D{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+		{Vertices: []Vec2D{{10, 0}, {20, 0}, {20, 10}, {10, 10}}},
+		// given clockwise, to check normalisation
+		{Vertices: []Vec2D{{10, 10}, {10, 20}, {20, 20}, {20, 10}}},
+	})
+}
+
+func TestNavMeshLinks(t *testing.T) {
+	nm := testingLNavMesh()
+	if len(nm.Neighbours(0)) != 1 || nm.Neighbours(0)[0] != 1 {
+		t.Fatalf("A should neighbour only B, got %v", nm.Neighbours(0))
+	}
+	if len(nm.Neighbours(1)) != 2 {
+		t.Fatalf("B should neighbour A and C, got %v", nm.Neighbours(1))
+	}
+	if nm.PolygonAt(Vec2D{15, 15}) != 2 {
+		t.Fatal("PolygonAt should find C")
+	}
+	if nm.PolygonAt(Vec2D{5, 15}) != -1 {
+		t.Fatal("PolygonAt should find nothing outside the mesh")
+	}
+}
+
+func TestNavMeshStraightPath(t *testing.T) {
+	nm := testingLNavMesh()
+	goal := Vec2D{19, 9}
+	path, ok := nm.FindPath(Vec2D{1, 1}, goal)
+	if !ok {
+		t.Fatal("should have found a path")
+	}
+	if len(path) != 1 || path[0] != goal {
+		t.Fatalf("visible goal should give a straight path, got %v", path)
+	}
+}
+
+func TestNavMeshFunnelCorner(t *testing.T) {
+	nm := testingLNavMesh()
+	goal := Vec2D{19, 19}
+	path, ok := nm.FindPath(Vec2D{1, 9}, goal)
+	if !ok {
+		t.Fatal("should have found a path")
+	}
+	if len(path) != 2 || path[0] != (Vec2D{10, 10}) || path[1] != goal {
+		t.Fatalf("path should bend around the inner corner (10, 10), got %v", path)
+	}
+	// and the other way round
+	path, _ = nm.FindPath(goal, Vec2D{1, 9})
+	if len(path) != 2 || path[0] != (Vec2D{10, 10}) {
+		t.Fatalf("reverse path should bend around the inner corner (10, 10), got %v", path)
+	}
+}
+
+func TestNavMeshNoPath(t *testing.T) {
+	nm := testingLNavMesh()
+	if _, ok := nm.FindPath(Vec2D{1, 1}, Vec2D{5, 15}); ok {
+		t.Fatal("should not find a path to a point off the mesh")
+	}
+}
+
+func testingSpawnPathfollower(w *World, pos Vec2D) *Entity {
+	return w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_:       pos,
+			MOVEMENTTARGET_: pos,
+			PATH_:           NewPath(nil, 0.5),
+		}})
+}
+
+func TestPathfindingSystemRequestPath(t *testing.T) {
+	w := testingWorld()
+	ps := NewPathfindingSystem(testingWalledPathGrid(), nil)
+	w.RegisterSystems(ps)
+	var result []Vec2D
+	called := false
+	r := ps.RequestPath(Vec2D{0.5, 0.5}, Vec2D{9.5, 0.5}, PathfindJPS,
+		func(path []Vec2D, ok bool) {
+			called = true
+			result = path
+		})
+	for i := 0; i < 10 && !r.Done(); i++ {
+		w.Update(FRAME_MS / 2)
+	}
+	if !called || !r.Done() {
+		t.Fatal("request should have completed and called back")
+	}
+	if path, ok := r.Result(); !ok || len(path) != len(result) {
+		t.Fatal("Result() should give the path passed to the callback")
+	}
+}
+
+func TestPathfindingSystemFollowPath(t *testing.T) {
+	w := testingWorld()
+	ps := NewPathfindingSystem(testingWalledPathGrid(), nil)
+	w.RegisterSystems(ps)
+	e := testingSpawnPathfollower(w, Vec2D{0.5, 0.5})
+	goal := Vec2D{9.5, 0.5}
+	ps.RequestEntityPath(e, goal, PathfindAStar)
+	path := w.GetPath(e, PATH_)
+	if !path.Pending {
+		t.Fatal("path should be pending after request")
+	}
+	for i := 0; i < 10 && path.Pending; i++ {
+		w.Update(FRAME_MS / 2)
+	}
+	if path.Pending || path.Failed || len(path.Waypoints) == 0 {
+		t.Fatalf("path should have been set, got %+v", path)
+	}
+	// move the entity by teleporting it to whatever target it's given
+	for i := 0; i < 20 && !path.Done(); i++ {
+		*w.GetVec2D(e, POSITION_) = *w.GetVec2D(e, MOVEMENTTARGET_)
+		w.Update(FRAME_MS / 2)
+	}
+	if !path.Done() {
+		t.Fatal("entity should have followed the path to the end")
+	}
+	if *w.GetVec2D(e, POSITION_) != goal {
+		t.Fatalf("entity should be at the goal, at %v", *w.GetVec2D(e, POSITION_))
+	}
+}
+
+func TestPathfindingSystemFailedPath(t *testing.T) {
+	w := testingWorld()
+	g := testingWalledPathGrid()
+	g.SetCost(5, 9, PATH_COST_IMPASSABLE)
+	ps := NewPathfindingSystem(g, nil)
+	w.RegisterSystems(ps)
+	e := testingSpawnPathfollower(w, Vec2D{0.5, 0.5})
+	ps.RequestEntityPath(e, Vec2D{9.5, 0.5}, PathfindAStar)
+	for i := 0; i < 10; i++ {
+		w.Update(FRAME_MS / 2)
+	}
+	path := w.GetPath(e, PATH_)
+	if path.Pending || !path.Failed {
+		t.Fatalf("path should have failed, got %+v", path)
+	}
+}
+
+func TestPathfindingSystemCancelOnDespawn(t *testing.T) {
+	w := testingWorld()
+	ps := NewPathfindingSystem(testingWalledPathGrid(), nil)
+	w.RegisterSystems(ps)
+	e := testingSpawnPathfollower(w, Vec2D{0.5, 0.5})
+	r := ps.RequestEntityPath(e, Vec2D{9.5, 0.5}, PathfindAStar)
+	w.Despawn(e)
+	w.Update(FRAME_MS / 2)
+	if r.Done() {
+		t.Fatal("request for despawned entity should have been cancelled")
+	}
+	if len(ps.requests) != 0 {
+		t.Fatal("cancelled request should have been dropped from the queue")
+	}
+}
+
+func TestPathfindingSystemNavMesh(t *testing.T) {
+	w := testingWorld()
+	ps := NewPathfindingSystem(nil, testingLNavMesh())
+	w.RegisterSystems(ps)
+	e := testingSpawnPathfollower(w, Vec2D{1, 9})
+	ps.RequestEntityPath(e, Vec2D{19, 19}, PathfindNavMesh)
+	w.Update(FRAME_MS / 2)
+	if *w.GetVec2D(e, MOVEMENTTARGET_) != (Vec2D{10, 10}) {
+		t.Fatalf("entity should be heading for the corner, heading for %v",
+			*w.GetVec2D(e, MOVEMENTTARGET_))
+	}
+}
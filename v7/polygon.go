@@ -70,3 +70,69 @@ func pointToLineSegmentDistance(point, lineStart, lineEnd Vec2D) float64 {
 	_, _, d := point.Distance(closestPoint)
 	return d
 }
+
+// closestPointOnLineSegment finds the point on the line segment formed by two
+// vertices which is nearest to the given point.
+func closestPointOnLineSegment(point, lineStart, lineEnd Vec2D) Vec2D {
+	lineVector := lineEnd.Sub(lineStart)
+	lengthSquared := lineVector.Dot(lineVector)
+	if lengthSquared == 0 {
+		return lineStart
+	}
+	projection := point.Sub(lineStart).Dot(lineVector) / lengthSquared
+	projection = math.Max(0, math.Min(1, projection))
+	return lineStart.Add(lineVector.Scale(projection))
+}
+
+// SignedArea calculates the area of the polygon, positive if the vertices are
+// in counter-clockwise order and negative if clockwise.
+func (p *Polygon) SignedArea() float64 {
+	area := 0.0
+	for i := 0; i < len(p.Vertices); i++ {
+		a := p.Vertices[i]
+		b := p.Vertices[(i+1)%len(p.Vertices)]
+		area += a.ScalarCross(b)
+	}
+	return area / 2
+}
+
+// Centroid calculates the center of mass of the polygon.
+func (p *Polygon) Centroid() Vec2D {
+	area := p.SignedArea()
+	if area == 0 {
+		// degenerate polygon; fall back to the average of the vertices
+		sum := Vec2D{0, 0}
+		for _, v := range p.Vertices {
+			sum = sum.Add(v)
+		}
+		return sum.Scale(1 / float64(len(p.Vertices)))
+	}
+	var cx, cy float64
+	for i := 0; i < len(p.Vertices); i++ {
+		a := p.Vertices[i]
+		b := p.Vertices[(i+1)%len(p.Vertices)]
+		cross := a.ScalarCross(b)
+		cx += (a.X + b.X) * cross
+		cy += (a.Y + b.Y) * cross
+	}
+	return Vec2D{cx / (6 * area), cy / (6 * area)}
+}
+
+// Contains determines whether the point is inside the polygon (or on its boundary).
+func (p *Polygon) Contains(point Vec2D) bool {
+	if p.DistanceToSide(point) < 1e-9 {
+		return true
+	}
+	// even-odd ray casting
+	inside := false
+	n := len(p.Vertices)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a := p.Vertices[i]
+		b := p.Vertices[j]
+		if (a.Y > point.Y) != (b.Y > point.Y) &&
+			point.X < (b.X-a.X)*(point.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,163 @@
+package sameriver
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// the params used when an entity's RVO component doesn't set them
+var RVO_DEFAULT_PARAMS = map[string]float64{
+	// the radius of the agent (0 to use half the diagonal of its BOX)
+	"radius": 0,
+	// how far ahead in time (ms) to guarantee no collisions; larger values
+	// make agents react earlier but restrict their movement more
+	"timeHorizon": 1000,
+	// how far away to consider neighbours
+	"neighbourDistance": 50,
+	// the most neighbours to consider (the nearest are taken)
+	"maxNeighbours": 10,
+}
+
+// RVOSystem adjusts the VELOCITY of entities with an RVO component (a
+// FLOATMAP of params, see RVO_DEFAULT_PARAMS) so that they don't collide
+// with each other, using ORCA. Each agent assumes other RVO agents will
+// take half the responsibility for avoiding a collision, and that other
+// entities in the spatial hash won't take any.
+//
+// It treats the VELOCITY set by steering as the preferred velocity, so it
+// should be registered after the SteeringSystem and before the
+// PhysicsSystem (systems update in the order they're registered).
+type RVOSystem struct {
+	w      *World
+	agents *UpdatedEntityList
+	// new velocities are computed for all agents before any are set, so
+	// that every agent sees the same neighbour velocities
+	newVelocities []Vec2D
+}
+
+func NewRVOSystem() *RVOSystem {
+	return &RVOSystem{}
+}
+
+func (s *RVOSystem) GetComponentDeps() []any {
+	return []any{
+		POSITION_, VEC2D, "POSITION",
+		VELOCITY_, VEC2D, "VELOCITY",
+		MAXVELOCITY_, FLOAT64, "MAXVELOCITY",
+		BOX_, VEC2D, "BOX",
+		RVO_, FLOATMAP, "RVO",
+	}
+}
+
+func (s *RVOSystem) LinkWorld(w *World) {
+	s.w = w
+	s.agents = w.GetUpdatedEntityList(
+		w.EntityFilterFromComponentBitArray(
+			"rvo",
+			w.Em.ComponentsTable.BitArrayFromIDs(
+				[]ComponentID{POSITION_, VELOCITY_, MAXVELOCITY_, BOX_, RVO_})))
+}
+
+func (s *RVOSystem) Update(dt_ms float64) {
+	if dt_ms <= 0 {
+		dt_ms = FRAME_MS
+	}
+	if cap(s.newVelocities) < len(s.agents.entities) {
+		s.newVelocities = make([]Vec2D, len(s.agents.entities))
+	}
+	s.newVelocities = s.newVelocities[:len(s.agents.entities)]
+
+	// divide the entities into N segments,
+	// where N is the number of CPU cores
+	numWorkers := runtime.NumCPU()
+	entitiesPerWorker := len(s.agents.entities) / numWorkers
+	remainder := len(s.agents.entities) % numWorkers
+
+	wg := sync.WaitGroup{}
+	wg.Add(numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
+		startIndex := i * entitiesPerWorker
+		endIndex := (i + 1) * entitiesPerWorker
+		if i == numWorkers-1 {
+			endIndex += remainder
+		}
+
+		go func(startIndex, endIndex int) {
+			for j := startIndex; j < endIndex; j++ {
+				s.newVelocities[j] = s.Velocity(s.agents.entities[j], dt_ms)
+			}
+			wg.Done()
+		}(startIndex, endIndex)
+	}
+
+	wg.Wait()
+
+	for i, e := range s.agents.entities {
+		*s.w.GetVec2D(e, VELOCITY_) = s.newVelocities[i]
+	}
+}
+
+// Param returns the entity's RVO value for key, or the default
+func (s *RVOSystem) Param(e *Entity, key string) float64 {
+	params := s.w.GetFloatMap(e, RVO_)
+	if params.Has(key) {
+		return params.Get(key)
+	}
+	return RVO_DEFAULT_PARAMS[key]
+}
+
+func (s *RVOSystem) orcaAgent(e *Entity) orcaAgent {
+	a := orcaAgent{
+		pos: *s.w.GetVec2D(e, POSITION_),
+	}
+	if s.w.EntityHasComponent(e, VELOCITY_) {
+		a.vel = *s.w.GetVec2D(e, VELOCITY_)
+	}
+	if s.w.EntityHasComponent(e, RVO_) {
+		a.radius = s.Param(e, "radius")
+	}
+	if a.radius == 0 {
+		a.radius = s.w.GetVec2D(e, BOX_).Magnitude() / 2
+	}
+	return a
+}
+
+// Velocity computes the collision-free velocity for the entity nearest its
+// current VELOCITY, given timeStep ms until the next update
+func (s *RVOSystem) Velocity(e *Entity, timeStep float64) Vec2D {
+	me := s.orcaAgent(e)
+	maxV := *s.w.GetFloat64(e, MAXVELOCITY_)
+	neighbourDistance := s.Param(e, "neighbourDistance")
+	maxNeighbours := int(s.Param(e, "maxNeighbours"))
+	timeHorizon := s.Param(e, "timeHorizon")
+
+	neighbours := s.w.SpatialHasher.EntitiesWithinDistanceFilter(
+		me.pos, *s.w.GetVec2D(e, BOX_), neighbourDistance,
+		func(o *Entity) bool { return o != e && o.Active })
+	if len(neighbours) > maxNeighbours {
+		distance := func(o *Entity) float64 {
+			return s.w.GetVec2D(o, POSITION_).Sub(me.pos).Magnitude()
+		}
+		sort.Slice(neighbours, func(i, j int) bool {
+			return distance(neighbours[i]) < distance(neighbours[j])
+		})
+		neighbours = neighbours[:maxNeighbours]
+	}
+
+	lines := make([]orcaLine, 0, len(neighbours))
+	for _, o := range neighbours {
+		responsibility := 1.0
+		if s.w.EntityHasComponent(o, RVO_) {
+			responsibility = 0.5
+		}
+		lines = append(lines,
+			orcaLineFor(me, s.orcaAgent(o), timeHorizon, timeStep, responsibility))
+	}
+	return orcaVelocity(lines, me.vel, maxV)
+}
+
+func (s *RVOSystem) Expand(n int) {
+	// nil?
+}
@@ -0,0 +1,111 @@
+package sameriver
+
+import (
+	"testing"
+)
+
+func testingSpawnRVOAgent(w *World, pos, vel Vec2D, rvo bool) *Entity {
+	components := map[ComponentID]any{
+		POSITION_:    pos,
+		VELOCITY_:    vel,
+		MAXVELOCITY_: 0.1,
+		BOX_:         Vec2D{10, 10},
+	}
+	if rvo {
+		components[RVO_] = map[string]float64{"radius": 5}
+	}
+	return w.Spawn(map[string]any{"components": components})
+}
+
+func TestORCAVelocityNoNeighbours(t *testing.T) {
+	v := orcaVelocity(nil, Vec2D{3, 4}, 10)
+	if v != (Vec2D{3, 4}) {
+		t.Fatalf("with no constraints, should keep preferred velocity, got %v", v)
+	}
+	v = orcaVelocity(nil, Vec2D{30, 40}, 10)
+	if v.Sub(Vec2D{6, 8}).Magnitude() > 1e-9 {
+		t.Fatalf("should clamp to max speed, got %v", v)
+	}
+}
+
+func TestORCALineHeadOn(t *testing.T) {
+	a := orcaAgent{pos: Vec2D{0, 0}, vel: Vec2D{1, 0}, radius: 1}
+	b := orcaAgent{pos: Vec2D{10, 0}, vel: Vec2D{-1, 0}, radius: 1}
+	line := orcaLineFor(a, b, 10, 1, 0.5)
+	v := orcaVelocity([]orcaLine{line}, a.vel, 1)
+	if v == a.vel {
+		t.Fatal("should change velocity to avoid a head-on collision")
+	}
+	if v.Y == 0 {
+		t.Fatalf("should sidestep, got %v", v)
+	}
+}
+
+// run the system with a simple integration of positions, returning the
+// closest the two entities came
+func testingRunRVO(w *World, s *RVOSystem, a, b *Entity, steps int) float64 {
+	closest := w.GetVec2D(a, POSITION_).Sub(*w.GetVec2D(b, POSITION_)).Magnitude()
+	for i := 0; i < steps; i++ {
+		w.SpatialHasher.Update()
+		s.Update(FRAME_MS)
+		for _, e := range []*Entity{a, b} {
+			pos := w.GetVec2D(e, POSITION_)
+			*pos = pos.Add(w.GetVec2D(e, VELOCITY_).Scale(FRAME_MS))
+		}
+		d := w.GetVec2D(a, POSITION_).Sub(*w.GetVec2D(b, POSITION_)).Magnitude()
+		if d < closest {
+			closest = d
+		}
+	}
+	return closest
+}
+
+func TestRVOSystemHeadOn(t *testing.T) {
+	w := testingWorld()
+	s := NewRVOSystem()
+	w.RegisterSystems(s)
+	a := testingSpawnRVOAgent(w, Vec2D{400, 500}, Vec2D{0.1, 0}, true)
+	b := testingSpawnRVOAgent(w, Vec2D{600, 500}, Vec2D{-0.1, 0}, true)
+	closest := 1e9
+	// keep each preferring to go straight through the other
+	for i := 0; i < 200; i++ {
+		*w.GetVec2D(a, VELOCITY_) = Vec2D{0.1, 0}
+		*w.GetVec2D(b, VELOCITY_) = Vec2D{-0.1, 0}
+		d := testingRunRVO(w, s, a, b, 1)
+		if d < closest {
+			closest = d
+		}
+	}
+	if closest < 10-0.5 {
+		t.Fatalf("agents overlapped, came within %f", closest)
+	}
+	if w.GetVec2D(a, POSITION_).X <= w.GetVec2D(b, POSITION_).X {
+		t.Fatal("agents should have passed each other")
+	}
+	for _, e := range []*Entity{a, b} {
+		if w.GetVec2D(e, VELOCITY_).Magnitude() > 0.1+1e-9 {
+			t.Fatal("should respect MAXVELOCITY")
+		}
+	}
+}
+
+func TestRVOSystemOptIn(t *testing.T) {
+	w := testingWorld()
+	s := NewRVOSystem()
+	w.RegisterSystems(s)
+	agent := testingSpawnRVOAgent(w, Vec2D{400, 500}, Vec2D{0.1, 0}, true)
+	other := testingSpawnRVOAgent(w, Vec2D{430, 500}, Vec2D{-0.1, 0}, false)
+	w.SpatialHasher.Update()
+	s.Update(FRAME_MS)
+	if *w.GetVec2D(other, VELOCITY_) != (Vec2D{-0.1, 0}) {
+		t.Fatal("entity without RVO should not be adjusted")
+	}
+	if *w.GetVec2D(agent, VELOCITY_) == (Vec2D{0.1, 0}) {
+		t.Fatal("agent should avoid the non-RVO entity")
+	}
+	// the agent takes full responsibility for avoiding the other
+	closest := testingRunRVO(w, s, agent, other, 60)
+	if closest < 10-0.5 {
+		t.Fatalf("agent collided with non-RVO entity, came within %f", closest)
+	}
+}
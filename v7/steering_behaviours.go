@@ -0,0 +1,375 @@
+package sameriver
+
+import (
+	"math"
+	"math/rand"
+)
+
+// A SteeringBehaviour returns the steering force it wants applied to the
+// entity this frame (before weighting)
+type SteeringBehaviour func(s *SteeringSystem, e *Entity) Vec2D
+
+// the behaviours available by name in STEERWEIGHTS
+var STEERING_BEHAVIOURS = map[string]SteeringBehaviour{
+	"seek":       steerSeek,
+	"flee":       steerFlee,
+	"arrive":     steerArrive,
+	"pursue":     steerPursue,
+	"evade":      steerEvade,
+	"wander":     steerWander,
+	"avoid":      steerAvoid,
+	"wallfollow": steerWallFollow,
+	"separation": steerSeparation,
+	"alignment":  steerAlignment,
+	"cohesion":   steerCohesion,
+	"followpath": steerFollowPath,
+}
+
+// the behaviours of entities without a STEERWEIGHTS component
+var STEERING_DEFAULT_WEIGHTS = map[string]float64{
+	"arrive": 1,
+}
+
+// the tuning params used when an entity's STEERPARAMS doesn't set them
+var STEERING_DEFAULT_PARAMS = map[string]float64{
+	// the most force that can be applied per update
+	"maxForce": 3,
+	// nonzero to blend behaviours by priority instead of weighted sum
+	"prioritised": 0,
+	// distance from the target at which arrive begins to slow down
+	"slowingRadius": 30,
+	// flee and evade only act within this distance (0 for always)
+	"panicDistance": 0,
+	// the wander circle's distance ahead of the entity, radius, and the
+	// most its angle can change per update (radians)
+	"wanderDistance": 20,
+	"wanderRadius":   10,
+	"wanderJitter":   0.5,
+	// how far ahead to look for obstacles
+	"avoidDistance": 30,
+	// how far from a wall to keep while following it, and the distance
+	// within which a wall will be followed
+	"wallDistance": 10,
+	"wallRange":    30,
+	// flocking neighbourhood radii
+	"separationRadius": 15,
+	"neighbourRadius":  30,
+	// how far off the path we can drift, and how far ahead on it to aim
+	"pathRadius":    5,
+	"pathLookAhead": 10,
+}
+
+// SteeringWall is a line segment for the "wallfollow" behaviour
+type SteeringWall struct {
+	A Vec2D
+	B Vec2D
+}
+
+// AddWallsFromPolygon adds each side of the polygon as a wall
+func (s *SteeringSystem) AddWallsFromPolygon(p Polygon) {
+	for i := range p.Vertices {
+		s.Walls = append(s.Walls, SteeringWall{
+			A: p.Vertices[i],
+			B: p.Vertices[(i+1)%len(p.Vertices)],
+		})
+	}
+}
+
+// the force needed to change the entity's velocity to desired
+func (s *SteeringSystem) steerToward(e *Entity, desired Vec2D) Vec2D {
+	return desired.Sub(*s.w.GetVec2D(e, VELOCITY_))
+}
+
+// the unit vector from a to b, or zero if they're the same point
+func steeringDirection(a, b Vec2D) Vec2D {
+	d := b.Sub(a)
+	if d.Magnitude() == 0 {
+		return Vec2D{0, 0}
+	}
+	return d.Unit()
+}
+
+func (s *SteeringSystem) SeekForce(e *Entity, target Vec2D) Vec2D {
+	pos := s.w.GetVec2D(e, POSITION_)
+	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+	return s.steerToward(e, steeringDirection(*pos, target).Scale(*maxV))
+}
+
+func (s *SteeringSystem) FleeForce(e *Entity, threat Vec2D) Vec2D {
+	pos := s.w.GetVec2D(e, POSITION_)
+	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+	panicDistance := s.Param(e, "panicDistance")
+	if panicDistance > 0 && threat.Sub(*pos).Magnitude() > panicDistance {
+		return Vec2D{0, 0}
+	}
+	return s.steerToward(e, steeringDirection(threat, *pos).Scale(*maxV))
+}
+
+func (s *SteeringSystem) ArriveForce(e *Entity, target Vec2D) Vec2D {
+	pos := s.w.GetVec2D(e, POSITION_)
+	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+	distance := target.Sub(*pos).Magnitude()
+	slowingRadius := s.Param(e, "slowingRadius")
+	desired := steeringDirection(*pos, target)
+	if slowingRadius > 0 && distance <= slowingRadius {
+		desired = desired.Scale(*maxV * distance / slowingRadius)
+	} else {
+		desired = desired.Scale(*maxV)
+	}
+	return s.steerToward(e, desired)
+}
+
+// where the entity's STEERTARGETENTITY will be by the time we could reach
+// it, or false if it has none (or it's gone)
+func (s *SteeringSystem) predictTarget(e *Entity) (Vec2D, bool) {
+	if !s.w.EntityHasComponent(e, STEERTARGETENTITY_) {
+		return Vec2D{}, false
+	}
+	target := s.w.GetEntity(*s.w.GetInt(e, STEERTARGETENTITY_))
+	if target == nil || !target.Active || target == e {
+		return Vec2D{}, false
+	}
+	pos := s.w.GetVec2D(e, POSITION_)
+	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+	targetPos := *s.w.GetVec2D(target, POSITION_)
+	if !s.w.EntityHasComponent(target, VELOCITY_) || *maxV == 0 {
+		return targetPos, true
+	}
+	// look ahead in proportion to how long it'd take us to get there
+	lookAhead := targetPos.Sub(*pos).Magnitude() / *maxV
+	return targetPos.Add(s.w.GetVec2D(target, VELOCITY_).Scale(lookAhead)), true
+}
+
+func steerSeek(s *SteeringSystem, e *Entity) Vec2D {
+	return s.SeekForce(e, *s.w.GetVec2D(e, MOVEMENTTARGET_))
+}
+
+func steerFlee(s *SteeringSystem, e *Entity) Vec2D {
+	return s.FleeForce(e, *s.w.GetVec2D(e, MOVEMENTTARGET_))
+}
+
+func steerArrive(s *SteeringSystem, e *Entity) Vec2D {
+	return s.ArriveForce(e, *s.w.GetVec2D(e, MOVEMENTTARGET_))
+}
+
+// seek where the STEERTARGETENTITY is heading
+func steerPursue(s *SteeringSystem, e *Entity) Vec2D {
+	predicted, ok := s.predictTarget(e)
+	if !ok {
+		return Vec2D{0, 0}
+	}
+	return s.SeekForce(e, predicted)
+}
+
+// flee where the STEERTARGETENTITY is heading
+func steerEvade(s *SteeringSystem, e *Entity) Vec2D {
+	predicted, ok := s.predictTarget(e)
+	if !ok {
+		return Vec2D{0, 0}
+	}
+	return s.FleeForce(e, predicted)
+}
+
+// seek a point on a circle ahead of the entity, which drifts randomly
+// around the circle each update
+func steerWander(s *SteeringSystem, e *Entity) Vec2D {
+	pos := s.w.GetVec2D(e, POSITION_)
+	v := s.w.GetVec2D(e, VELOCITY_)
+	jitter := s.Param(e, "wanderJitter")
+	angle := s.wanderAngles[e] + (rand.Float64()*2-1)*jitter
+	s.wanderAngles[e] = angle
+	heading := Vec2D{1, 0}
+	if v.Magnitude() > 0 {
+		heading = v.Unit()
+	}
+	center := pos.Add(heading.Scale(s.Param(e, "wanderDistance")))
+	r := s.Param(e, "wanderRadius")
+	return s.SeekForce(e, center.Add(Vec2D{r * math.Cos(angle), r * math.Sin(angle)}))
+}
+
+// steer sideways away from the nearest entity in the spatial hash which
+// lies ahead within avoidDistance, harder the closer it is
+func steerAvoid(s *SteeringSystem, e *Entity) Vec2D {
+	pos := *s.w.GetVec2D(e, POSITION_)
+	v := s.w.GetVec2D(e, VELOCITY_)
+	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+	if v.Magnitude() == 0 {
+		return Vec2D{0, 0}
+	}
+	lookAhead := s.Param(e, "avoidDistance")
+	heading := v.Unit()
+	side := heading.PerpendicularUnit()
+	box := Vec2D{0, 0}
+	if s.w.EntityHasComponent(e, BOX_) {
+		box = *s.w.GetVec2D(e, BOX_)
+	}
+	ownRadius := box.Magnitude() / 2
+
+	nearest := math.Inf(+1)
+	force := Vec2D{0, 0}
+	for _, o := range s.w.SpatialHasher.EntitiesWithinDistance(pos, box, lookAhead) {
+		if o == e || !o.Active {
+			continue
+		}
+		local := s.w.GetVec2D(o, POSITION_).Sub(pos)
+		ahead := local.Dot(heading)
+		if ahead <= 0 || ahead > lookAhead+ownRadius || ahead >= nearest {
+			continue
+		}
+		radius := ownRadius + s.w.GetVec2D(o, BOX_).Magnitude()/2
+		lateral := local.Dot(side)
+		if math.Abs(lateral) >= radius {
+			continue
+		}
+		nearest = ahead
+		away := side
+		if lateral > 0 {
+			away = side.Scale(-1)
+		}
+		urgency := 1 - ahead/(lookAhead+ownRadius)
+		force = away.Scale(*maxV * (1 + urgency))
+	}
+	return force
+}
+
+// steer along the nearest wall within wallRange, keeping wallDistance from
+// it on whichever side we're on
+func steerWallFollow(s *SteeringSystem, e *Entity) Vec2D {
+	pos := *s.w.GetVec2D(e, POSITION_)
+	v := s.w.GetVec2D(e, VELOCITY_)
+	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+	wallRange := s.Param(e, "wallRange")
+	nearest := math.Inf(+1)
+	var wall SteeringWall
+	var closest Vec2D
+	for _, candidate := range s.Walls {
+		p := closestPointOnLineSegment(pos, candidate.A, candidate.B)
+		d := p.Sub(pos).Magnitude()
+		if d < nearest && d <= wallRange {
+			nearest, wall, closest = d, candidate, p
+		}
+	}
+	if math.IsInf(nearest, +1) || wall.A == wall.B {
+		return Vec2D{0, 0}
+	}
+	// follow the wall in whichever direction we're already going
+	along := wall.B.Sub(wall.A).Unit()
+	if v.Dot(along) < 0 {
+		along = along.Scale(-1)
+	}
+	// away from the wall, toward our side of it
+	away := steeringDirection(closest, pos)
+	if away == (Vec2D{0, 0}) {
+		away = along.PerpendicularUnit()
+	}
+	wallDistance := s.Param(e, "wallDistance")
+	// with no distance to keep, just follow along the wall
+	correction := 0.0
+	if wallDistance > 0 {
+		correction = (wallDistance - nearest) / wallDistance
+	}
+	desired := along.Add(away.Scale(correction)).Unit().Scale(*maxV)
+	return s.steerToward(e, desired)
+}
+
+// neighbours for flocking: other active entities with a VELOCITY in the
+// spatial hash within radius
+func (s *SteeringSystem) neighbours(e *Entity, radius float64) []*Entity {
+	pos := *s.w.GetVec2D(e, POSITION_)
+	box := Vec2D{0, 0}
+	if s.w.EntityHasComponent(e, BOX_) {
+		box = *s.w.GetVec2D(e, BOX_)
+	}
+	return s.w.SpatialHasher.EntitiesWithinDistanceFilter(pos, box, radius,
+		func(o *Entity) bool {
+			return o != e && o.Active && s.w.EntityHasComponent(o, VELOCITY_)
+		})
+}
+
+// steer away from neighbours within separationRadius, harder the closer
+// they are
+func steerSeparation(s *SteeringSystem, e *Entity) Vec2D {
+	pos := *s.w.GetVec2D(e, POSITION_)
+	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+	push := Vec2D{0, 0}
+	for _, o := range s.neighbours(e, s.Param(e, "separationRadius")) {
+		away := pos.Sub(*s.w.GetVec2D(o, POSITION_))
+		d := away.Magnitude()
+		if d == 0 {
+			push = push.Add(RandomUnitVec2D())
+			continue
+		}
+		push = push.Add(away.Scale(1 / (d * d)))
+	}
+	if push.Magnitude() == 0 {
+		return Vec2D{0, 0}
+	}
+	return s.steerToward(e, push.Unit().Scale(*maxV))
+}
+
+// match the average velocity of neighbours within neighbourRadius
+func steerAlignment(s *SteeringSystem, e *Entity) Vec2D {
+	neighbours := s.neighbours(e, s.Param(e, "neighbourRadius"))
+	if len(neighbours) == 0 {
+		return Vec2D{0, 0}
+	}
+	avg := Vec2D{0, 0}
+	for _, o := range neighbours {
+		avg = avg.Add(*s.w.GetVec2D(o, VELOCITY_))
+	}
+	return s.steerToward(e, avg.Scale(1/float64(len(neighbours))))
+}
+
+// seek the centre of neighbours within neighbourRadius
+func steerCohesion(s *SteeringSystem, e *Entity) Vec2D {
+	neighbours := s.neighbours(e, s.Param(e, "neighbourRadius"))
+	if len(neighbours) == 0 {
+		return Vec2D{0, 0}
+	}
+	center := Vec2D{0, 0}
+	for _, o := range neighbours {
+		center = center.Add(*s.w.GetVec2D(o, POSITION_))
+	}
+	return s.ArriveForce(e, center.Scale(1/float64(len(neighbours))))
+}
+
+// stay within pathRadius of the entity's PATH, moving along it: if our
+// predicted position drifts off the path, seek a point pathLookAhead
+// further along it
+func steerFollowPath(s *SteeringSystem, e *Entity) Vec2D {
+	if !s.w.EntityHasComponent(e, PATH_) {
+		return Vec2D{0, 0}
+	}
+	path := s.w.GetPath(e, PATH_)
+	remaining := path.Remaining()
+	if len(remaining) == 0 {
+		return Vec2D{0, 0}
+	}
+	pos := *s.w.GetVec2D(e, POSITION_)
+	if len(remaining) == 1 {
+		return s.ArriveForce(e, remaining[0])
+	}
+	v := s.w.GetVec2D(e, VELOCITY_)
+	predicted := pos.Add(*v)
+	// find the nearest point on the remaining segments to our predicted
+	// position
+	nearest := math.Inf(+1)
+	var onPath Vec2D
+	var segmentDir Vec2D
+	for i := 0; i < len(remaining)-1; i++ {
+		p := closestPointOnLineSegment(predicted, remaining[i], remaining[i+1])
+		d := p.Sub(predicted).Magnitude()
+		if d < nearest {
+			nearest = d
+			onPath = p
+			segmentDir = steeringDirection(remaining[i], remaining[i+1])
+		}
+	}
+	if onPath == remaining[len(remaining)-1] {
+		return s.ArriveForce(e, onPath)
+	}
+	if nearest <= s.Param(e, "pathRadius") && v.Magnitude() > 0 {
+		return Vec2D{0, 0}
+	}
+	return s.SeekForce(e, onPath.Add(segmentDir.Scale(s.Param(e, "pathLookAhead"))))
+}
@@ -0,0 +1,236 @@
+package sameriver
+
+import (
+	"math"
+	"testing"
+)
+
+func testingSpawnSteerer(w *World, pos Vec2D, weights map[string]float64, params map[string]float64) *Entity {
+	components := map[ComponentID]any{
+		POSITION_:       pos,
+		VELOCITY_:       Vec2D{0, 0},
+		ACCELERATION_:   Vec2D{0, 0},
+		MAXVELOCITY_:    3.0,
+		MOVEMENTTARGET_: pos,
+		STEER_:          Vec2D{0, 0},
+		MASS_:           1.0,
+		BOX_:            Vec2D{2, 2},
+	}
+	if weights != nil {
+		components[STEERWEIGHTS_] = weights
+	}
+	if params != nil {
+		components[STEERPARAMS_] = params
+	}
+	return w.Spawn(map[string]any{"components": components})
+}
+
+func testingSteeringWorld() (*World, *SteeringSystem) {
+	w := testingWorld()
+	ss := NewSteeringSystem()
+	w.RegisterSystems(ss)
+	return w, ss
+}
+
+func TestSteeringDefaultArrive(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	e := testingSpawnSteerer(w, Vec2D{100, 100}, nil, nil)
+	*w.GetVec2D(e, MOVEMENTTARGET_) = Vec2D{200, 100}
+	ss.Steer(e)
+	if w.GetVec2D(e, STEER_).X <= 0 {
+		t.Fatal("entity without STEERWEIGHTS should arrive at MOVEMENTTARGET")
+	}
+	// within the slowing radius, the desired speed is reduced
+	*w.GetVec2D(e, STEER_) = Vec2D{0, 0}
+	*w.GetVec2D(e, MOVEMENTTARGET_) = Vec2D{110, 100}
+	near := ss.ArriveForce(e, Vec2D{110, 100})
+	far := ss.ArriveForce(e, Vec2D{200, 100})
+	if near.Magnitude() >= far.Magnitude() {
+		t.Fatal("arrive should want less speed near the target")
+	}
+	// on the target with no slowing radius, no force (rather than NaN)
+	stopped := testingSpawnSteerer(w, Vec2D{100, 100}, nil, map[string]float64{"slowingRadius": 0})
+	if f := ss.ArriveForce(stopped, Vec2D{100, 100}); f != (Vec2D{0, 0}) {
+		t.Fatalf("arrive on the target should be no force, got %v", f)
+	}
+}
+
+func TestSteeringFlee(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	e := testingSpawnSteerer(w, Vec2D{100, 100},
+		map[string]float64{"flee": 1},
+		map[string]float64{"panicDistance": 50})
+	*w.GetVec2D(e, MOVEMENTTARGET_) = Vec2D{120, 100}
+	if steerFlee(ss, e).X >= 0 {
+		t.Fatal("flee should steer away from the threat")
+	}
+	*w.GetVec2D(e, MOVEMENTTARGET_) = Vec2D{300, 100}
+	if steerFlee(ss, e) != (Vec2D{0, 0}) {
+		t.Fatal("flee should ignore threats beyond panicDistance")
+	}
+}
+
+func TestSteeringPursueEvade(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	target := testingSpawnSteerer(w, Vec2D{200, 100}, nil, nil)
+	*w.GetVec2D(target, VELOCITY_) = Vec2D{0, 3}
+	pursuer := testingSpawnSteerer(w, Vec2D{100, 100}, map[string]float64{"pursue": 1}, nil)
+	w.Em.ApplyComponentSet(pursuer, map[ComponentID]any{STEERTARGETENTITY_: target.ID})
+	force := steerPursue(ss, pursuer)
+	if force.X <= 0 || force.Y <= 0 {
+		t.Fatalf("pursue should lead the target, got %v", force)
+	}
+	force = steerEvade(ss, pursuer)
+	if force.X >= 0 || force.Y >= 0 {
+		t.Fatalf("evade should flee the target's predicted position, got %v", force)
+	}
+	w.Despawn(target)
+	if steerPursue(ss, pursuer) != (Vec2D{0, 0}) {
+		t.Fatal("pursue of a despawned entity should do nothing")
+	}
+}
+
+func TestSteeringWander(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	e := testingSpawnSteerer(w, Vec2D{100, 100}, map[string]float64{"wander": 1}, nil)
+	if steerWander(ss, e).Magnitude() == 0 {
+		t.Fatal("wander should steer")
+	}
+	if _, ok := ss.wanderAngles[e]; !ok {
+		t.Fatal("wander should keep its angle between updates")
+	}
+	w.Despawn(e)
+	if _, ok := ss.wanderAngles[e]; ok {
+		t.Fatal("wander state should be forgotten on despawn")
+	}
+}
+
+func TestSteeringAvoid(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	e := testingSpawnSteerer(w, Vec2D{100, 100}, map[string]float64{"avoid": 1}, nil)
+	*w.GetVec2D(e, VELOCITY_) = Vec2D{3, 0}
+	obstacle := testingSpawnSteerer(w, Vec2D{115, 101}, nil, nil)
+	*w.GetVec2D(obstacle, BOX_) = Vec2D{10, 10}
+	w.Update(FRAME_MS / 2)
+	*w.GetVec2D(e, POSITION_) = Vec2D{100, 100}
+	*w.GetVec2D(e, VELOCITY_) = Vec2D{3, 0}
+	force := steerAvoid(ss, e)
+	if force.Y >= 0 {
+		t.Fatalf("should steer away (down) from an obstacle ahead and above, got %v", force)
+	}
+	// obstacles behind are ignored
+	*w.GetVec2D(e, VELOCITY_) = Vec2D{-3, 0}
+	if steerAvoid(ss, e) != (Vec2D{0, 0}) {
+		t.Fatal("should ignore obstacles behind")
+	}
+}
+
+func TestSteeringFlocking(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	e := testingSpawnSteerer(w, Vec2D{100, 100}, nil, nil)
+	a := testingSpawnSteerer(w, Vec2D{108, 100}, nil, nil)
+	b := testingSpawnSteerer(w, Vec2D{108, 110}, nil, nil)
+	*w.GetVec2D(a, VELOCITY_) = Vec2D{0, 2}
+	*w.GetVec2D(b, VELOCITY_) = Vec2D{0, 2}
+	w.SpatialHasher.Update()
+	if steerSeparation(ss, e).X >= 0 {
+		t.Fatal("separation should push away from close neighbours")
+	}
+	if alignment := steerAlignment(ss, e); alignment.Y <= 0 {
+		t.Fatalf("alignment should match neighbours' velocity, got %v", alignment)
+	}
+	if cohesion := steerCohesion(ss, e); cohesion.X <= 0 || cohesion.Y <= 0 {
+		t.Fatalf("cohesion should steer toward neighbours' centre, got %v", cohesion)
+	}
+}
+
+func TestSteeringWallFollow(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	ss.AddWallsFromPolygon(Polygon{Vertices: []Vec2D{{0, 50}, {500, 50}, {500, 60}, {0, 60}}})
+	e := testingSpawnSteerer(w, Vec2D{100, 45}, map[string]float64{"wallfollow": 1}, nil)
+	*w.GetVec2D(e, VELOCITY_) = Vec2D{1, 0}
+	force := steerWallFollow(ss, e)
+	if force.X <= 0 {
+		t.Fatalf("should keep moving along the wall, got %v", force)
+	}
+	if force.Y >= 0 {
+		t.Fatalf("should move out to wallDistance from the wall, got %v", force)
+	}
+	*w.GetVec2D(e, POSITION_) = Vec2D{100, 0}
+	if steerWallFollow(ss, e) != (Vec2D{0, 0}) {
+		t.Fatal("should ignore walls out of range")
+	}
+	hugger := testingSpawnSteerer(w, Vec2D{100, 45}, map[string]float64{"wallfollow": 1},
+		map[string]float64{"wallDistance": 0})
+	*w.GetVec2D(hugger, VELOCITY_) = Vec2D{1, 0}
+	force = steerWallFollow(ss, hugger)
+	if math.IsNaN(force.X) || math.IsNaN(force.Y) || force.X <= 0 {
+		t.Fatalf("should follow along the wall with a wallDistance of 0, got %v", force)
+	}
+}
+
+func TestSteeringFollowPath(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	w.RegisterComponents([]any{PATH_, PATH, "PATH"})
+	e := testingSpawnSteerer(w, Vec2D{0, 20}, map[string]float64{"followpath": 1}, nil)
+	w.Em.ApplyComponentSet(e, map[ComponentID]any{
+		PATH_: NewPath([]Vec2D{{0, 0}, {100, 0}, {100, 100}}, 1),
+	})
+	*w.GetVec2D(e, VELOCITY_) = Vec2D{1, 0}
+	force := steerFollowPath(ss, e)
+	if force.Y >= 0 {
+		t.Fatalf("should steer back onto the path, got %v", force)
+	}
+	*w.GetVec2D(e, POSITION_) = Vec2D{50, 0}
+	if steerFollowPath(ss, e) != (Vec2D{0, 0}) {
+		t.Fatal("should not steer while on the path")
+	}
+}
+
+func TestSteeringParams(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	e := testingSpawnSteerer(w, Vec2D{100, 100}, nil, map[string]float64{"maxForce": 0.5})
+	if ss.Param(e, "maxForce") != 0.5 {
+		t.Fatal("should read params from STEERPARAMS")
+	}
+	if ss.Param(e, "slowingRadius") != STEERING_DEFAULT_PARAMS["slowingRadius"] {
+		t.Fatal("should fall back to default params")
+	}
+	*w.GetVec2D(e, MOVEMENTTARGET_) = Vec2D{500, 100}
+	ss.Steer(e)
+	ss.Apply(e)
+	if w.GetVec2D(e, VELOCITY_).Magnitude() > 0.5+1e-9 {
+		t.Fatal("steering force should be truncated to the entity's maxForce")
+	}
+}
+
+func TestSteeringPrioritised(t *testing.T) {
+	w, ss := testingSteeringWorld()
+	ss.RegisterBehaviour("left", func(s *SteeringSystem, e *Entity) Vec2D {
+		return Vec2D{-3, 0}
+	})
+	ss.RegisterBehaviour("up", func(s *SteeringSystem, e *Entity) Vec2D {
+		return Vec2D{0, 3}
+	})
+	weights := map[string]float64{"left": 2, "up": 1}
+	summed := testingSpawnSteerer(w, Vec2D{100, 100}, weights, nil)
+	prioritised := testingSpawnSteerer(w, Vec2D{100, 100}, weights,
+		map[string]float64{"prioritised": 1})
+	ss.Steer(summed)
+	ss.Steer(prioritised)
+	if *w.GetVec2D(summed, STEER_) != (Vec2D{-6, 3}) {
+		t.Fatalf("weighted sum should blend all behaviours, got %v", *w.GetVec2D(summed, STEER_))
+	}
+	// unknown behaviours are ignored rather than panicking
+	unknown := testingSpawnSteerer(w, Vec2D{100, 100},
+		map[string]float64{"left": 1, "nope": 1}, nil)
+	ss.Steer(unknown)
+	if *w.GetVec2D(unknown, STEER_) != (Vec2D{-3, 0}) {
+		t.Fatalf("should ignore the unknown behaviour, got %v", *w.GetVec2D(unknown, STEER_))
+	}
+	// "left" alone uses up the maxForce of 3, so "up" gets nothing
+	if *w.GetVec2D(prioritised, STEER_) != (Vec2D{-3, 0}) {
+		t.Fatalf("prioritised should stop once maxForce is used, got %v",
+			*w.GetVec2D(prioritised, STEER_))
+	}
+}
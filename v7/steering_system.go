@@ -1,12 +1,43 @@
 package sameriver
 
+import (
+	"sort"
+)
+
+// SteeringSystem moves entities toward their MOVEMENTTARGET (and around
+// each other, obstacles, walls...) by blending steering behaviours into a
+// STEER force which is then applied to VELOCITY.
+//
+// Which behaviours an entity uses is set by its optional STEERWEIGHTS
+// component (behaviour name -> weight, see STEERING_BEHAVIOURS). Entities
+// without one just arrive at their MOVEMENTTARGET. Tuning parameters are
+// read from the optional STEERPARAMS component, falling back to
+// STEERING_DEFAULT_PARAMS.
 type SteeringSystem struct {
 	w                *World
 	movementEntities *UpdatedEntityList
+	// behaviours available to STEERWEIGHTS, by name
+	behaviours map[string]SteeringBehaviour
+	// walls for the "wallfollow" behaviour
+	Walls []SteeringWall
+	// per-entity state of the "wander" behaviour
+	wanderAngles map[*Entity]float64
+	// STEERWEIGHTS naming behaviours that don't exist are warned of
+	logUnknownBehaviour PrintfLike
 }
 
 func NewSteeringSystem() *SteeringSystem {
-	return &SteeringSystem{}
+	s := &SteeringSystem{
+		behaviours:   make(map[string]SteeringBehaviour),
+		Walls:        make([]SteeringWall, 0),
+		wanderAngles: make(map[*Entity]float64),
+
+		logUnknownBehaviour: logWarningRateLimited(10 * 1000),
+	}
+	for name, b := range STEERING_BEHAVIOURS {
+		s.behaviours[name] = b
+	}
+	return s
 }
 
 func (s *SteeringSystem) GetComponentDeps() []any {
@@ -18,6 +49,9 @@ func (s *SteeringSystem) GetComponentDeps() []any {
 		MOVEMENTTARGET_, VEC2D, "MOVEMENTTARGET",
 		STEER_, VEC2D, "STEER",
 		MASS_, FLOAT64, "MASS",
+		STEERWEIGHTS_, FLOATMAP, "STEERWEIGHTS",
+		STEERPARAMS_, FLOATMAP, "STEERPARAMS",
+		STEERTARGETENTITY_, INT, "STEERTARGETENTITY",
 	}
 }
 
@@ -31,44 +65,97 @@ func (s *SteeringSystem) LinkWorld(w *World) {
 					POSITION_, VELOCITY_, ACCELERATION_,
 					MAXVELOCITY_, MOVEMENTTARGET_, STEER_, MASS_,
 				})))
+	w.AddDespawnCallback(func(e *Entity) {
+		delete(s.wanderAngles, e)
+	})
 }
 
 func (s *SteeringSystem) Update(dt_ms float64) {
 	for _, e := range s.movementEntities.entities {
-		s.Seek(e)
+		s.Steer(e)
 		s.Apply(e)
 	}
 }
 
-func (s *SteeringSystem) Seek(e *Entity) {
-	p0 := s.w.GetVec2D(e, POSITION_)
-	p1 := s.w.GetVec2D(e, MOVEMENTTARGET_)
-	v := s.w.GetVec2D(e, VELOCITY_)
-	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
+// RegisterBehaviour makes a custom behaviour available to STEERWEIGHTS
+func (s *SteeringSystem) RegisterBehaviour(name string, b SteeringBehaviour) {
+	s.behaviours[name] = b
+}
+
+// Param returns the entity's STEERPARAMS value for key, or the default
+func (s *SteeringSystem) Param(e *Entity, key string) float64 {
+	if s.w.EntityHasComponent(e, STEERPARAMS_) {
+		params := s.w.GetFloatMap(e, STEERPARAMS_)
+		if params.Has(key) {
+			return params.Get(key)
+		}
+	}
+	return STEERING_DEFAULT_PARAMS[key]
+}
+
+// Steer adds to STEER the blend of the entity's steering behaviours.
+//
+// By default the weighted forces are summed. If the entity's "prioritised"
+// param is nonzero, behaviours are instead taken in order of descending
+// weight, each adding its weighted force until the entity's maxForce is
+// used up, so that (eg.) obstacle avoidance can't be drowned out by seek.
+func (s *SteeringSystem) Steer(e *Entity) {
 	st := s.w.GetVec2D(e, STEER_)
-	desired := p1.Sub(*p0)
-	distance := desired.Magnitude()
-	desired = desired.Unit()
-	// do slowing for arrival behavior
-	// TODO: define this properly
-	slowingRadius := 30.0
-	if distance <= slowingRadius {
-		desired = desired.Scale(*maxV * distance / slowingRadius)
+	var weights map[string]float64
+	if s.w.EntityHasComponent(e, STEERWEIGHTS_) {
+		weights = s.w.GetFloatMap(e, STEERWEIGHTS_).M
 	} else {
-		desired = desired.Scale(*maxV)
+		weights = STEERING_DEFAULT_WEIGHTS
+	}
+	// in a fixed order, since the sum of the forces depends on it
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		if _, ok := s.behaviours[name]; !ok {
+			s.logUnknownBehaviour("entity %d's STEERWEIGHTS name unknown steering behaviour %s; ignoring it", e.ID, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	if s.Param(e, "prioritised") == 0 {
+		sort.Strings(names)
+		for _, name := range names {
+			st.Inc(s.behaviours[name](s, e).Scale(weights[name]))
+		}
+		return
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if weights[names[i]] != weights[names[j]] {
+			return weights[names[i]] > weights[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	maxForce := s.Param(e, "maxForce")
+	total := Vec2D{0, 0}
+	for _, name := range names {
+		remaining := maxForce - total.Magnitude()
+		if remaining <= 0 {
+			break
+		}
+		force := s.behaviours[name](s, e).Scale(weights[name])
+		total = total.Add(force.Truncate(remaining))
 	}
-	force := desired.Sub(*v)
-	st.Inc(force)
+	st.Inc(total)
+}
+
+// Seek adds to STEER the force to arrive at MOVEMENTTARGET
+func (s *SteeringSystem) Seek(e *Entity) {
+	st := s.w.GetVec2D(e, STEER_)
+	st.Inc(s.ArriveForce(e, *s.w.GetVec2D(e, MOVEMENTTARGET_)))
 }
 
+// Apply truncates STEER to the entity's maxForce and applies it (scaled by
+// mass) to VELOCITY
 func (s *SteeringSystem) Apply(e *Entity) {
 	v := s.w.GetVec2D(e, VELOCITY_)
 	maxV := s.w.GetFloat64(e, MAXVELOCITY_)
 	st := s.w.GetVec2D(e, STEER_)
 	mass := s.w.GetFloat64(e, MASS_)
-	// TODO: define this properly
-	maxSteerForce := 3.0
-	*st = st.Truncate(maxSteerForce)
+	*st = st.Truncate(s.Param(e, "maxForce"))
 	*st = st.Scale(1 / *mass)
 	*v = v.Add(*st).Truncate(*maxV)
 }
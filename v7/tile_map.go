@@ -12,6 +12,8 @@ type TileMap struct {
 	Width  int32
 	Height int32
 	Tiles  [][]string
+	// called after each SetTile()
+	setTileCallbacks []func(x, y int32, kind string)
 }
 
 func NewTileMap(tm *TileManager, width, height int32) *TileMap {
@@ -47,6 +49,15 @@ func (tm *TileMap) DrawTiles(viewport *Viewport, window *sdl.Window) {
 
 func (tm *TileMap) SetTile(x, y int32, kind string) {
 	tm.Tiles[y][x] = kind
+	for _, f := range tm.setTileCallbacks {
+		f(x, y, kind)
+	}
+}
+
+// OnSetTile registers a function to be called whenever a tile is changed
+// with SetTile() (eg. to keep a PathGrid in sync with the map)
+func (tm *TileMap) OnSetTile(f func(x, y int32, kind string)) {
+	tm.setTileCallbacks = append(tm.setTileCallbacks, f)
 }
 
 func (tm *TileMap) Save(filename string) {
@@ -0,0 +1,372 @@
+package sameriver
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// UtilityConsideration scores one aspect of a situation for a
+// UtilityOption: Input reads a value for the entity (normalized to [0, 1],
+// see the Utility*Input() constructors) and Curve maps it to a score in
+// [0, 1] (eg. Curves.Decay(2) to lose interest in food as hunger falls)
+type UtilityConsideration struct {
+	Name  string
+	Input func(e *Entity) float64
+	// nil means Curves.Lin
+	Curve CurveFunc
+}
+
+func (c *UtilityConsideration) score(e *Entity) (input, output float64) {
+	input = c.Input(e)
+	if c.Curve == nil {
+		return input, Curves.Lin(input)
+	}
+	return input, c.Curve(input)
+}
+
+// NewUtilityConsideration creates a consideration mapping input through the
+// curve of the given name and params (see Curves.Named())
+func NewUtilityConsideration(
+	name string, input func(e *Entity) float64, curve string, params ...float64) (
+	*UtilityConsideration, error) {
+
+	f, err := Curves.Named(curve, params...)
+	if err != nil {
+		return nil, fmt.Errorf("consideration %s: %w", name, err)
+	}
+	return &UtilityConsideration{Name: name, Input: input, Curve: f}, nil
+}
+
+func utilityNormalize(x, min, max float64) float64 {
+	if max == min {
+		return 0
+	}
+	return (x - min) / (max - min)
+}
+
+// UtilityComponentInput reads an INT, FLOAT64 or BOOL component, mapping
+// [min, max] to [0, 1]
+func UtilityComponentInput(w *World, component ComponentID, min, max float64) func(e *Entity) float64 {
+	switch kind := w.Em.ComponentsTable.Kinds[component]; kind {
+	case INT:
+		return func(e *Entity) float64 {
+			return utilityNormalize(float64(*w.GetInt(e, component)), min, max)
+		}
+	case FLOAT64:
+		return func(e *Entity) float64 {
+			return utilityNormalize(*w.GetFloat64(e, component), min, max)
+		}
+	case BOOL:
+		return func(e *Entity) float64 {
+			return utilityNormalize(goapBoolVal(*w.GetBool(e, component)), min, max)
+		}
+	default:
+		panic(fmt.Sprintf("utility input can't read component %s of kind %d",
+			w.Em.ComponentsTable.Strings[component], kind))
+	}
+}
+
+// UtilityStateInput reads a key of the entity's STATE, mapping [min, max]
+// to [0, 1]
+func UtilityStateInput(w *World, key string, min, max float64) func(e *Entity) float64 {
+	return func(e *Entity) float64 {
+		return utilityNormalize(float64(w.GetIntMap(e, STATE_).Get(key)), min, max)
+	}
+}
+
+// UtilityMindInput reads an int, float64 or bool from the entity's mind
+// (missing is 0), mapping [min, max] to [0, 1]
+func UtilityMindInput(key string, min, max float64) func(e *Entity) float64 {
+	return func(e *Entity) float64 {
+		var x float64
+		switch v := e.Mind.Get(key).(type) {
+		case int:
+			x = float64(v)
+		case float64:
+			x = v
+		case bool:
+			x = goapBoolVal(v)
+		}
+		return utilityNormalize(x, min, max)
+	}
+}
+
+// UtilityEFDSLInput counts the entities matching an EFDSL expression
+// (resolved for the entity, so eg. "HasTag(wolf) && WithinDistance(self,
+// 100)" counts nearby wolves), mapping [0, max] to [0, 1]
+func UtilityEFDSLInput(w *World, expr string, max float64) (func(e *Entity) float64, error) {
+	q, err := w.EFDSL.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(e *Entity) float64 {
+		return utilityNormalize(float64(len(q.Filter(&EntityResolver{e: e, w: w}))), 0, max)
+	}, nil
+}
+
+// UtilityOption is something an entity could choose to do, scored by its
+// considerations
+type UtilityOption struct {
+	Name           string
+	Considerations []*UtilityConsideration
+	// multiplies the score; 0 means 1
+	Weight float64
+	// the goal to pursue when chosen, if handing over to a GOAPAgent (see
+	// UtilitySelector.BindGOAP())
+	Goal *GOAPAgentGoal
+}
+
+func (o *UtilityOption) weight() float64 {
+	if o.Weight == 0 {
+		return 1
+	}
+	return o.Weight
+}
+
+// UtilityScore is the last evaluation of an option, for inspecting why it
+// was (or wasn't) chosen
+type UtilityScore struct {
+	Option         *UtilityOption
+	Considerations []UtilityConsiderationScore
+	// the considerations' scores combined, times the weight
+	Raw float64
+	// Raw smoothed over time by the selector's Momentum_ms
+	Score float64
+}
+
+type UtilityConsiderationScore struct {
+	Name   string
+	Input  float64
+	Output float64
+}
+
+// the product of the considerations' scores, each compensated so that
+// options with more considerations aren't penalised just for having them
+// (the product of n scores of 0.9 shrinks as n grows)
+func (o *UtilityOption) evaluate(e *Entity, s *UtilityScore) {
+	s.Considerations = s.Considerations[:0]
+	modification := 0.0
+	if n := len(o.Considerations); n > 0 {
+		modification = 1 - 1/float64(n)
+	}
+	product := 1.0
+	for _, c := range o.Considerations {
+		input, output := c.score(e)
+		output = Curves.Clamped(output)
+		s.Considerations = append(s.Considerations, UtilityConsiderationScore{c.Name, input, output})
+		product *= output + (1-output)*modification*output
+	}
+	s.Raw = product * o.weight()
+}
+
+/*
+UtilitySelector chooses which of its options an entity pursues, by scoring
+them each update and choosing the best. To keep from dithering between
+options with similar scores:
+
+  - Hysteresis: another option must score more than the current one by this
+    much to replace it
+  - Momentum_ms: scores follow their raw values with this time constant
+    (0 for immediately), so brief spikes don't cause a switch
+
+An option scoring 0 is never chosen, and the current option is dropped as
+soon as it scores 0. The winner can be handed to a GOAPAgent (BindGOAP())
+or a BehaviourTree (BTSelector()), and Scores() or String() show why it won.
+*/
+type UtilitySelector struct {
+	w *World
+	e *Entity
+
+	Options     []*UtilityOption
+	Hysteresis  float64
+	Momentum_ms float64
+	// how often to evaluate the options; 0 means every update
+	Interval_ms float64
+	// called when the chosen option changes (either may be nil)
+	OnChange func(from, to *UtilityOption)
+
+	scores    []*UtilityScore
+	evaluated bool
+	current   *UtilityOption
+	// time since the last evaluation
+	elapsed_ms float64
+}
+
+// NewUtilitySelector creates a selector for e, running as an entity logic
+// named "utility-selector"
+func NewUtilitySelector(e *Entity, w *World, options ...*UtilityOption) *UtilitySelector {
+	s := &UtilitySelector{
+		w:       w,
+		e:       e,
+		Options: options,
+	}
+	w.AddEntityLogic(e, "utility-selector", s.Update)
+	return s
+}
+
+// Update evaluates the options (if Interval_ms has passed) and chooses one
+func (s *UtilitySelector) Update(dt_ms float64) {
+	if s.e.Despawned {
+		return
+	}
+	s.elapsed_ms += dt_ms
+	if s.evaluated && s.elapsed_ms < s.Interval_ms {
+		return
+	}
+	s.Evaluate(s.elapsed_ms)
+	s.elapsed_ms = 0
+}
+
+// Evaluate scores the options now, as though dt_ms has passed since they
+// were last scored, and chooses one
+func (s *UtilitySelector) Evaluate(dt_ms float64) {
+	for len(s.scores) < len(s.Options) {
+		s.scores = append(s.scores, &UtilityScore{})
+	}
+	s.scores = s.scores[:len(s.Options)]
+	alpha := 1.0
+	if s.evaluated && s.Momentum_ms > 0 {
+		alpha = 1 - math.Exp(-dt_ms/s.Momentum_ms)
+	}
+	for i, o := range s.Options {
+		score := s.scores[i]
+		fresh := score.Option != o
+		score.Option = o
+		o.evaluate(s.e, score)
+		if fresh {
+			score.Score = score.Raw
+		} else {
+			score.Score += (score.Raw - score.Score) * alpha
+		}
+	}
+	s.evaluated = true
+	s.choose()
+}
+
+func (s *UtilitySelector) choose() {
+	var best *UtilityScore
+	for _, score := range s.scores {
+		if score.Score > 0 && (best == nil || score.Score > best.Score) {
+			best = score
+		}
+	}
+	next := (*UtilityOption)(nil)
+	if best != nil {
+		next = best.Option
+	}
+	if current := s.scoreOf(s.current); current != nil && current.Score > 0 &&
+		best != nil && best.Score <= current.Score+s.Hysteresis {
+		next = s.current
+	}
+	if next == s.current {
+		return
+	}
+	from := s.current
+	s.current = next
+	logUtilityDebug("entity %d switching from %s to %s", s.e.ID, from.displayName(), next.displayName())
+	if s.OnChange != nil {
+		s.OnChange(from, next)
+	}
+}
+
+func (o *UtilityOption) displayName() string {
+	if o == nil {
+		return "nothing"
+	}
+	return o.Name
+}
+
+func (s *UtilitySelector) scoreOf(o *UtilityOption) *UtilityScore {
+	for _, score := range s.scores {
+		if score.Option == o {
+			return score
+		}
+	}
+	return nil
+}
+
+// Current returns the chosen option, or nil
+func (s *UtilitySelector) Current() *UtilityOption {
+	return s.current
+}
+
+// Scores returns the last evaluation of each option, in the order of Options
+func (s *UtilitySelector) Scores() []*UtilityScore {
+	return s.scores
+}
+
+// Score returns the last score of the named option (0 if there's none)
+func (s *UtilitySelector) Score(name string) float64 {
+	for _, score := range s.scores {
+		if score.Option.Name == name {
+			return score.Score
+		}
+	}
+	return 0
+}
+
+// String shows the scores, best first, eg.
+//
+//	eat     0.72 (raw 0.80) *
+//	  hunger   in 0.90 out 0.80
+//	sleep   0.30 (raw 0.30)
+//	  fatigue  in 0.30 out 0.30
+func (s *UtilitySelector) String() string {
+	scores := make([]*UtilityScore, len(s.scores))
+	copy(scores, s.scores)
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+	var buf bytes.Buffer
+	for _, score := range scores {
+		fmt.Fprintf(&buf, "%-8s%.2f (raw %.2f)", score.Option.Name, score.Score, score.Raw)
+		if score.Option == s.current {
+			buf.WriteString(" *")
+		}
+		buf.WriteString("\n")
+		for _, c := range score.Considerations {
+			fmt.Fprintf(&buf, "  %-9sin %.2f out %.2f\n", c.Name, c.Input, c.Output)
+		}
+	}
+	return buf.String()
+}
+
+// BindGOAP adds the options' goals to the agent, prioritised so that the
+// agent pursues the chosen option's goal, falling back to the others in
+// order of score while it can't find a plan for it
+func (s *UtilitySelector) BindGOAP(a *GOAPAgent) {
+	for _, o := range s.Options {
+		if o.Goal == nil {
+			continue
+		}
+		o := o
+		o.Goal.Priority = func() float64 {
+			if o == s.current {
+				return math.Inf(1)
+			}
+			if score := s.scoreOf(o); score != nil {
+				return score.Score
+			}
+			return 0
+		}
+		a.AddGoal(o.Goal)
+	}
+}
+
+// BTSelector returns a BTNode Selector choosing the child named as the
+// chosen option (so the node fails if there's none)
+func (s *UtilitySelector) BTSelector() func(self *BTNode) int {
+	return func(self *BTNode) int {
+		if s.current == nil {
+			return -1
+		}
+		for i, child := range self.Children {
+			if child.Name == s.current.Name {
+				return i
+			}
+		}
+		return -1
+	}
+}
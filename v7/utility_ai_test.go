@@ -0,0 +1,169 @@
+package sameriver
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func testingUtilityWorld() (*World, ComponentID) {
+	w := testingGOAPAgentWorld()
+	const (
+		HUNGER = GENERICTAGS_ + 1 + iota
+	)
+	w.RegisterComponents([]any{
+		HUNGER, FLOAT64, "HUNGER",
+	})
+	return w, HUNGER
+}
+
+func TestUtilitySelector(t *testing.T) {
+	w, HUNGER := testingUtilityWorld()
+	e := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{0, 0},
+			BOX_:      Vec2D{1, 1},
+			HUNGER:    0.2,
+			STATE_:    map[string]int{"fatigue": 50},
+		},
+	})
+	wolves, err := UtilityEFDSLInput(w, "HasTag(wolf)", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hunger, err := NewUtilityConsideration("hunger", UtilityComponentInput(w, HUNGER, 0, 1), "Lin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eat := &UtilityOption{Name: "eat", Considerations: []*UtilityConsideration{hunger}}
+	sleep := &UtilityOption{Name: "sleep", Considerations: []*UtilityConsideration{
+		{Name: "fatigue", Input: UtilityStateInput(w, "fatigue", 0, 100)},
+		{Name: "safe", Input: wolves, Curve: Curves.Decay(1)},
+	}}
+	flee := &UtilityOption{Name: "flee", Weight: 2, Considerations: []*UtilityConsideration{
+		{Name: "wolves", Input: wolves, Curve: Curves.Steps(2)},
+	}}
+	s := NewUtilitySelector(e, w, eat, sleep, flee)
+	s.Hysteresis = 0.1
+	changes := 0
+	s.OnChange = func(from, to *UtilityOption) { changes++ }
+
+	s.Update(FRAME_MS)
+	if s.Current() != sleep || s.Score("flee") != 0 {
+		t.Fatalf("should sleep, scores:\n%s", s)
+	}
+	// two considerations of 0.5 and 1 are compensated up to 0.625
+	if math.Abs(s.Score("sleep")-0.625) > 0.001 {
+		t.Fatalf("sleep should score 0.625, got %f", s.Score("sleep"))
+	}
+	// eat beats sleep but not by the hysteresis
+	*w.GetFloat64(e, HUNGER) = 0.7
+	s.Update(FRAME_MS)
+	if s.Current() != sleep {
+		t.Fatalf("should keep sleeping within hysteresis, scores:\n%s", s)
+	}
+	*w.GetFloat64(e, HUNGER) = 0.8
+	s.Update(FRAME_MS)
+	if s.Current() != eat || changes != 2 {
+		t.Fatalf("should eat once past hysteresis, scores:\n%s", s)
+	}
+	// a wolf makes sleep score 0 and flee win by weight
+	w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{10, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"tags": []string{"wolf"},
+	})
+	s.Update(FRAME_MS)
+	if s.Current() != flee || s.Score("flee") != 2*Curves.Steps(2)(0.5) {
+		t.Fatalf("should flee, scores:\n%s", s)
+	}
+	str := s.String()
+	if !strings.HasPrefix(str, "flee") || !strings.Contains(str, "  wolves   in 0.50") {
+		t.Fatalf("scores should be listed best first with considerations:\n%s", str)
+	}
+}
+
+func TestUtilitySelectorMomentum(t *testing.T) {
+	w, _ := testingUtilityWorld()
+	e := w.Spawn(map[string]any{
+		"components": map[ComponentID]any{
+			POSITION_: Vec2D{0, 0},
+			BOX_:      Vec2D{1, 1},
+		},
+		"mind": map[string]any{"boredom": 0.3},
+	})
+	idle := &UtilityOption{Name: "idle", Considerations: []*UtilityConsideration{
+		{Name: "constant", Input: func(e *Entity) float64 { return 0.5 }},
+	}}
+	play := &UtilityOption{Name: "play", Considerations: []*UtilityConsideration{
+		{Name: "boredom", Input: UtilityMindInput("boredom", 0, 1)},
+	}}
+	s := NewUtilitySelector(e, w, idle, play)
+	s.Momentum_ms = 1000
+	s.Update(FRAME_MS)
+	if s.Current() != idle {
+		t.Fatalf("should idle, scores:\n%s", s)
+	}
+	// a brief spike isn't enough
+	e.Mind.Set("boredom", 1.0)
+	s.Update(FRAME_MS)
+	e.Mind.Set("boredom", 0.3)
+	s.Update(FRAME_MS)
+	if s.Current() != idle {
+		t.Fatalf("should still idle after a spike, scores:\n%s", s)
+	}
+	e.Mind.Set("boredom", 1.0)
+	for i := 0; i < 100 && s.Current() == idle; i++ {
+		s.Update(FRAME_MS)
+	}
+	if s.Current() != play || s.Scores()[1].Raw != 1 || s.Score("play") >= 1 {
+		t.Fatalf("should play once the score has caught up, scores:\n%s", s)
+	}
+}
+
+func TestUtilitySelectorHandover(t *testing.T) {
+	w, _ := testingUtilityWorld()
+	wc := testingSpawnWoodcutter(w, false)
+	wc.a.goals = nil
+	work := &UtilityOption{
+		Name: "work",
+		Considerations: []*UtilityConsideration{
+			{Name: "energy", Input: UtilityMindInput("energy", 0, 10)},
+		},
+		Goal: &GOAPAgentGoal{Name: "wood", Spec: map[string]int{"woodChopped,>=": 2}},
+	}
+	rest := &UtilityOption{
+		Name: "rest",
+		Considerations: []*UtilityConsideration{
+			{Name: "tired", Input: UtilityMindInput("energy", 10, 0)},
+		},
+		Goal: &GOAPAgentGoal{Name: "rest", Spec: map[string]int{"rested,=": 1}},
+	}
+	wc.e.Mind.Set("energy", 8)
+	s := NewUtilitySelector(wc.e, w, work, rest)
+	s.BindGOAP(wc.a)
+	s.Update(FRAME_MS)
+	if !math.IsInf(work.Goal.Priority(), 1) || math.Abs(rest.Goal.Priority()-0.2) > 0.001 {
+		t.Fatalf("the chosen option's goal should come first, scores:\n%s", s)
+	}
+	wc.run(1000)
+	if wc.chopped != 2 {
+		t.Fatal("agent should pursue the chosen goal")
+	}
+
+	bt := NewBehaviourTree("villager", &BTNode{
+		Name:     "Utility",
+		Selector: s.BTSelector(),
+		Children: []*BTNode{{Name: "rest"}, {Name: "work"}},
+	})
+	if state := NewBTRunner().ExecuteBT(wc.e, bt); state.Path != "Utility.work" {
+		t.Fatalf("BT should run the chosen option, got %s", state.Path)
+	}
+	wc.e.Mind.Set("energy", 1)
+	s.Update(FRAME_MS)
+	if state := NewBTRunner().ExecuteBT(wc.e, bt); state.Path != "Utility.rest" {
+		t.Fatalf("BT should follow the choice, got %s", state.Path)
+	}
+}
@@ -178,6 +178,11 @@ func (w *World) Update(allowance_ms float64) (overunder_ms float64) {
 	// process entity manager and spatial hash before anything
 	w.Em.Update(allowance_ms / 8)
 	w.SpatialHasher.Update()
+	// then the deferred event handlers, with the events since last update
+	w.Events.Dispatch()
+	for _, bb := range w.Blackboards {
+		bb.Events.Dispatch()
+	}
 	remaining_ms := allowance_ms - float64(time.Since(t0).Nanoseconds())/1e6
 	w.runtimeSharer.Share(remaining_ms)
 